@@ -1,32 +0,0 @@
-// Copyright 2020 ChainSafe Systems
-// SPDX-License-Identifier: LGPL-3.0-only
-
-package acala
-
-import (
-	"strconv"
-
-	"github.com/ChainSafe/chainbridge-utils/core"
-)
-
-func parseStartBlock(cfg *core.ChainConfig) uint64 {
-	if blk, ok := cfg.Opts["startBlock"]; ok {
-		res, err := strconv.ParseUint(blk, 10, 32)
-		if err != nil {
-			panic(err)
-		}
-		return res
-	}
-	return 0
-}
-
-func parseUseExtended(cfg *core.ChainConfig) bool {
-	if b, ok := cfg.Opts["useExtendedCall"]; ok {
-		res, err := strconv.ParseBool(b)
-		if err != nil {
-			panic(err)
-		}
-		return res
-	}
-	return false
-}
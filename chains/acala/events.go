@@ -1,43 +0,0 @@
-// Copyright 2020 ChainSafe Systems
-// SPDX-License-Identifier: LGPL-3.0-only
-
-package acala
-
-import (
-	"fmt"
-
-	events "github.com/ChainSafe/chainbridge-substrate-events"
-	"github.com/ChainSafe/chainbridge-utils/msg"
-	"github.com/ChainSafe/log15"
-)
-
-type eventName string
-type eventHandler func(interface{}, log15.Logger) (msg.Message, error)
-
-const FungibleTransfer eventName = "FungibleTransfer"
-
-var Subscriptions = []struct {
-	name    eventName
-	handler eventHandler
-}{
-	{FungibleTransfer, fungibleTransferHandler},
-}
-
-func fungibleTransferHandler(evtI interface{}, log log15.Logger) (msg.Message, error) {
-	evt, ok := evtI.(events.EventFungibleTransfer)
-	if !ok {
-		return msg.Message{}, fmt.Errorf("failed to cast EventFungibleTransfer type")
-	}
-
-	resourceId := msg.ResourceId(evt.ResourceId)
-	log.Info("Got fungible transfer event!", "destination", evt.Destination, "resourceId", resourceId.Hex(), "amount", evt.Amount)
-
-	return msg.NewFungibleTransfer(
-		0, // Unset
-		msg.ChainId(evt.Destination),
-		msg.Nonce(evt.DepositNonce),
-		evt.Amount.Int,
-		resourceId,
-		evt.Recipient,
-	), nil
-}
@@ -0,0 +1,109 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+const (
+	RejectDust = "reject" // Transfers that would lose precision are not executed
+	RoundDust  = "round"  // Amounts are rounded down, the dust stays locked on the source chain
+)
+
+// Decimals are the number of decimals a resource uses on the source chain and on the destination chain
+type Decimals struct {
+	Source      uint8
+	Destination uint8
+}
+
+// Scale converts an amount of the source chain to the precision of the destination chain. The part of the amount
+// that cannot be represented on the destination chain is returned as dust, in units of the source chain.
+func (d Decimals) Scale(amount *big.Int) (*big.Int, *big.Int) {
+	switch {
+	case d.Destination > d.Source:
+		factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.Destination-d.Source)), nil)
+		return new(big.Int).Mul(amount, factor), big.NewInt(0)
+	case d.Destination < d.Source:
+		factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.Source-d.Destination)), nil)
+		return new(big.Int).QuoRem(amount, factor, new(big.Int))
+	default:
+		return new(big.Int).Set(amount), big.NewInt(0)
+	}
+}
+
+// ResourceDecimals maps resource IDs to the decimals of their tokens. Resources without an entry are not scaled.
+type ResourceDecimals map[msg.ResourceId]Decimals
+
+// DustError is returned if an amount cannot be scaled without losing precision and dust is rejected
+type DustError struct {
+	Amount *big.Int
+	Dust   *big.Int
+}
+
+func (e *DustError) Error() string {
+	return fmt.Sprintf("amount %s leaves dust %s when scaled to the destination decimals", e.Amount, e.Dust)
+}
+
+// ScaleAmount converts the amount of a fungible transfer of the resource to the destination decimals. Dust is
+// rejected with a DustError or dropped and returned for logging, according to the policy.
+func (r ResourceDecimals) ScaleAmount(rId msg.ResourceId, amount *big.Int, policy string) (*big.Int, *big.Int, error) {
+	d, ok := r[rId]
+	if !ok {
+		return amount, big.NewInt(0), nil
+	}
+	scaled, dust := d.Scale(amount)
+	if dust.Sign() != 0 && policy != RoundDust {
+		return nil, nil, &DustError{Amount: amount, Dust: dust}
+	}
+	return scaled, dust, nil
+}
+
+// ParseResourceDecimals parses a comma separated list of resourceId:sourceDecimals:destinationDecimals entries,
+// eg. "0x00...01:18:12"
+func ParseResourceDecimals(opt string) (ResourceDecimals, error) {
+	res := make(ResourceDecimals)
+	for _, entry := range strings.Split(opt, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unable to parse decimals entry %q, expected resourceId:sourceDecimals:destinationDecimals", entry)
+		}
+		rId, err := hex.DecodeString(strings.TrimPrefix(parts[0], "0x"))
+		if err != nil || len(rId) != 32 {
+			return nil, fmt.Errorf("invalid resource ID %s", parts[0])
+		}
+		src, err := strconv.ParseUint(parts[1], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source decimals %s: %w", parts[1], err)
+		}
+		dst, err := strconv.ParseUint(parts[2], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination decimals %s: %w", parts[2], err)
+		}
+		res[msg.ResourceIdFromSlice(rId)] = Decimals{Source: uint8(src), Destination: uint8(dst)}
+	}
+	return res, nil
+}
+
+// ParseDustPolicy validates the dust policy, the default is to reject transfers leaving dust
+func ParseDustPolicy(opt string) (string, error) {
+	switch opt {
+	case "":
+		return RejectDust, nil
+	case RejectDust, RoundDust:
+		return opt, nil
+	default:
+		return "", fmt.Errorf("unknown dust policy %s, must be %s or %s", opt, RejectDust, RoundDust)
+	}
+}
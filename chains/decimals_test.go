@@ -0,0 +1,96 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+func TestDecimalsScale(t *testing.T) {
+	testCases := []struct {
+		decimals Decimals
+		amount   int64
+		scaled   int64
+		dust     int64
+	}{
+		{Decimals{Source: 18, Destination: 18}, 123456, 123456, 0},
+		{Decimals{Source: 12, Destination: 18}, 123456, 123456000000, 0},
+		{Decimals{Source: 18, Destination: 12}, 123456000000, 123456, 0},
+		{Decimals{Source: 18, Destination: 12}, 123456000001, 123456, 1},
+		{Decimals{Source: 18, Destination: 12}, 999999, 0, 999999},
+	}
+
+	for _, tc := range testCases {
+		scaled, dust := tc.decimals.Scale(big.NewInt(tc.amount))
+		if scaled.Int64() != tc.scaled || dust.Int64() != tc.dust {
+			t.Errorf("Scaling %d with %v. Got: %s, %s Expected: %d, %d", tc.amount, tc.decimals, scaled, dust, tc.scaled, tc.dust)
+		}
+	}
+}
+
+func TestScaleAmount(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{0x01})
+	decimals := ResourceDecimals{rId: {Source: 18, Destination: 12}}
+
+	scaled, _, err := decimals.ScaleAmount(msg.ResourceId{}, big.NewInt(1), RejectDust)
+	if err != nil || scaled.Int64() != 1 {
+		t.Fatalf("Expected unconfigured resource to be passed through. Got: %s, %v", scaled, err)
+	}
+
+	var dustErr *DustError
+	_, _, err = decimals.ScaleAmount(rId, big.NewInt(1000001), RejectDust)
+	if !errors.As(err, &dustErr) || dustErr.Dust.Int64() != 1 {
+		t.Fatalf("Expected DustError with dust 1, got %v", err)
+	}
+
+	scaled, dust, err := decimals.ScaleAmount(rId, big.NewInt(1000001), RoundDust)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scaled.Int64() != 1 || dust.Int64() != 1 {
+		t.Fatalf("Got: %s, %s Expected: 1, 1", scaled, dust)
+	}
+}
+
+func TestParseResourceDecimals(t *testing.T) {
+	rId := "0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00"
+	decimals, err := ParseResourceDecimals(rId + ":18:12, 0000000000000000000000000000000000000000000000000000000000000001:12:18")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decimals) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(decimals))
+	}
+	first := msg.ResourceIdFromSlice([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xc7, 0x6e, 0xbe, 0x4a, 0x02, 0xbb, 0xc3, 0x47, 0x86, 0xd8, 0x60, 0xb3, 0x55, 0xf5, 0xa5, 0xce, 0x00})
+	if d := decimals[first]; d != (Decimals{Source: 18, Destination: 12}) {
+		t.Fatalf("Unexpected decimals %v", d)
+	}
+	second := msg.ResourceIdFromSlice(append(make([]byte, 31), 0x01))
+	if d := decimals[second]; d != (Decimals{Source: 12, Destination: 18}) {
+		t.Fatalf("Unexpected decimals %v", d)
+	}
+
+	for _, val := range []string{rId + ":18", "0x01:18:12", rId + ":x:12", rId + ":18:256"} {
+		_, err := ParseResourceDecimals(val)
+		if err == nil {
+			t.Errorf("Decimals option should not accept %s", val)
+		}
+	}
+}
+
+func TestParseDustPolicy(t *testing.T) {
+	for val, expected := range map[string]string{"": RejectDust, "reject": RejectDust, "round": RoundDust} {
+		policy, err := ParseDustPolicy(val)
+		if err != nil || policy != expected {
+			t.Errorf("Got: %s, %v Expected: %s", policy, err, expected)
+		}
+	}
+	if _, err := ParseDustPolicy("ignore"); err == nil {
+		t.Error("Dust policy should not accept ignore")
+	}
+}
@@ -17,10 +17,13 @@ The listener polls for each new block and looks for deposit events in the bridge
 Writer
 
 The writer recieves the message and creates a proposals on-chain. Once a proposal is made, the writer then watches for a finalization event and will attempt to execute the proposal if a matching event occurs. The writer skips over any proposals it has already seen.
+
+The state of each message is recorded in an outbox, so proposals that were not executed before a restart are resumed when the writer starts.
 */
 package ethereum
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 
@@ -29,6 +32,7 @@ import (
 	erc721Handler "github.com/ChainSafe/ChainBridge/bindings/ERC721Handler"
 	"github.com/ChainSafe/ChainBridge/bindings/GenericHandler"
 	connection "github.com/ChainSafe/ChainBridge/connections/ethereum"
+	"github.com/ChainSafe/ChainBridge/outbox"
 	"github.com/ChainSafe/chainbridge-utils/blockstore"
 	"github.com/ChainSafe/chainbridge-utils/core"
 	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
@@ -36,8 +40,10 @@ import (
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/chainbridge-utils/msg"
 	"github.com/ChainSafe/log15"
+	eth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -53,6 +59,7 @@ type Connection interface {
 	LockAndUpdateOpts() error
 	UnlockOpts()
 	Client() *ethclient.Client
+	FilterLogs(ctx context.Context, query eth.FilterQuery) ([]ethtypes.Log, error)
 	EnsureHasBytecode(address common.Address) error
 	LatestBlock() (*big.Int, error)
 	WaitForBlock(block *big.Int, delay *big.Int) error
@@ -65,6 +72,7 @@ type Chain struct {
 	listener *listener         // The listener of this chain
 	writer   *writer           // The writer of the chain
 	stop     chan<- int
+	relayed  *outbox.Watcher // Tracks messages passed to the writer by Relay, nil until Relay is called
 }
 
 // checkBlockstore queries the blockstore for the latest known block. If the latest block is
@@ -106,9 +114,38 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 		return nil, err
 	}
 
+	ob, err := outbox.NewOutbox(cfg.blockstorePath, cfg.id, kp.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	return newChain(chainCfg, cfg, kp, bs, ob, logger, sysErr, m)
+}
+
+// InspectChain connects to the chain to look up its deposits and proposals. A generated key is used, so no keystore
+// is required, and neither the blockstore nor the outbox are loaded. The returned chain must not be started.
+func InspectChain(chainCfg *core.ChainConfig, logger log15.Logger) (*Chain, error) {
+	cfg, err := parseChainConfig(chainCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	kp, err := secp256k1.GenerateKeypair()
+	if err != nil {
+		return nil, err
+	}
+
+	return newChain(chainCfg, cfg, kp, &blockstore.EmptyStore{}, &outbox.EmptyOutbox{}, logger, nil, nil)
+}
+
+// newChain connects to the chain and sets up the listener and writer
+func newChain(chainCfg *core.ChainConfig, cfg *Config, kp *secp256k1.Keypair, bs blockstore.Blockstorer, ob outbox.Outboxer, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics) (*Chain, error) {
 	stop := make(chan int)
-	conn := connection.NewConnection(cfg.endpoint, cfg.http, kp, logger, cfg.gasLimit, cfg.maxGasPrice, cfg.gasMultiplier)
-	err = conn.Connect()
+	conn := connection.NewConnection(cfg.endpoints(), cfg.http, kp, logger, cfg.gasLimit, cfg.maxGasPrice, cfg.gasMultiplier)
+	if cfg.eip1559 {
+		conn.EnableDynamicFees(cfg.maxPriorityFeePerGas, cfg.priorityFeePercentile)
+	}
+	err := conn.Connect()
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +162,7 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 		return nil, err
 	}
 
-	bridgeContract, err := bridge.NewBridge(cfg.bridgeContract, conn.Client())
+	bridgeContract, err := bridge.NewBridge(cfg.bridgeContract, conn.Backend())
 	if err != nil {
 		return nil, err
 	}
@@ -139,17 +176,17 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 		return nil, fmt.Errorf("chainId (%d) and configuration chainId (%d) do not match", chainId, chainCfg.Id)
 	}
 
-	erc20HandlerContract, err := erc20Handler.NewERC20Handler(cfg.erc20HandlerContract, conn.Client())
+	erc20HandlerContract, err := erc20Handler.NewERC20Handler(cfg.erc20HandlerContract, conn.Backend())
 	if err != nil {
 		return nil, err
 	}
 
-	erc721HandlerContract, err := erc721Handler.NewERC721Handler(cfg.erc721HandlerContract, conn.Client())
+	erc721HandlerContract, err := erc721Handler.NewERC721Handler(cfg.erc721HandlerContract, conn.Backend())
 	if err != nil {
 		return nil, err
 	}
 
-	genericHandlerContract, err := GenericHandler.NewGenericHandler(cfg.genericHandlerContract, conn.Client())
+	genericHandlerContract, err := GenericHandler.NewGenericHandler(cfg.genericHandlerContract, conn.Backend())
 	if err != nil {
 		return nil, err
 	}
@@ -164,9 +201,27 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 
 	listener := NewListener(conn, cfg, logger, bs, stop, sysErr, m)
 	listener.setContracts(bridgeContract, erc20HandlerContract, erc721HandlerContract, genericHandlerContract)
+	for _, h := range cfg.handlers {
+		err = conn.EnsureHasBytecode(h.address)
+		if err != nil {
+			return nil, err
+		}
+		handler, err := newDepositHandler(h.kind, h.address, conn.Backend())
+		if err != nil {
+			return nil, err
+		}
+		listener.registerHandler(h.address, handler)
+	}
 
 	writer := NewWriter(conn, cfg, logger, stop, sysErr, m)
 	writer.setContract(bridgeContract)
+	writer.setOutbox(ob)
+
+	if m != nil {
+		em := newEthMetrics(chainCfg.Name)
+		listener.setEthMetrics(em)
+		writer.setEthMetrics(em)
+	}
 
 	return &Chain{
 		cfg:      chainCfg,
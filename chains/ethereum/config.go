@@ -7,7 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
 
+	"github.com/ChainSafe/ChainBridge/chains"
 	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
 	"github.com/ChainSafe/chainbridge-utils/core"
 	"github.com/ChainSafe/chainbridge-utils/msg"
@@ -18,6 +21,9 @@ const DefaultGasLimit = 6721975
 const DefaultGasPrice = 20000000000
 const DefaultBlockConfirmations = 10
 const DefaultGasMultiplier = 1
+const DefaultMaxBlockRange = 100
+const DefaultMaxPriorityFeePerGas = 2000000000
+const DefaultPriorityFeePercentile = 50
 
 // Chain specific options
 var (
@@ -31,13 +37,29 @@ var (
 	HttpOpt               = "http"
 	StartBlockOpt         = "startBlock"
 	BlockConfirmationsOpt = "blockConfirmations"
+	MaxBlockRangeOpt      = "maxBlockRange"
+	FallbackEndpointsOpt  = "fallbackEndpoints"
+	EIP1559Opt            = "eip1559"
+	MaxPriorityFeeOpt     = "maxPriorityFeePerGas"
+	PriorityFeePctOpt     = "priorityFeePercentile"
+	HandlersOpt           = "handlers"
+	SimulationPolicyOpt   = "simulationPolicy"
+	DecimalsOpt           = "decimals"
+	DustPolicyOpt         = "dustPolicy"
 )
 
+// handlerConfig is an additional deposit handler contract and the kind of decoder used for it
+type handlerConfig struct {
+	kind    string
+	address common.Address
+}
+
 // Config encapsulates all necessary parameters in ethereum compatible forms
 type Config struct {
 	name                   string      // Human-readable chain name
 	id                     msg.ChainId // ChainID
 	endpoint               string      // url for rpc endpoint
+	fallbackEndpoints      []string    // urls used if the primary endpoint is unhealthy
 	from                   string      // address of key to use
 	keystorePath           string      // Location of keyfiles
 	blockstorePath         string
@@ -46,12 +68,20 @@ type Config struct {
 	erc20HandlerContract   common.Address
 	erc721HandlerContract  common.Address
 	genericHandlerContract common.Address
+	handlers               []handlerConfig // Additional deposit handlers
 	gasLimit               *big.Int
 	maxGasPrice            *big.Int
 	gasMultiplier          *big.Float
 	http                   bool // Config for type of connection
 	startBlock             *big.Int
 	blockConfirmations     *big.Int
+	maxBlockRange          *big.Int // Maximum number of blocks queried for deposit logs at once
+	eip1559                bool     // Send EIP-1559 transactions when the chain reports a base fee
+	maxPriorityFeePerGas   *big.Int
+	priorityFeePercentile  float64                 // Percentile of recent priority fees to pay
+	simulationPolicy       string                  // Action taken if the simulated execution of a proposal fails
+	decimals               chains.ResourceDecimals // Decimals used to scale the amounts of fungible transfers
+	dustPolicy             string                  // Handling of amounts that lose precision when scaled
 }
 
 // parseChainConfig uses a core.ChainConfig to construct a corresponding Config
@@ -75,6 +105,12 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		http:                   false,
 		startBlock:             big.NewInt(0),
 		blockConfirmations:     big.NewInt(0),
+		maxBlockRange:          big.NewInt(DefaultMaxBlockRange),
+		eip1559:                false,
+		maxPriorityFeePerGas:   big.NewInt(DefaultMaxPriorityFeePerGas),
+		priorityFeePercentile:  DefaultPriorityFeePercentile,
+		simulationPolicy:       SimulationDisabled,
+		dustPolicy:             chains.RejectDust,
 	}
 
 	if contract, ok := chainCfg.Opts[BridgeOpt]; ok && contract != "" {
@@ -159,9 +195,118 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		delete(chainCfg.Opts, BlockConfirmationsOpt)
 	}
 
+	if maxBlockRange, ok := chainCfg.Opts[MaxBlockRangeOpt]; ok && maxBlockRange != "" {
+		val := big.NewInt(DefaultMaxBlockRange)
+		_, pass := val.SetString(maxBlockRange, 10)
+		if pass && val.Sign() == 1 {
+			config.maxBlockRange = val
+			delete(chainCfg.Opts, MaxBlockRangeOpt)
+		} else {
+			return nil, fmt.Errorf("unable to parse %s", MaxBlockRangeOpt)
+		}
+	}
+
+	if eip1559, ok := chainCfg.Opts[EIP1559Opt]; ok && eip1559 == "true" {
+		config.eip1559 = true
+		delete(chainCfg.Opts, EIP1559Opt)
+	} else if eip1559, ok := chainCfg.Opts[EIP1559Opt]; ok && eip1559 == "false" {
+		config.eip1559 = false
+		delete(chainCfg.Opts, EIP1559Opt)
+	}
+
+	if maxPriorityFee, ok := chainCfg.Opts[MaxPriorityFeeOpt]; ok {
+		fee := big.NewInt(0)
+		_, pass := fee.SetString(maxPriorityFee, 10)
+		if pass && fee.Sign() != -1 {
+			config.maxPriorityFeePerGas = fee
+			delete(chainCfg.Opts, MaxPriorityFeeOpt)
+		} else {
+			return nil, fmt.Errorf("unable to parse %s", MaxPriorityFeeOpt)
+		}
+	}
+
+	if percentile, ok := chainCfg.Opts[PriorityFeePctOpt]; ok {
+		val, err := strconv.ParseFloat(percentile, 64)
+		if err == nil && val >= 0 && val <= 100 {
+			config.priorityFeePercentile = val
+			delete(chainCfg.Opts, PriorityFeePctOpt)
+		} else {
+			return nil, fmt.Errorf("unable to parse %s", PriorityFeePctOpt)
+		}
+	}
+
+	if policy, ok := chainCfg.Opts[SimulationPolicyOpt]; ok {
+		if policy != SimulationDisabled && policy != SimulationSkip && policy != SimulationHold {
+			return nil, fmt.Errorf("unable to parse %s, must be one of %s, %s or %s", SimulationPolicyOpt, SimulationHold, SimulationSkip, SimulationDisabled)
+		}
+		config.simulationPolicy = policy
+		delete(chainCfg.Opts, SimulationPolicyOpt)
+	}
+
+	if decimals, ok := chainCfg.Opts[DecimalsOpt]; ok {
+		parsed, err := chains.ParseResourceDecimals(decimals)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", DecimalsOpt, err)
+		}
+		config.decimals = parsed
+		delete(chainCfg.Opts, DecimalsOpt)
+	}
+
+	if policy, ok := chainCfg.Opts[DustPolicyOpt]; ok {
+		parsed, err := chains.ParseDustPolicy(policy)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", DustPolicyOpt, err)
+		}
+		config.dustPolicy = parsed
+		delete(chainCfg.Opts, DustPolicyOpt)
+	}
+
+	if handlers, ok := chainCfg.Opts[HandlersOpt]; ok {
+		parsed, err := parseHandlers(handlers)
+		if err != nil {
+			return nil, err
+		}
+		config.handlers = parsed
+		delete(chainCfg.Opts, HandlersOpt)
+	}
+
+	if fallbackEndpoints, ok := chainCfg.Opts[FallbackEndpointsOpt]; ok {
+		for _, url := range strings.Split(fallbackEndpoints, ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				config.fallbackEndpoints = append(config.fallbackEndpoints, url)
+			}
+		}
+		delete(chainCfg.Opts, FallbackEndpointsOpt)
+	}
+
 	if len(chainCfg.Opts) != 0 {
 		return nil, fmt.Errorf("unknown Opts Encountered: %#v", chainCfg.Opts)
 	}
 
 	return config, nil
 }
+
+// parseHandlers parses a comma separated list of kind:address pairs, eg. "erc20:0x1234,generic:0x5678"
+func parseHandlers(opt string) ([]handlerConfig, error) {
+	var handlers []handlerConfig
+	for _, entry := range strings.Split(opt, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 || !common.IsHexAddress(parts[1]) {
+			return nil, fmt.Errorf("unable to parse %s entry %q, expected kind:address", HandlersOpt, entry)
+		}
+		if _, ok := depositHandlerKinds[parts[0]]; !ok {
+			return nil, fmt.Errorf("unknown handler kind %s", parts[0])
+		}
+		handlers = append(handlers, handlerConfig{kind: parts[0], address: common.HexToAddress(parts[1])})
+	}
+	return handlers, nil
+}
+
+// endpoints returns the primary endpoint followed by any fallback endpoints
+func (c *Config) endpoints() []string {
+	return append([]string{c.endpoint}, c.fallbackEndpoints...)
+}
@@ -8,7 +8,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/ChainSafe/ChainBridge/chains"
 	"github.com/ChainSafe/chainbridge-utils/core"
+	"github.com/ChainSafe/chainbridge-utils/msg"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -23,16 +25,24 @@ func TestParseChainConfig(t *testing.T) {
 		KeystorePath: "./keys",
 		Insecure:     false,
 		Opts: map[string]string{
-			"bridge":             "0x1234",
-			"erc20Handler":       "0x1234",
-			"erc721Handler":      "0x1234",
-			"genericHandler":     "0x1234",
-			"gasLimit":           "10",
-			"gasMultiplier":      "1",
-			"maxGasPrice":        "20",
-			"http":               "true",
-			"startBlock":         "10",
-			"blockConfirmations": "50",
+			"bridge":                "0x1234",
+			"erc20Handler":          "0x1234",
+			"erc721Handler":         "0x1234",
+			"genericHandler":        "0x1234",
+			"gasLimit":              "10",
+			"gasMultiplier":         "1",
+			"maxGasPrice":           "20",
+			"http":                  "true",
+			"startBlock":            "10",
+			"blockConfirmations":    "50",
+			"maxBlockRange":         "500",
+			"fallbackEndpoints":     "endpoint2, endpoint3",
+			"eip1559":               "true",
+			"maxPriorityFeePerGas":  "3",
+			"priorityFeePercentile": "25",
+			"simulationPolicy":      "skip",
+			"decimals":              "0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00:18:12",
+			"dustPolicy":            "round",
 		},
 	}
 
@@ -46,6 +56,7 @@ func TestParseChainConfig(t *testing.T) {
 		name:                   "chain",
 		id:                     1,
 		endpoint:               "endpoint",
+		fallbackEndpoints:      []string{"endpoint2", "endpoint3"},
 		from:                   "0x0",
 		keystorePath:           "./keys",
 		bridgeContract:         common.HexToAddress("0x1234"),
@@ -58,11 +69,25 @@ func TestParseChainConfig(t *testing.T) {
 		http:                   true,
 		startBlock:             big.NewInt(10),
 		blockConfirmations:     big.NewInt(50),
+		maxBlockRange:          big.NewInt(500),
+		eip1559:                true,
+		maxPriorityFeePerGas:   big.NewInt(3),
+		priorityFeePercentile:  25,
+		simulationPolicy:       SimulationSkip,
+		decimals: chains.ResourceDecimals{
+			msg.ResourceIdFromSlice(common.FromHex("0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00")): {Source: 18, Destination: 12},
+		},
+		dustPolicy: chains.RoundDust,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
 		t.Fatalf("Output not expected.\n\tExpected: %#v\n\tGot: %#v\n", &expected, out)
 	}
+
+	endpoints := []string{"endpoint", "endpoint2", "endpoint3"}
+	if !reflect.DeepEqual(out.endpoints(), endpoints) {
+		t.Fatalf("Unexpected endpoints. Expected: %v Got: %v", endpoints, out.endpoints())
+	}
 }
 
 //TestParseChainConfig tests parseChainConfig with all handlerContracts provided
@@ -110,6 +135,11 @@ func TestParseChainConfigWithNoBlockConfirmations(t *testing.T) {
 		http:                   true,
 		startBlock:             big.NewInt(10),
 		blockConfirmations:     big.NewInt(DefaultBlockConfirmations),
+		maxBlockRange:          big.NewInt(DefaultMaxBlockRange),
+		maxPriorityFeePerGas:   big.NewInt(DefaultMaxPriorityFeePerGas),
+		priorityFeePercentile:  DefaultPriorityFeePercentile,
+		simulationPolicy:       SimulationDisabled,
+		dustPolicy:             chains.RejectDust,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
@@ -145,19 +175,24 @@ func TestChainConfigOneContract(t *testing.T) {
 	}
 
 	expected := Config{
-		name:                 "chain",
-		id:                   1,
-		endpoint:             "endpoint",
-		from:                 "0x0",
-		keystorePath:         "./keys",
-		bridgeContract:       common.HexToAddress("0x1234"),
-		erc20HandlerContract: common.HexToAddress("0x1234"),
-		gasLimit:             big.NewInt(10),
-		maxGasPrice:          big.NewInt(20),
-		gasMultiplier:        big.NewFloat(1),
-		http:                 true,
-		startBlock:           big.NewInt(10),
-		blockConfirmations:   big.NewInt(DefaultBlockConfirmations),
+		name:                  "chain",
+		id:                    1,
+		endpoint:              "endpoint",
+		from:                  "0x0",
+		keystorePath:          "./keys",
+		bridgeContract:        common.HexToAddress("0x1234"),
+		erc20HandlerContract:  common.HexToAddress("0x1234"),
+		gasLimit:              big.NewInt(10),
+		maxGasPrice:           big.NewInt(20),
+		gasMultiplier:         big.NewFloat(1),
+		http:                  true,
+		startBlock:            big.NewInt(10),
+		blockConfirmations:    big.NewInt(DefaultBlockConfirmations),
+		maxBlockRange:         big.NewInt(DefaultMaxBlockRange),
+		maxPriorityFeePerGas:  big.NewInt(DefaultMaxPriorityFeePerGas),
+		priorityFeePercentile: DefaultPriorityFeePercentile,
+		simulationPolicy:      SimulationDisabled,
+		dustPolicy:            chains.RejectDust,
 	}
 
 	if !reflect.DeepEqual(&expected, out) {
@@ -224,3 +259,68 @@ func TestExtraOpts(t *testing.T) {
 		t.Error("Config should not accept incorrect opts.")
 	}
 }
+
+func TestInvalidMaxBlockRange(t *testing.T) {
+	for _, val := range []string{"0", "-1", "ten"} {
+		input := core.ChainConfig{
+			Name:         "chain",
+			Id:           1,
+			Endpoint:     "endpoint",
+			From:         "0x0",
+			KeystorePath: "./keys",
+			Opts: map[string]string{
+				"bridge":        "0x1234",
+				"maxBlockRange": val,
+			},
+		}
+
+		_, err := parseChainConfig(&input)
+		if err == nil {
+			t.Errorf("Config should not accept maxBlockRange %s", val)
+		}
+	}
+}
+
+func TestInvalidPriorityFeePercentile(t *testing.T) {
+	for _, val := range []string{"-1", "101", "half"} {
+		input := core.ChainConfig{
+			Name:         "chain",
+			Id:           1,
+			Endpoint:     "endpoint",
+			From:         "0x0",
+			KeystorePath: "./keys",
+			Opts: map[string]string{
+				"bridge":                "0x1234",
+				"priorityFeePercentile": val,
+			},
+		}
+
+		_, err := parseChainConfig(&input)
+		if err == nil {
+			t.Errorf("Config should not accept priorityFeePercentile %s", val)
+		}
+	}
+}
+
+func TestParseHandlers(t *testing.T) {
+	handlers, err := parseHandlers("erc20:0x1111111111111111111111111111111111111111, erc20:0x2222222222222222222222222222222222222222,generic:0x3333333333333333333333333333333333333333")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []handlerConfig{
+		{kind: Erc20HandlerKind, address: common.HexToAddress("0x1111111111111111111111111111111111111111")},
+		{kind: Erc20HandlerKind, address: common.HexToAddress("0x2222222222222222222222222222222222222222")},
+		{kind: GenericHandlerKind, address: common.HexToAddress("0x3333333333333333333333333333333333333333")},
+	}
+	if !reflect.DeepEqual(handlers, expected) {
+		t.Fatalf("Unexpected handlers.\n\tExpected: %#v\n\tGot: %#v\n", expected, handlers)
+	}
+
+	for _, val := range []string{"erc20", "erc20:notanaddress", "unknown:0x1111111111111111111111111111111111111111", "erc20:0x1111111111111111111111111111111111111111:0x2222222222222222222222222222222222222222"} {
+		_, err := parseHandlers(val)
+		if err == nil {
+			t.Errorf("Handlers option should not accept %s", val)
+		}
+	}
+}
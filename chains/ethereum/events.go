@@ -4,22 +4,86 @@
 package ethereum
 
 import (
+	"fmt"
+
+	"github.com/ChainSafe/ChainBridge/bindings/ERC20Handler"
+	"github.com/ChainSafe/ChainBridge/bindings/ERC721Handler"
+	"github.com/ChainSafe/ChainBridge/bindings/GenericHandler"
 	"github.com/ChainSafe/chainbridge-utils/msg"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Built-in deposit handler kinds
+const (
+	Erc20HandlerKind   = "erc20"
+	Erc721HandlerKind  = "erc721"
+	GenericHandlerKind = "generic"
 )
 
-func (l *listener) handleErc20DepositedEvent(destId msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
-	l.log.Info("Handling fungible deposit event", "dest", destId, "nonce", nonce)
+// DepositHandler decodes the deposit record stored by a handler contract into a message
+type DepositHandler interface {
+	HandleDeposit(opts *bind.CallOpts, source, dest msg.ChainId, nonce msg.Nonce) (msg.Message, error)
+}
+
+// DepositHandlerConstructor binds a DepositHandler to the handler contract deployed at addr
+type DepositHandlerConstructor func(addr common.Address, backend bind.ContractBackend) (DepositHandler, error)
 
-	record, err := l.erc20HandlerContract.GetDepositRecord(&bind.CallOpts{From: l.conn.Keypair().CommonAddress()}, uint64(nonce), uint8(destId))
+var depositHandlerKinds = map[string]DepositHandlerConstructor{
+	Erc20HandlerKind: func(addr common.Address, backend bind.ContractBackend) (DepositHandler, error) {
+		contract, err := ERC20Handler.NewERC20Handler(addr, backend)
+		return &erc20DepositHandler{contract}, err
+	},
+	Erc721HandlerKind: func(addr common.Address, backend bind.ContractBackend) (DepositHandler, error) {
+		contract, err := ERC721Handler.NewERC721Handler(addr, backend)
+		return &erc721DepositHandler{contract}, err
+	},
+	GenericHandlerKind: func(addr common.Address, backend bind.ContractBackend) (DepositHandler, error) {
+		contract, err := GenericHandler.NewGenericHandler(addr, backend)
+		return &genericDepositHandler{contract}, err
+	},
+}
+
+// RegisterDepositHandlerKind makes a custom handler kind available to the handlers option.
+// Must be called before the chain is initialized.
+func RegisterDepositHandlerKind(kind string, constructor DepositHandlerConstructor) {
+	depositHandlerKinds[kind] = constructor
+}
+
+// newDepositHandler binds the handler contract at addr using the constructor registered for kind
+func newDepositHandler(kind string, addr common.Address, backend bind.ContractBackend) (DepositHandler, error) {
+	constructor, ok := depositHandlerKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown handler kind %s", kind)
+	}
+	return constructor(addr, backend)
+}
+
+// handlerRegistry maps handler contract addresses to their DepositHandler
+type handlerRegistry map[common.Address]DepositHandler
+
+func (r handlerRegistry) register(addr common.Address, handler DepositHandler) {
+	r[addr] = handler
+}
+
+func (r handlerRegistry) lookup(addr common.Address) (DepositHandler, bool) {
+	handler, ok := r[addr]
+	return handler, ok
+}
+
+type erc20DepositHandler struct {
+	contract *ERC20Handler.ERC20Handler
+}
+
+func (h *erc20DepositHandler) HandleDeposit(opts *bind.CallOpts, source, dest msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
+	record, err := h.contract.GetDepositRecord(opts, uint64(nonce), uint8(dest))
 	if err != nil {
-		l.log.Error("Error Unpacking ERC20 Deposit Record", "err", err)
-		return msg.Message{}, err
+		return msg.Message{}, fmt.Errorf("error unpacking ERC20 deposit record: %w", err)
 	}
 
 	return msg.NewFungibleTransfer(
-		l.cfg.id,
-		destId,
+		source,
+		dest,
 		nonce,
 		record.Amount,
 		record.ResourceID,
@@ -27,18 +91,19 @@ func (l *listener) handleErc20DepositedEvent(destId msg.ChainId, nonce msg.Nonce
 	), nil
 }
 
-func (l *listener) handleErc721DepositedEvent(destId msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
-	l.log.Info("Handling nonfungible deposit event")
+type erc721DepositHandler struct {
+	contract *ERC721Handler.ERC721Handler
+}
 
-	record, err := l.erc721HandlerContract.GetDepositRecord(&bind.CallOpts{From: l.conn.Keypair().CommonAddress()}, uint64(nonce), uint8(destId))
+func (h *erc721DepositHandler) HandleDeposit(opts *bind.CallOpts, source, dest msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
+	record, err := h.contract.GetDepositRecord(opts, uint64(nonce), uint8(dest))
 	if err != nil {
-		l.log.Error("Error Unpacking ERC721 Deposit Record", "err", err)
-		return msg.Message{}, err
+		return msg.Message{}, fmt.Errorf("error unpacking ERC721 deposit record: %w", err)
 	}
 
 	return msg.NewNonFungibleTransfer(
-		l.cfg.id,
-		destId,
+		source,
+		dest,
 		nonce,
 		record.ResourceID,
 		record.TokenID,
@@ -47,18 +112,19 @@ func (l *listener) handleErc721DepositedEvent(destId msg.ChainId, nonce msg.Nonc
 	), nil
 }
 
-func (l *listener) handleGenericDepositedEvent(destId msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
-	l.log.Info("Handling generic deposit event")
+type genericDepositHandler struct {
+	contract *GenericHandler.GenericHandler
+}
 
-	record, err := l.genericHandlerContract.GetDepositRecord(&bind.CallOpts{From: l.conn.Keypair().CommonAddress()}, uint64(nonce), uint8(destId))
+func (h *genericDepositHandler) HandleDeposit(opts *bind.CallOpts, source, dest msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
+	record, err := h.contract.GetDepositRecord(opts, uint64(nonce), uint8(dest))
 	if err != nil {
-		l.log.Error("Error Unpacking Generic Deposit Record", "err", err)
-		return msg.Message{}, nil
+		return msg.Message{}, fmt.Errorf("error unpacking generic deposit record: %w", err)
 	}
 
 	return msg.NewGenericTransfer(
-		l.cfg.id,
-		destId,
+		source,
+		dest,
 		nonce,
 		record.ResourceID,
 		record.MetaData[:],
@@ -0,0 +1,71 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// mockDepositHandler produces a fungible transfer for every deposit
+type mockDepositHandler struct {
+	addr common.Address
+}
+
+func (h *mockDepositHandler) HandleDeposit(_ *bind.CallOpts, source, dest msg.ChainId, nonce msg.Nonce) (msg.Message, error) {
+	return msg.NewFungibleTransfer(source, dest, nonce, big.NewInt(1), msg.ResourceIdFromSlice(h.addr.Bytes()), h.addr.Bytes()), nil
+}
+
+func TestRegisterDepositHandlerKind(t *testing.T) {
+	kind := "mock"
+	RegisterDepositHandlerKind(kind, func(addr common.Address, _ bind.ContractBackend) (DepositHandler, error) {
+		return &mockDepositHandler{addr}, nil
+	})
+	defer delete(depositHandlerKinds, kind)
+
+	handlers, err := parseHandlers("mock:0x1111111111111111111111111111111111111111,mock:0x2222222222222222222222222222222222222222")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := make(handlerRegistry)
+	for _, h := range handlers {
+		handler, err := newDepositHandler(h.kind, h.address, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		registry.register(h.address, handler)
+	}
+
+	for _, h := range handlers {
+		handler, ok := registry.lookup(h.address)
+		if !ok {
+			t.Fatalf("No handler registered for %s", h.address.Hex())
+		}
+		m, err := handler.HandleDeposit(nil, 1, 2, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(1), msg.ResourceIdFromSlice(h.address.Bytes()), h.address.Bytes())
+		if !reflect.DeepEqual(m, expected) {
+			t.Fatalf("Unexpected message.\n\tExpected: %#v\n\tGot: %#v\n", expected, m)
+		}
+	}
+
+	if _, ok := registry.lookup(common.HexToAddress("0x9abc")); ok {
+		t.Fatal("Expected no handler for unregistered address")
+	}
+}
+
+func TestNewDepositHandler_UnknownKind(t *testing.T) {
+	_, err := newDepositHandler("unknown", common.HexToAddress("0x1234"), nil)
+	if err == nil {
+		t.Fatal("Expected error for unknown handler kind")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/ChainSafe/ChainBridge/bindings/Bridge"
@@ -23,6 +24,7 @@ import (
 	eth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 )
 
 var BlockRetryInterval = time.Second * 5
@@ -30,20 +32,20 @@ var BlockRetryLimit = 5
 var ErrFatalPolling = errors.New("listener block polling failed")
 
 type listener struct {
-	cfg                    Config
-	conn                   Connection
-	router                 chains.Router
-	bridgeContract         *Bridge.Bridge // instance of bound bridge contract
-	erc20HandlerContract   *ERC20Handler.ERC20Handler
-	erc721HandlerContract  *ERC721Handler.ERC721Handler
-	genericHandlerContract *GenericHandler.GenericHandler
-	log                    log15.Logger
-	blockstore             blockstore.Blockstorer
-	stop                   <-chan int
-	sysErr                 chan<- error // Reports fatal error to core
-	latestBlock            metrics.LatestBlock
-	metrics                *metrics.ChainMetrics
-	blockConfirmations     *big.Int
+	cfg                Config
+	conn               Connection
+	router             chains.Router
+	bridgeContract     *Bridge.Bridge  // instance of bound bridge contract
+	handlers           handlerRegistry // deposit handlers by handler contract address
+	log                log15.Logger
+	blockstore         blockstore.Blockstorer
+	stop               <-chan int
+	sysErr             chan<- error // Reports fatal error to core
+	latestBlock        metrics.LatestBlock
+	metrics            *metrics.ChainMetrics
+	ethMetrics         *ethMetrics
+	blockConfirmations *big.Int
+	history            *blockHistory // recently processed blocks, used to detect reorgs
 }
 
 // NewListener creates and returns a listener
@@ -58,15 +60,33 @@ func NewListener(conn Connection, cfg *Config, log log15.Logger, bs blockstore.B
 		latestBlock:        metrics.LatestBlock{LastUpdated: time.Now()},
 		metrics:            m,
 		blockConfirmations: cfg.blockConfirmations,
+		history:            newBlockHistory(ReorgHistoryLength),
+		handlers:           make(handlerRegistry),
 	}
 }
 
 // setContracts sets the listener with the appropriate contracts
 func (l *listener) setContracts(bridge *Bridge.Bridge, erc20Handler *ERC20Handler.ERC20Handler, erc721Handler *ERC721Handler.ERC721Handler, genericHandler *GenericHandler.GenericHandler) {
 	l.bridgeContract = bridge
-	l.erc20HandlerContract = erc20Handler
-	l.erc721HandlerContract = erc721Handler
-	l.genericHandlerContract = genericHandler
+	if l.cfg.erc20HandlerContract != utils.ZeroAddress {
+		l.handlers.register(l.cfg.erc20HandlerContract, &erc20DepositHandler{erc20Handler})
+	}
+	if l.cfg.erc721HandlerContract != utils.ZeroAddress {
+		l.handlers.register(l.cfg.erc721HandlerContract, &erc721DepositHandler{erc721Handler})
+	}
+	if l.cfg.genericHandlerContract != utils.ZeroAddress {
+		l.handlers.register(l.cfg.genericHandlerContract, &genericDepositHandler{genericHandler})
+	}
+}
+
+// registerHandler adds a deposit handler for the handler contract at addr
+func (l *listener) registerHandler(addr ethcommon.Address, handler DepositHandler) {
+	l.handlers.register(addr, handler)
+}
+
+// setEthMetrics sets the ethereum specific metrics
+func (l *listener) setEthMetrics(m *ethMetrics) {
+	l.ethMetrics = m
 }
 
 // sets the router
@@ -89,12 +109,15 @@ func (l *listener) start() error {
 }
 
 // pollBlocks will poll for the latest block and proceed to parse the associated events as it sees new blocks.
-// Polling begins at the block defined in `l.cfg.startBlock`. Failed attempts to fetch the latest block or parse
-// a block will be retried up to BlockRetryLimit times before continuing to the next block.
+// Polling begins at the block defined in `l.cfg.startBlock`. Confirmed blocks are scanned in ranges of up to
+// `l.cfg.maxBlockRange` blocks. Before each range the parent of its first block is checked against the last
+// processed block, and blocks replaced by a reorganisation are scanned again. Failed attempts to fetch the latest
+// block or parse a range will be retried up to BlockRetryLimit times before continuing to the next block.
 func (l *listener) pollBlocks() error {
 	l.log.Info("Polling Blocks...")
 	var currentBlock = l.cfg.startBlock
 	var retry = BlockRetryLimit
+	var unrecorded *processedBlock // Processed range whose hash is not yet in the history
 	for {
 		select {
 		case <-l.stop:
@@ -107,6 +130,18 @@ func (l *listener) pollBlocks() error {
 				return nil
 			}
 
+			// Reorgs can only be detected against the last processed range, so don't move on without its hash
+			if unrecorded != nil {
+				err := l.recordProcessedBlock(unrecorded.start, unrecorded.number)
+				if err != nil {
+					l.log.Error("Unable to fetch header of processed block", "block", unrecorded.number, "err", err)
+					retry--
+					time.Sleep(BlockRetryInterval)
+					continue
+				}
+				unrecorded = nil
+			}
+
 			latestBlock, err := l.conn.LatestBlock()
 			if err != nil {
 				l.log.Error("Unable to get latest block", "block", currentBlock, "err", err)
@@ -120,86 +155,148 @@ func (l *listener) pollBlocks() error {
 			}
 
 			// Sleep if the difference is less than BlockDelay; (latest - current) < BlockDelay
-			if big.NewInt(0).Sub(latestBlock, currentBlock).Cmp(l.blockConfirmations) == -1 {
+			// Otherwise scan up to maxBlockRange confirmed blocks at once
+			endBlock := nextBlockRange(currentBlock, latestBlock, l.blockConfirmations, l.cfg.maxBlockRange)
+			if endBlock == nil {
 				l.log.Debug("Block not ready, will retry", "target", currentBlock, "latest", latestBlock)
 				time.Sleep(BlockRetryInterval)
 				continue
 			}
 
-			// Parse out events
-			err = l.getDepositEventsForBlock(currentBlock)
+			// Rescan from the most recent canonical block if the chain was reorganised since the last range
+			resumeBlock, err := l.checkForReorg(currentBlock)
 			if err != nil {
-				l.log.Error("Failed to get events for block", "block", currentBlock, "err", err)
+				l.log.Error("Unable to check for chain reorganisation", "block", currentBlock, "err", err)
 				retry--
+				time.Sleep(BlockRetryInterval)
 				continue
 			}
-
-			// Write to block store. Not a critical operation, no need to retry
-			err = l.blockstore.StoreBlock(currentBlock)
-			if err != nil {
-				l.log.Error("Failed to write latest block to blockstore", "block", currentBlock, "err", err)
+			if resumeBlock != nil {
+				currentBlock = resumeBlock
+				continue
 			}
 
-			if l.metrics != nil {
-				l.metrics.BlocksProcessed.Inc()
-				l.metrics.LatestProcessedBlock.Set(float64(latestBlock.Int64()))
+			// Parse out events
+			failedBlock, err := l.getDepositEventsForBlockRange(currentBlock, endBlock)
+			if err != nil {
+				l.log.Error("Failed to get events for block range", "from", currentBlock, "to", endBlock, "err", err)
+				// Blocks before the failing one were fully processed, resume from the failing block
+				if failedBlock != nil && failedBlock.Cmp(currentBlock) == 1 {
+					processed := big.NewInt(0).Sub(failedBlock, big.NewInt(1))
+					l.storeProcessedBlocks(currentBlock, processed, latestBlock)
+					unrecorded = &processedBlock{start: currentBlock, number: processed}
+					currentBlock = failedBlock
+				}
+				retry--
+				continue
 			}
 
-			l.latestBlock.Height = big.NewInt(0).Set(latestBlock)
-			l.latestBlock.LastUpdated = time.Now()
+			l.storeProcessedBlocks(currentBlock, endBlock, latestBlock)
+			unrecorded = &processedBlock{start: currentBlock, number: endBlock}
 
 			// Goto next block and reset retry counter
-			currentBlock.Add(currentBlock, big.NewInt(1))
+			currentBlock = big.NewInt(0).Add(endBlock, big.NewInt(1))
 			retry = BlockRetryLimit
 		}
 	}
 }
 
-// getDepositEventsForBlock looks for the deposit event in the latest block
-func (l *listener) getDepositEventsForBlock(latestBlock *big.Int) error {
-	l.log.Debug("Querying block for deposit events", "block", latestBlock)
-	query := buildQuery(l.cfg.bridgeContract, utils.Deposit, latestBlock, latestBlock)
+// nextBlockRange returns the last block of the next range to scan, starting at currentBlock. The range is
+// limited by maxRange and by the latest block that has the required number of confirmations. Nil is returned
+// if currentBlock itself is not yet confirmed.
+func nextBlockRange(currentBlock, latestBlock, confirmations, maxRange *big.Int) *big.Int {
+	confirmed := big.NewInt(0).Sub(latestBlock, confirmations)
+	if confirmed.Cmp(currentBlock) == -1 {
+		return nil
+	}
+
+	endBlock := big.NewInt(0).Add(currentBlock, maxRange)
+	endBlock.Sub(endBlock, big.NewInt(1))
+	if endBlock.Cmp(confirmed) == 1 {
+		endBlock = confirmed
+	}
+	return endBlock
+}
+
+// storeProcessedBlocks records the range [startBlock, endBlock] as processed in the blockstore and metrics
+func (l *listener) storeProcessedBlocks(startBlock, endBlock, latestBlock *big.Int) {
+	// Write to block store. Not a critical operation, no need to retry
+	err := l.blockstore.StoreBlock(endBlock)
+	if err != nil {
+		l.log.Error("Failed to write latest block to blockstore", "block", endBlock, "err", err)
+	}
+
+	if l.metrics != nil {
+		count := big.NewInt(0).Sub(endBlock, startBlock)
+		l.metrics.BlocksProcessed.Add(float64(count.Int64() + 1))
+		l.metrics.LatestProcessedBlock.Set(float64(endBlock.Int64()))
+	}
+
+	l.latestBlock.Height = big.NewInt(0).Set(latestBlock)
+	l.latestBlock.LastUpdated = time.Now()
+}
+
+// getDepositEventsForBlockRange looks for deposit events in the blocks from startBlock to endBlock (inclusive)
+// and routes them in block and log index order. If an event cannot be handled, the block containing it is
+// returned along with the error so that scanning can resume from there.
+func (l *listener) getDepositEventsForBlockRange(startBlock, endBlock *big.Int) (*big.Int, error) {
+	l.log.Debug("Querying block range for deposit events", "from", startBlock, "to", endBlock)
+	query := buildQuery(l.cfg.bridgeContract, utils.Deposit, startBlock, endBlock)
 
 	// querying for logs
-	logs, err := l.conn.Client().FilterLogs(context.Background(), query)
+	logs, err := l.conn.FilterLogs(context.Background(), query)
 	if err != nil {
-		return fmt.Errorf("unable to Filter Logs: %w", err)
+		return startBlock, fmt.Errorf("unable to Filter Logs: %w", err)
 	}
+	return l.handleDepositLogs(logs, l.router)
+}
+
+// handleDepositLogs sends the messages of deposit logs to the router in block and log index order. If an event
+// cannot be handled, the block containing it is returned along with the error.
+func (l *listener) handleDepositLogs(logs []ethtypes.Log, router chains.Router) (*big.Int, error) {
+	sortLogs(logs)
 
 	// read through the log events and handle their deposit event if handler is recognized
 	for _, log := range logs {
-		var m msg.Message
 		destId := msg.ChainId(log.Topics[1].Big().Uint64())
 		rId := msg.ResourceIdFromSlice(log.Topics[2].Bytes())
 		nonce := msg.Nonce(log.Topics[3].Big().Uint64())
+		block := big.NewInt(0).SetUint64(log.BlockNumber)
 
 		addr, err := l.bridgeContract.ResourceIDToHandlerAddress(&bind.CallOpts{From: l.conn.Keypair().CommonAddress()}, rId)
 		if err != nil {
-			return fmt.Errorf("failed to get handler from resource ID %x", rId)
+			return block, fmt.Errorf("failed to get handler from resource ID %x", rId)
 		}
 
-		if addr == l.cfg.erc20HandlerContract {
-			m, err = l.handleErc20DepositedEvent(destId, nonce)
-		} else if addr == l.cfg.erc721HandlerContract {
-			m, err = l.handleErc721DepositedEvent(destId, nonce)
-		} else if addr == l.cfg.genericHandlerContract {
-			m, err = l.handleGenericDepositedEvent(destId, nonce)
-		} else {
-			l.log.Error("event has unrecognized handler", "handler", addr.Hex())
-			return nil
+		handler, ok := l.handlers.lookup(addr)
+		if !ok {
+			l.log.Error("event has unrecognized handler", "handler", addr.Hex(), "block", block, "nonce", nonce)
+			continue
 		}
 
+		l.log.Info("Handling deposit event", "handler", addr.Hex(), "dest", destId, "nonce", nonce)
+		m, err := handler.HandleDeposit(&bind.CallOpts{From: l.conn.Keypair().CommonAddress()}, l.cfg.id, destId, nonce)
 		if err != nil {
-			return err
+			return block, err
 		}
 
-		err = l.router.Send(m)
+		err = router.Send(m)
 		if err != nil {
 			l.log.Error("subscription error: failed to route message", "err", err)
 		}
 	}
 
-	return nil
+	return nil, nil
+}
+
+// sortLogs orders logs by block number and then by their index within the block
+func sortLogs(logs []ethtypes.Log) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
 }
 
 // buildQuery constructs a query for the bridgeContract by hashing sig to get the event topic
@@ -20,6 +20,7 @@ import (
 	"github.com/ChainSafe/chainbridge-utils/msg"
 	"github.com/ChainSafe/log15"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -258,6 +259,92 @@ func TestListener_GenericDepositedEvent(t *testing.T) {
 	verifyMessage(t, router, expectedMessage, errs)
 }
 
+func TestListener_findDeposit(t *testing.T) {
+	client := ethtest.NewClient(t, TestEndpoint, AliceKp)
+	contracts := deployTestContracts(t, client, aliceTestConfig.id)
+	errs := make(chan error)
+	l, router := createTestListener(t, aliceTestConfig, contracts, make(chan int), errs)
+
+	erc20Contract := ethtest.DeployMintApproveErc20(t, client, contracts.ERC20HandlerAddress, big.NewInt(100))
+
+	amount := big.NewInt(10)
+	src := msg.ChainId(0)
+	dst := msg.ChainId(1)
+	resourceId := msg.ResourceIdFromSlice(append(common.LeftPadBytes(erc20Contract.Bytes(), 31), uint8(src)))
+	recipient := ethcrypto.PubkeyToAddress(BobKp.PrivateKey().PublicKey)
+	ethtest.RegisterResource(t, client, contracts.BridgeAddress, contracts.ERC20HandlerAddress, resourceId, erc20Contract)
+
+	for nonce := msg.Nonce(1); nonce <= 2; nonce++ {
+		createErc20Deposit(t, l.bridgeContract, client, resourceId, recipient, dst, amount)
+		verifyMessage(t, router, msg.NewFungibleTransfer(src, dst, nonce, amount, resourceId, recipient.Bytes()), errs)
+	}
+
+	deposit, err := l.findDeposit(dst, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = compareMessage(msg.NewFungibleTransfer(src, dst, 1, amount, resourceId, recipient.Bytes()), deposit.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deposit.Tx == "" || deposit.Block < l.cfg.startBlock.Uint64() {
+		t.Fatalf("Unexpected deposit location: block %d, tx %q", deposit.Block, deposit.Tx)
+	}
+
+	_, err = l.findDeposit(dst, 3)
+	if err == nil {
+		t.Fatal("Expected an error for a nonce that was not deposited")
+	}
+}
+
+func TestListener_nextBlockRange(t *testing.T) {
+	testCases := []struct {
+		name          string
+		current       int64
+		latest        int64
+		confirmations int64
+		maxRange      int64
+		expected      *big.Int
+	}{
+		{"not confirmed", 100, 105, 10, 50, nil},
+		{"single block", 95, 105, 10, 50, big.NewInt(95)},
+		{"limited by confirmations", 80, 105, 10, 50, big.NewInt(95)},
+		{"limited by range", 0, 1000, 10, 50, big.NewInt(49)},
+		{"range of one", 10, 1000, 10, 1, big.NewInt(10)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := nextBlockRange(big.NewInt(tc.current), big.NewInt(tc.latest), big.NewInt(tc.confirmations), big.NewInt(tc.maxRange))
+			if tc.expected == nil && res != nil {
+				t.Fatalf("Expected no range, got end block %s", res)
+			} else if tc.expected != nil && (res == nil || res.Cmp(tc.expected) != 0) {
+				t.Fatalf("Unexpected end block. Expected: %s Got: %s", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestListener_sortLogs(t *testing.T) {
+	logs := []ethtypes.Log{
+		{BlockNumber: 12, Index: 0},
+		{BlockNumber: 10, Index: 3},
+		{BlockNumber: 10, Index: 1},
+		{BlockNumber: 11, Index: 2},
+	}
+	sortLogs(logs)
+
+	expected := []ethtypes.Log{
+		{BlockNumber: 10, Index: 1},
+		{BlockNumber: 10, Index: 3},
+		{BlockNumber: 11, Index: 2},
+		{BlockNumber: 12, Index: 0},
+	}
+	if !reflect.DeepEqual(expected, logs) {
+		t.Fatalf("Logs not sorted.\n\tExpected: %#v\n\tGot: %#v\n", expected, logs)
+	}
+}
+
 func compareMessage(expected, actual msg.Message) error {
 	if !reflect.DeepEqual(expected, actual) {
 		if !reflect.DeepEqual(expected.Source, actual.Source) {
@@ -0,0 +1,72 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ethMetrics are ethereum specific metrics, registered alongside the core chain metrics
+type ethMetrics struct {
+	ChainReorgs    prometheus.Counter
+	LastReorgDepth prometheus.Gauge
+	TxsMined       prometheus.Counter
+	TxsReverted    prometheus.Counter
+	TxsReplaced    prometheus.Counter
+	TxsAbandoned   prometheus.Counter
+}
+
+func newEthMetrics(chain string) *ethMetrics {
+	metrics := &ethMetrics{
+		ChainReorgs: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_chain_reorgs", chain),
+			Help: "Number of chain reorganisations detected by the listener",
+		}),
+		LastReorgDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: fmt.Sprintf("%s_last_reorg_depth", chain),
+			Help: "Number of blocks rescanned after the most recent chain reorganisation",
+		}),
+		TxsMined: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_txs_mined", chain),
+			Help: "Number of transactions included without being replaced",
+		}),
+		TxsReverted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_txs_reverted", chain),
+			Help: "Number of transactions that reverted",
+		}),
+		TxsReplaced: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_txs_replaced", chain),
+			Help: "Number of transactions included after being replaced with a higher fee",
+		}),
+		TxsAbandoned: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_txs_abandoned", chain),
+			Help: "Number of transactions given up on before being included",
+		}),
+	}
+
+	prometheus.MustRegister(metrics.ChainReorgs)
+	prometheus.MustRegister(metrics.LastReorgDepth)
+	prometheus.MustRegister(metrics.TxsMined)
+	prometheus.MustRegister(metrics.TxsReverted)
+	prometheus.MustRegister(metrics.TxsReplaced)
+	prometheus.MustRegister(metrics.TxsAbandoned)
+
+	return metrics
+}
+
+// txOutcome increments the counter of the given transaction outcome
+func (m *ethMetrics) txOutcome(outcome txOutcome) {
+	switch outcome {
+	case TxMined:
+		m.TxsMined.Inc()
+	case TxReverted:
+		m.TxsReverted.Inc()
+	case TxReplaced:
+		m.TxsReplaced.Inc()
+	case TxAbandoned:
+		m.TxsAbandoned.Inc()
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/outbox"
+	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// DepositsInBlock builds the messages for the deposits of a block as the listener does
+func (c *Chain) DepositsInBlock(block uint64) ([]msg.Message, error) {
+	return c.listener.depositsInBlock(block)
+}
+
+// DepositsInTx builds the messages for the deposits of a transaction as the listener does
+func (c *Chain) DepositsInTx(hash string) ([]msg.Message, error) {
+	return c.listener.depositsInTx(hash)
+}
+
+// Relay passes a message to the writer once, as the router does. Proposals that pass are executed by the writer in
+// the background, so Relay waits up to the timeout for the message to be executed, failed or held. It returns the
+// latest outbox entry of the message.
+func (c *Chain) Relay(m msg.Message, timeout time.Duration) outbox.Entry {
+	if c.relayed == nil {
+		c.relayed = outbox.NewWatcher(c.writer.outbox)
+		c.writer.setOutbox(c.relayed)
+	}
+	if !c.writer.ResolveMessage(m) {
+		// Nothing is left running for the message
+		e, _ := c.relayed.Latest(m)
+		return e
+	}
+	e, _ := c.relayed.Wait(m, timeout, outbox.Executed, outbox.Failed, outbox.Held)
+	return e
+}
+
+func (l *listener) depositsInBlock(block uint64) ([]msg.Message, error) {
+	number := new(big.Int).SetUint64(block)
+	logs, err := l.conn.FilterLogs(context.Background(), buildQuery(l.cfg.bridgeContract, utils.Deposit, number, number))
+	if err != nil {
+		return nil, fmt.Errorf("unable to Filter Logs: %w", err)
+	}
+	return l.depositMessages(logs)
+}
+
+// depositsInTx selects the deposit logs of the bridge from the receipt of a transaction
+func (l *listener) depositsInTx(hash string) ([]msg.Message, error) {
+	if len(ethcommon.FromHex(hash)) != ethcommon.HashLength {
+		return nil, fmt.Errorf("invalid transaction hash %s", hash)
+	}
+	receipt, err := l.conn.Client().TransactionReceipt(context.Background(), ethcommon.HexToHash(hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt of tx %s: %w", hash, err)
+	}
+	if receipt.Status == ethtypes.ReceiptStatusFailed {
+		return nil, fmt.Errorf("tx %s reverted", hash)
+	}
+	return l.depositMessages(depositLogs(receipt.Logs, l.cfg.bridgeContract))
+}
+
+// depositLogs returns the deposit events emitted by the bridge
+func depositLogs(logs []*ethtypes.Log, bridge ethcommon.Address) []ethtypes.Log {
+	var res []ethtypes.Log
+	for _, log := range logs {
+		if log.Address == bridge && len(log.Topics) > 0 && log.Topics[0] == utils.Deposit.GetTopic() {
+			res = append(res, *log)
+		}
+	}
+	return res
+}
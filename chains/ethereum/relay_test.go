@@ -0,0 +1,29 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"testing"
+
+	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDepositLogs(t *testing.T) {
+	bridge := ethcommon.HexToAddress("0x01")
+	other := ethcommon.HexToAddress("0x02")
+	logs := []*ethtypes.Log{
+		{Address: bridge, Topics: []ethcommon.Hash{utils.Deposit.GetTopic()}, Index: 0},
+		{Address: other, Topics: []ethcommon.Hash{utils.Deposit.GetTopic()}, Index: 1},
+		{Address: bridge, Topics: []ethcommon.Hash{utils.ProposalEvent.GetTopic()}, Index: 2},
+		{Address: bridge, Index: 3},
+		{Address: bridge, Topics: []ethcommon.Hash{utils.Deposit.GetTopic()}, Index: 4},
+	}
+
+	res := depositLogs(logs, bridge)
+	if len(res) != 2 || res[0].Index != 0 || res[1].Index != 4 {
+		t.Fatalf("Unexpected deposit logs: %#v", res)
+	}
+}
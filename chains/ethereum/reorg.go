@@ -0,0 +1,141 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// Number of processed blocks whose hashes are kept to detect chain reorganisations
+var ReorgHistoryLength = 64
+
+type headerFetcher func(number *big.Int) (*ethtypes.Header, error)
+
+// processedBlock is the last block of a processed range
+type processedBlock struct {
+	start  *big.Int // First block of the range
+	number *big.Int
+	hash   ethcommon.Hash
+}
+
+// blockHistory tracks the hashes of the most recently processed ranges, oldest first
+type blockHistory struct {
+	blocks []processedBlock
+	size   int
+}
+
+func newBlockHistory(size int) *blockHistory {
+	return &blockHistory{size: size}
+}
+
+// add records the last block of a processed range, dropping the oldest range if the history is full
+func (h *blockHistory) add(start, number *big.Int, hash ethcommon.Hash) {
+	h.blocks = append(h.blocks, processedBlock{start: big.NewInt(0).Set(start), number: big.NewInt(0).Set(number), hash: hash})
+	if len(h.blocks) > h.size {
+		h.blocks = h.blocks[len(h.blocks)-h.size:]
+	}
+}
+
+// last returns the most recently processed block, or nil if the history is empty
+func (h *blockHistory) last() *processedBlock {
+	if len(h.blocks) == 0 {
+		return nil
+	}
+	return &h.blocks[len(h.blocks)-1]
+}
+
+// truncate removes all blocks after the given block number
+func (h *blockHistory) truncate(number *big.Int) {
+	for i := len(h.blocks) - 1; i >= 0; i-- {
+		if h.blocks[i].number.Cmp(number) <= 0 {
+			h.blocks = h.blocks[:i+1]
+			return
+		}
+	}
+	h.blocks = nil
+}
+
+// findCommonAncestor walks back through the history and returns the most recent block that is still part of
+// the canonical chain. Nil is returned if none of the tracked blocks are canonical.
+func (h *blockHistory) findCommonAncestor(fetch headerFetcher) (*processedBlock, error) {
+	for i := len(h.blocks) - 1; i >= 0; i-- {
+		header, err := fetch(h.blocks[i].number)
+		if err != nil {
+			return nil, err
+		}
+		if header.Hash() == h.blocks[i].hash {
+			return &h.blocks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (l *listener) headerByNumber(number *big.Int) (*ethtypes.Header, error) {
+	return l.conn.Client().HeaderByNumber(context.Background(), number)
+}
+
+// recordProcessedBlock stores the hash of the last block of a processed range so it can be checked against its
+// child later
+func (l *listener) recordProcessedBlock(start, number *big.Int) error {
+	header, err := l.headerByNumber(number)
+	if err != nil {
+		return err
+	}
+	l.history.add(start, number, header.Hash())
+	return nil
+}
+
+// checkForReorg verifies that the parent of currentBlock is the block that was last processed. If the chain has
+// been reorganised, the blockstore is rewound to the most recent canonical block and the block to resume
+// scanning from is returned. Nil is returned if no reorganisation was detected.
+func (l *listener) checkForReorg(currentBlock *big.Int) (*big.Int, error) {
+	last := l.history.last()
+	if last == nil || big.NewInt(0).Add(last.number, big.NewInt(1)).Cmp(currentBlock) != 0 {
+		return nil, nil
+	}
+
+	header, err := l.headerByNumber(currentBlock)
+	if err != nil {
+		return nil, err
+	}
+	if header.ParentHash == last.hash {
+		return nil, nil
+	}
+
+	ancestor, err := l.history.findCommonAncestor(l.headerByNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var resumeBlock *big.Int
+	if ancestor != nil {
+		resumeBlock = big.NewInt(0).Add(ancestor.number, big.NewInt(1))
+		l.history.truncate(ancestor.number)
+	} else {
+		// None of the tracked blocks are canonical anymore, rescan from the start of the oldest tracked range
+		resumeBlock = big.NewInt(0).Set(l.history.blocks[0].start)
+		l.history.truncate(big.NewInt(-1))
+		l.log.Warn("Chain reorganisation is deeper than the tracked block history", "history", ReorgHistoryLength)
+	}
+
+	depth := big.NewInt(0).Sub(currentBlock, resumeBlock)
+	l.log.Error("Chain reorganisation detected, rescanning blocks", "block", currentBlock, "expectedParent", last.hash.Hex(),
+		"actualParent", header.ParentHash.Hex(), "depth", depth, "confirmations", l.blockConfirmations, "resumeBlock", resumeBlock)
+	if l.ethMetrics != nil {
+		l.ethMetrics.ChainReorgs.Inc()
+		l.ethMetrics.LastReorgDepth.Set(float64(depth.Int64()))
+	}
+
+	// Rewind the blockstore so a restart doesn't skip the reorganised blocks
+	err = l.blockstore.StoreBlock(big.NewInt(0).Sub(resumeBlock, big.NewInt(1)))
+	if err != nil {
+		l.log.Error("Failed to rewind blockstore", "block", resumeBlock, "err", err)
+	}
+
+	return resumeBlock, nil
+}
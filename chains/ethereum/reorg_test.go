@@ -0,0 +1,238 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// testHeader returns a header whose hash is unique for the given number and fork
+func testHeader(number int64, fork uint64) *ethtypes.Header {
+	return &ethtypes.Header{Number: big.NewInt(number), Nonce: ethtypes.EncodeNonce(fork), Difficulty: big.NewInt(0)}
+}
+
+func TestBlockHistory_add(t *testing.T) {
+	h := newBlockHistory(3)
+	if h.last() != nil {
+		t.Fatal("expected empty history")
+	}
+
+	for i := int64(1); i <= 5; i++ {
+		h.add(big.NewInt(i), big.NewInt(i), testHeader(i, 0).Hash())
+	}
+
+	if len(h.blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %d", len(h.blocks))
+	}
+	if h.blocks[0].number.Int64() != 3 || h.last().number.Int64() != 5 {
+		t.Fatalf("unexpected history range: %s - %s", h.blocks[0].number, h.last().number)
+	}
+}
+
+func TestBlockHistory_truncate(t *testing.T) {
+	h := newBlockHistory(10)
+	for i := int64(1); i <= 5; i++ {
+		h.add(big.NewInt(i), big.NewInt(i), testHeader(i, 0).Hash())
+	}
+
+	h.truncate(big.NewInt(3))
+	if h.last().number.Int64() != 3 {
+		t.Fatalf("expected last block 3, got %s", h.last().number)
+	}
+
+	h.truncate(big.NewInt(0))
+	if h.last() != nil {
+		t.Fatal("expected empty history")
+	}
+}
+
+func TestBlockHistory_findCommonAncestor(t *testing.T) {
+	h := newBlockHistory(10)
+	for i := int64(1); i <= 5; i++ {
+		h.add(big.NewInt(i), big.NewInt(i), testHeader(i, 0).Hash())
+	}
+
+	// Blocks after 3 have been replaced
+	fetch := func(n *big.Int) (*ethtypes.Header, error) {
+		if n.Int64() > 3 {
+			return testHeader(n.Int64(), 1), nil
+		}
+		return testHeader(n.Int64(), 0), nil
+	}
+	ancestor, err := h.findCommonAncestor(fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ancestor == nil || ancestor.number.Int64() != 3 {
+		t.Fatalf("expected ancestor 3, got %v", ancestor)
+	}
+
+	// All tracked blocks have been replaced
+	fetch = func(n *big.Int) (*ethtypes.Header, error) {
+		return testHeader(n.Int64(), 1), nil
+	}
+	ancestor, err = h.findCommonAncestor(fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ancestor != nil {
+		t.Fatalf("expected no ancestor, got %s", ancestor.number)
+	}
+}
+
+// recordingStore is a blockstore that keeps every stored block
+type recordingStore struct {
+	lock   sync.Mutex
+	blocks []uint64
+}
+
+func (s *recordingStore) StoreBlock(block *big.Int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.blocks = append(s.blocks, block.Uint64())
+	return nil
+}
+
+func TestListener_pollBlocks_reorg(t *testing.T) {
+	defer func(interval time.Duration) { BlockRetryInterval = interval }(BlockRetryInterval)
+	BlockRetryInterval = time.Millisecond
+
+	node := newFakeNode()
+	node.extend(0, 4, 0)
+	reorged := false
+	node.onLatest = func() {
+		// Replace blocks 3 and 4 once block 4 has been processed, the listener is then asked for block 5
+		if !reorged && len(node.logRanges) > 0 && node.logRanges[len(node.logRanges)-1][1] == 4 {
+			node.extend(3, 6, 1)
+			reorged = true
+		}
+	}
+
+	cfg := createConfig("alice", big.NewInt(1), nil)
+	cfg.blockConfirmations = big.NewInt(0)
+	cfg.maxBlockRange = big.NewInt(1)
+	store := &recordingStore{}
+	stop := make(chan int)
+	l := NewListener(newFakeConnection(t, node), cfg, TestLogger, store, stop, make(chan error, 1), nil)
+	l.setEthMetrics(newTestEthMetrics())
+
+	done := make(chan error)
+	go func() { done <- l.pollBlocks() }()
+	deadline := time.After(TestTimeout)
+	for {
+		ranges := node.queriedRanges()
+		if len(ranges) > 0 && ranges[len(ranges)-1][1] == 6 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("Timed out polling blocks, queried: %v", ranges)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(stop)
+	<-done
+
+	// Blocks 3 and 4 are scanned again after the reorg
+	expected := [][2]uint64{{1, 1}, {2, 2}, {3, 3}, {4, 4}, {3, 3}, {4, 4}, {5, 5}, {6, 6}}
+	if ranges := node.queriedRanges(); !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf("Unexpected block ranges.\n\tExpected: %v\n\tGot: %v", expected, ranges)
+	}
+	// The blockstore is rewound to the common ancestor
+	if !reflect.DeepEqual(store.blocks, []uint64{1, 2, 3, 4, 2, 3, 4, 5, 6}) {
+		t.Fatalf("Unexpected stored blocks: %v", store.blocks)
+	}
+	if reorgs := testutil.ToFloat64(l.ethMetrics.ChainReorgs); reorgs != 1 {
+		t.Fatalf("Expected 1 reorg, got %v", reorgs)
+	}
+	if depth := testutil.ToFloat64(l.ethMetrics.LastReorgDepth); depth != 2 {
+		t.Fatalf("Expected a reorg depth of 2, got %v", depth)
+	}
+}
+
+func TestListener_checkForReorg_beyondHistory(t *testing.T) {
+	node := newFakeNode()
+	node.extend(0, 5, 0)
+	l := NewListener(newFakeConnection(t, node), createConfig("alice", big.NewInt(1), nil), TestLogger, &recordingStore{}, make(chan int), make(chan error, 1), nil)
+	// Ranges 1-2 and 3-4 have been processed
+	for _, r := range [][2]int64{{1, 2}, {3, 4}} {
+		if err := l.recordProcessedBlock(big.NewInt(r[0]), big.NewInt(r[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	node.extend(1, 5, 1)
+	resumeBlock, err := l.checkForReorg(big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// None of the tracked ranges are canonical, so all of them are scanned again
+	if resumeBlock == nil || resumeBlock.Int64() != 1 {
+		t.Fatalf("Expected to resume from block 1, got %v", resumeBlock)
+	}
+	if l.history.last() != nil {
+		t.Fatal("Expected empty history")
+	}
+}
+
+func TestListener_pollBlocks_unrecordedBlock(t *testing.T) {
+	defer func(interval time.Duration) { BlockRetryInterval = interval }(BlockRetryInterval)
+	BlockRetryInterval = time.Millisecond
+
+	node := newFakeNode()
+	node.extend(0, 3, 0)
+	// Once block 2 has been checked for a reorg, its header is unavailable for the next two requests
+	header := node.headers[2]
+	requests := 0
+	node.onHeader = func(number uint64) {
+		if number != 2 {
+			return
+		}
+		switch requests++; requests {
+		case 2:
+			delete(node.headers, 2)
+		case 4:
+			node.headers[2] = header
+		}
+	}
+
+	cfg := createConfig("alice", big.NewInt(1), nil)
+	cfg.blockConfirmations = big.NewInt(0)
+	cfg.maxBlockRange = big.NewInt(1)
+	stop := make(chan int)
+	l := NewListener(newFakeConnection(t, node), cfg, TestLogger, &recordingStore{}, stop, make(chan error, 1), nil)
+
+	done := make(chan error)
+	go func() { done <- l.pollBlocks() }()
+	deadline := time.After(TestTimeout)
+	for {
+		ranges := node.queriedRanges()
+		if len(ranges) > 0 && ranges[len(ranges)-1][1] == 3 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("Timed out polling blocks, queried: %v", ranges)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(stop)
+	<-done
+
+	// Block 3 is only scanned once block 2 is in the history
+	var recorded []int64
+	for _, b := range l.history.blocks {
+		recorded = append(recorded, b.number.Int64())
+	}
+	if !reflect.DeepEqual(recorded[:2], []int64{1, 2}) {
+		t.Fatalf("Unexpected recorded blocks: %v", recorded)
+	}
+}
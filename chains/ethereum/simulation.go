@@ -0,0 +1,203 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/bindings/IDepositExecute"
+	"github.com/ChainSafe/ChainBridge/outbox"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Policies applied when the simulated execution of a proposal fails
+const (
+	SimulationDisabled = "disabled" // Don't simulate, always vote
+	SimulationSkip     = "skip"     // Don't vote and mark the message as failed
+	SimulationHold     = "hold"     // Don't vote and retry the message with a backoff, up to SimulationHoldLimit times
+)
+
+// Time before the first retry of a message held due to a failed simulation, doubled for every further retry
+var SimulationHoldInterval = time.Minute
+
+// Longest time between retries of a held message
+var SimulationMaxHoldInterval = time.Hour
+
+// Number of retries of a held message before it is marked failed
+var SimulationHoldLimit = 10
+
+var depositExecuteABI = mustParseABI(IDepositExecute.IDepositExecuteABI)
+
+func mustParseABI(def string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// SimulationError describes why the simulated execution of a proposal was rejected
+type SimulationError struct {
+	Source  msg.ChainId
+	Nonce   msg.Nonce
+	Handler ethcommon.Address
+	Reason  string // Revert reason returned by the handler, if any
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("execution of proposal (src=%d, nonce=%d) by handler %s would fail: %s", e.Source, e.Nonce, e.Handler.Hex(), e.Reason)
+}
+
+// simulateExecution calls executeProposal on the handler as the bridge would, against the pending state.
+// A SimulationError is returned if the execution would fail, any other error means the simulation could not be run.
+func (w *writer) simulateExecution(m msg.Message, handler ethcommon.Address, data []byte) error {
+	paused, err := w.bridgeContract.Paused(w.conn.CallOpts())
+	if err != nil {
+		return err
+	}
+	if paused {
+		return &SimulationError{Source: m.Source, Nonce: m.DepositNonce, Handler: handler, Reason: "bridge is paused"}
+	}
+
+	input, err := depositExecuteABI.Pack("executeProposal", m.ResourceId, data)
+	if err != nil {
+		return err
+	}
+
+	call := eth.CallMsg{From: w.cfg.bridgeContract, To: &handler, Data: input}
+	_, err = w.conn.Client().PendingCallContract(context.Background(), call)
+	if err != nil {
+		if reason, ok := revertReason(err); ok {
+			return &SimulationError{Source: m.Source, Nonce: m.DepositNonce, Handler: handler, Reason: reason}
+		}
+		return err
+	}
+	return nil
+}
+
+// revertReason extracts the revert reason from a call error. False is returned if the error is not a revert.
+func revertReason(err error) (string, bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if bz, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(bz); unpackErr == nil {
+					return reason, true
+				}
+			}
+		}
+	}
+
+	if strings.Contains(err.Error(), "revert") {
+		return err.Error(), true
+	}
+	return "", false
+}
+
+// checkSimulation simulates the execution of a proposal and applies the configured policy if it fails. Under the
+// hold policy, messages whose simulation could not be run are held as well, as the node may be unhealthy.
+// Returns true if the writer should vote on the proposal.
+func (w *writer) checkSimulation(m msg.Message, handler ethcommon.Address, data []byte) bool {
+	if w.cfg.simulationPolicy == SimulationDisabled {
+		return true
+	}
+
+	err := w.simulateExecution(m, handler, data)
+	if err == nil {
+		w.held.clear(m)
+		return true
+	}
+
+	var simErr *SimulationError
+	if errors.As(err, &simErr) {
+		w.log.Warn("Simulated proposal execution failed, not voting", "src", m.Source, "nonce", m.DepositNonce,
+			"handler", simErr.Handler.Hex(), "reason", simErr.Reason, "policy", w.cfg.simulationPolicy)
+	} else if w.cfg.simulationPolicy == SimulationHold {
+		w.log.Warn("Unable to simulate proposal execution, not voting", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+		err = fmt.Errorf("unable to simulate execution: %w", err)
+	} else {
+		w.log.Warn("Unable to simulate proposal execution, voting anyway", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+		return true
+	}
+
+	if w.cfg.simulationPolicy != SimulationHold {
+		w.updateOutbox(m, outbox.Failed, err.Error())
+		return false
+	}
+
+	retry := w.held.add(m)
+	if retry > SimulationHoldLimit {
+		w.log.Error("Held message retries exceeded", "src", m.Source, "nonce", m.DepositNonce, "retries", SimulationHoldLimit)
+		w.held.clear(m)
+		w.updateOutbox(m, outbox.Failed, fmt.Sprintf("%s, gave up after %d retries", err.Error(), SimulationHoldLimit))
+		return false
+	}
+	w.updateOutbox(m, outbox.Held, err.Error())
+	go w.holdMessage(m, holdDelay(retry))
+	return false
+}
+
+// holdMessage resolves the message again after the delay
+func (w *writer) holdMessage(m msg.Message, delay time.Duration) {
+	select {
+	case <-w.stop:
+		return
+	case <-time.After(delay):
+		w.log.Info("Retrying held message", "src", m.Source, "nonce", m.DepositNonce)
+		w.ResolveMessage(m)
+	}
+}
+
+// holdDelay returns the time before the nth retry of a held message
+func holdDelay(retry int) time.Duration {
+	delay := SimulationHoldInterval
+	for i := 1; i < retry && delay < SimulationMaxHoldInterval; i++ {
+		delay *= 2
+	}
+	if delay > SimulationMaxHoldInterval {
+		delay = SimulationMaxHoldInterval
+	}
+	return delay
+}
+
+// heldMessages counts the retries of messages held due to failed simulations
+type heldMessages struct {
+	retries map[heldKey]int
+	lock    sync.Mutex
+}
+
+type heldKey struct {
+	source msg.ChainId
+	nonce  msg.Nonce
+}
+
+func newHeldMessages() *heldMessages {
+	return &heldMessages{retries: make(map[heldKey]int)}
+}
+
+// add counts a retry of the message and returns the number of retries so far
+func (h *heldMessages) add(m msg.Message) int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	key := heldKey{m.Source, m.DepositNonce}
+	h.retries[key]++
+	return h.retries[key]
+}
+
+// clear forgets the retries of the message
+func (h *heldMessages) clear(m msg.Message) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.retries, heldKey{m.Source, m.DepositNonce})
+}
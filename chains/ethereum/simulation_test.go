@@ -0,0 +1,148 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/bindings/Bridge"
+	"github.com/ChainSafe/ChainBridge/outbox"
+	"github.com/ChainSafe/chainbridge-utils/core"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// testDataError mimics the error returned by the rpc client when a call reverts
+type testDataError struct {
+	msg  string
+	data interface{}
+}
+
+func (e *testDataError) Error() string          { return e.msg }
+func (e *testDataError) ErrorData() interface{} { return e.data }
+
+func TestRevertReason(t *testing.T) {
+	// Error(string) encoding of "ERC20: transfer amount exceeds balance"
+	data := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000026" +
+		"45524332303a207472616e7366657220616d6f756e7420657863656564732062" +
+		"616c616e63650000000000000000000000000000000000000000000000000000"
+
+	testCases := []struct {
+		name     string
+		err      error
+		reason   string
+		reverted bool
+	}{
+		{"decoded reason", &testDataError{msg: "execution reverted", data: data}, "ERC20: transfer amount exceeds balance", true},
+		{"reason in message", errors.New("VM Exception while processing transaction: revert"), "VM Exception while processing transaction: revert", true},
+		{"not a revert", errors.New("connection refused"), "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, reverted := revertReason(tc.err)
+			if reason != tc.reason || reverted != tc.reverted {
+				t.Fatalf("Expected (%q, %v), got (%q, %v)", tc.reason, tc.reverted, reason, reverted)
+			}
+		})
+	}
+}
+
+func TestInvalidSimulationPolicy(t *testing.T) {
+	input := core.ChainConfig{
+		Name:         "chain",
+		Id:           1,
+		Endpoint:     "endpoint",
+		From:         "0x0",
+		KeystorePath: "./keys",
+		Opts: map[string]string{
+			"bridge":           "0x1234",
+			"simulationPolicy": "ignore",
+		},
+	}
+
+	_, err := parseChainConfig(&input)
+	if err == nil {
+		t.Fatal("Config should not accept simulationPolicy ignore")
+	}
+}
+
+func TestHoldDelay(t *testing.T) {
+	defer func(interval, max time.Duration) {
+		SimulationHoldInterval, SimulationMaxHoldInterval = interval, max
+	}(SimulationHoldInterval, SimulationMaxHoldInterval)
+	SimulationHoldInterval, SimulationMaxHoldInterval = time.Minute, time.Minute*10
+
+	expected := []time.Duration{time.Minute, time.Minute * 2, time.Minute * 4, time.Minute * 8, time.Minute * 10, time.Minute * 10}
+	for i, delay := range expected {
+		if res := holdDelay(i + 1); res != delay {
+			t.Errorf("Unexpected delay of retry %d. Expected: %s Got: %s", i+1, delay, res)
+		}
+	}
+}
+
+func TestHeldMessages(t *testing.T) {
+	h := newHeldMessages()
+	rId := msg.ResourceIdFromSlice([]byte{1})
+	m := msg.NewGenericTransfer(1, 0, 3, rId, []byte{})
+	other := msg.NewGenericTransfer(1, 0, 4, rId, []byte{})
+
+	for i := 1; i <= 3; i++ {
+		if retries := h.add(m); retries != i {
+			t.Fatalf("Expected %d retries, got %d", i, retries)
+		}
+	}
+	if retries := h.add(other); retries != 1 {
+		t.Fatalf("Retries of messages should be counted separately, got %d", retries)
+	}
+	h.clear(m)
+	if retries := h.add(m); retries != 1 {
+		t.Fatalf("Expected retries to restart after clearing, got %d", retries)
+	}
+}
+
+func TestCheckSimulation_unavailable(t *testing.T) {
+	defer func(interval time.Duration) { SimulationHoldInterval = interval }(SimulationHoldInterval)
+	SimulationHoldInterval = time.Hour
+
+	m := msg.NewGenericTransfer(1, 0, 3, msg.ResourceIdFromSlice([]byte{1}), []byte{})
+	tests := []struct {
+		policy string
+		vote   bool
+		state  outbox.State
+	}{
+		// The fake node doesn't support eth_call, so the simulation can't be run
+		{SimulationHold, false, outbox.Held},
+		{SimulationSkip, true, ""},
+		{SimulationDisabled, true, ""},
+	}
+
+	for _, tt := range tests {
+		w := newTrackerWriter(t, newFakeNode(), 1000)
+		w.cfg.simulationPolicy = tt.policy
+		bridge, err := Bridge.NewBridge(ethcommon.Address{}, w.conn.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.setContract(bridge)
+		watcher := outbox.NewWatcher(&outbox.EmptyOutbox{})
+		w.setOutbox(watcher)
+
+		if vote := w.checkSimulation(m, ethcommon.Address{}, nil); vote != tt.vote {
+			t.Fatalf("%s: expected vote to be %t", tt.policy, tt.vote)
+		}
+		e, _ := watcher.Latest(m)
+		if e.State != tt.state {
+			t.Fatalf("%s: expected state %q, got %q", tt.policy, tt.state, e.State)
+		}
+		if tt.state == outbox.Held && !strings.HasPrefix(e.Reason, "unable to simulate execution") {
+			t.Fatalf("%s: unexpected reason %q", tt.policy, e.Reason)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/ChainBridge/chains"
+	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// FindDeposit looks up the deposit with the nonce to the destination chain and builds its message as the listener does
+func (c *Chain) FindDeposit(dest msg.ChainId, nonce msg.Nonce) (*chains.Deposit, error) {
+	return c.listener.findDeposit(dest, nonce)
+}
+
+// ProposalStatus reports the state of the proposal for a message on this chain
+func (c *Chain) ProposalStatus(m msg.Message) (*chains.Proposal, error) {
+	return c.writer.proposalStatus(m)
+}
+
+// depositMessages returns the messages the listener routes for the deposit logs
+func (l *listener) depositMessages(logs []ethtypes.Log) ([]msg.Message, error) {
+	collector := &chains.Collector{}
+	_, err := l.handleDepositLogs(logs, collector)
+	return collector.Messages, err
+}
+
+// findDeposit searches the deposit counts of the bridge in historical state for the block of a deposit, so an
+// archive node is required. Blocks before the start block are not searched.
+func (l *listener) findDeposit(dest msg.ChainId, nonce msg.Nonce) (*chains.Deposit, error) {
+	latest, err := l.conn.LatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	count, err := l.bridgeContract.DepositCounts(l.conn.CallOpts(), uint8(dest))
+	if err != nil {
+		return nil, err
+	}
+	if nonce == 0 || uint64(nonce) > count {
+		return nil, fmt.Errorf("no deposit with nonce %d to chain %d, the latest nonce is %d", nonce, dest, count)
+	}
+
+	// Search from the block before the start block, to tell deposits made before it apart
+	start := l.cfg.startBlock.Uint64()
+	from := start
+	if from > 0 {
+		from--
+	}
+	block, ok, err := chains.FirstBlock(from, latest.Uint64(), func(block uint64) (bool, error) {
+		count, err := l.bridgeContract.DepositCounts(&bind.CallOpts{BlockNumber: new(big.Int).SetUint64(block)}, uint8(dest))
+		return count >= uint64(nonce), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search deposit counts, an archive node is required: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("deposit with nonce %d to chain %d not found", nonce, dest)
+	}
+	if block < start {
+		return nil, fmt.Errorf("deposit with nonce %d to chain %d was made before the start block %d", nonce, dest, start)
+	}
+
+	number := new(big.Int).SetUint64(block)
+	logs, err := l.conn.FilterLogs(context.Background(), buildQuery(l.cfg.bridgeContract, utils.Deposit, number, number))
+	if err != nil {
+		return nil, fmt.Errorf("unable to Filter Logs: %w", err)
+	}
+	for _, log := range logs {
+		if msg.ChainId(log.Topics[1].Big().Uint64()) != dest || msg.Nonce(log.Topics[3].Big().Uint64()) != nonce {
+			continue
+		}
+		msgs, err := l.depositMessages([]ethtypes.Log{log})
+		if err != nil {
+			return nil, err
+		}
+		if len(msgs) == 0 {
+			return nil, fmt.Errorf("deposit in tx %s has an unrecognized handler", log.TxHash.Hex())
+		}
+		return &chains.Deposit{Message: msgs[0], Block: block, Tx: log.TxHash.Hex()}, nil
+	}
+	return nil, fmt.Errorf("deposit event with nonce %d to chain %d not found in block %d", nonce, dest, block)
+}
+
+// proposalStatus computes the data hash of a message as it is voted on and reports the proposal of the bridge
+func (w *writer) proposalStatus(m msg.Message) (*chains.Proposal, error) {
+	_, dataHash, err := w.proposalData(m)
+	if err != nil {
+		return nil, err
+	}
+	prop, err := w.bridgeContract.GetProposal(w.conn.CallOpts(), uint8(m.Source), uint64(m.DepositNonce), dataHash)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &chains.Proposal{
+		Data:         ethcommon.Hash(dataHash).Hex(),
+		Status:       utils.ProposalStatus(prop.Status).String(),
+		VotesFor:     addressStrings(prop.YesVotes),
+		VotesAgainst: addressStrings(prop.NoVotes),
+	}
+	if prop.Status == TransferredStatus {
+		status.Execution, err = w.executionTx(m, dataHash, prop.ProposedBlock)
+		if err != nil {
+			w.log.Warn("Unable to find execution of proposal", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+		}
+	}
+	return status, nil
+}
+
+// executionTx searches the proposal status in historical state for the block the proposal was executed in and
+// returns the hash of the transaction emitting the execution event
+func (w *writer) executionTx(m msg.Message, dataHash [32]byte, proposedBlock *big.Int) (string, error) {
+	latest, err := w.conn.LatestBlock()
+	if err != nil {
+		return "", err
+	}
+	block, ok, err := chains.FirstBlock(proposedBlock.Uint64(), latest.Uint64(), func(block uint64) (bool, error) {
+		prop, err := w.bridgeContract.GetProposal(&bind.CallOpts{BlockNumber: new(big.Int).SetUint64(block)}, uint8(m.Source), uint64(m.DepositNonce), dataHash)
+		return prop.Status == TransferredStatus, err
+	})
+	if err != nil || !ok {
+		return "", err
+	}
+
+	number := new(big.Int).SetUint64(block)
+	evts, err := w.conn.FilterLogs(context.Background(), buildQuery(w.cfg.bridgeContract, utils.ProposalEvent, number, number))
+	if err != nil {
+		return "", err
+	}
+	for _, evt := range evts {
+		if msg.ChainId(evt.Topics[1].Big().Uint64()) == m.Source &&
+			msg.Nonce(evt.Topics[2].Big().Uint64()) == m.DepositNonce &&
+			utils.IsExecuted(uint8(evt.Topics[3].Big().Uint64())) {
+			return evt.TxHash.Hex(), nil
+		}
+	}
+	return "", fmt.Errorf("execution event not found in block %d", block)
+}
+
+func addressStrings(addrs []ethcommon.Address) []string {
+	res := make([]string, len(addrs))
+	for i, addr := range addrs {
+		res[i] = addr.Hex()
+	}
+	return res
+}
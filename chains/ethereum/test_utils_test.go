@@ -4,18 +4,28 @@
 package ethereum
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/ChainSafe/ChainBridge/bindings/Bridge"
 	connection "github.com/ChainSafe/ChainBridge/connections/ethereum"
 	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
+	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
 	"github.com/ChainSafe/chainbridge-utils/keystore"
 	"github.com/ChainSafe/chainbridge-utils/msg"
 	"github.com/ChainSafe/log15"
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const TestEndpoint = "ws://localhost:8545"
@@ -50,6 +60,10 @@ func createConfig(name string, startBlock *big.Int, contracts *utils.DeployedCon
 		http:                   false,
 		startBlock:             startBlock,
 		blockConfirmations:     big.NewInt(3),
+		maxBlockRange:          big.NewInt(DefaultMaxBlockRange),
+		maxPriorityFeePerGas:   big.NewInt(DefaultMaxPriorityFeePerGas),
+		priorityFeePercentile:  DefaultPriorityFeePercentile,
+		simulationPolicy:       SimulationDisabled,
 	}
 
 	if contracts != nil {
@@ -70,7 +84,7 @@ func newTestLogger(name string) log15.Logger {
 
 func newLocalConnection(t *testing.T, cfg *Config) *connection.Connection {
 	kp := keystore.TestKeyRing.EthereumKeys[cfg.from]
-	conn := connection.NewConnection(TestEndpoint, false, kp, TestLogger, big.NewInt(DefaultGasLimit), big.NewInt(DefaultGasPrice), big.NewFloat(DefaultGasMultiplier))
+	conn := connection.NewConnection([]string{TestEndpoint}, false, kp, TestLogger, big.NewInt(DefaultGasLimit), big.NewInt(DefaultGasPrice), big.NewFloat(DefaultGasMultiplier))
 	err := conn.Connect()
 	if err != nil {
 		t.Fatal(err)
@@ -168,3 +182,146 @@ func createGenericDeposit(
 		t.Fatal(err)
 	}
 }
+
+// newTestEthMetrics returns ethereum metrics that are not registered, so they can be created by every test
+func newTestEthMetrics() *ethMetrics {
+	return &ethMetrics{
+		ChainReorgs:    prometheus.NewCounter(prometheus.CounterOpts{Name: "test_chain_reorgs"}),
+		LastReorgDepth: prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_last_reorg_depth"}),
+		TxsMined:       prometheus.NewCounter(prometheus.CounterOpts{Name: "test_txs_mined"}),
+		TxsReverted:    prometheus.NewCounter(prometheus.CounterOpts{Name: "test_txs_reverted"}),
+		TxsReplaced:    prometheus.NewCounter(prometheus.CounterOpts{Name: "test_txs_replaced"}),
+		TxsAbandoned:   prometheus.NewCounter(prometheus.CounterOpts{Name: "test_txs_abandoned"}),
+	}
+}
+
+// fakeNode serves the JSON-RPC methods used by the listener and the transaction tracker from memory, so they can be
+// tested without a node
+type fakeNode struct {
+	lock      sync.Mutex
+	headers   map[uint64]*ethtypes.Header
+	latest    uint64
+	logRanges [][2]uint64 // Block ranges queried for logs
+	receipts  map[common.Hash]*ethtypes.Receipt
+	nonce     uint64              // Confirmed nonce of every account
+	onLatest  func()              // Called with the lock held whenever the latest block is queried
+	onHeader  func(number uint64) // Called with the lock held before a block header is served
+}
+
+func newFakeNode() *fakeNode {
+	return &fakeNode{
+		headers:  make(map[uint64]*ethtypes.Header),
+		receipts: make(map[common.Hash]*ethtypes.Receipt),
+	}
+}
+
+// extend replaces the blocks from `from` to `to` with blocks of the fork, each linked to its parent. The latest block
+// becomes `to`. Must be called with the lock held once the node is in use.
+func (n *fakeNode) extend(from, to, fork uint64) {
+	for i := from; i <= to; i++ {
+		h := testHeader(int64(i), fork)
+		if parent, ok := n.headers[i-1]; ok && i > 0 {
+			h.ParentHash = parent.Hash()
+		}
+		n.headers[i] = h
+	}
+	n.latest = to
+}
+
+// include adds a receipt for the transaction
+func (n *fakeNode) include(tx *ethtypes.Transaction, status uint64) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.receipts[tx.Hash()] = &ethtypes.Receipt{Status: status, TxHash: tx.Hash(), BlockNumber: big.NewInt(1), Logs: []*ethtypes.Log{}}
+}
+
+func (n *fakeNode) queriedRanges() [][2]uint64 {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return append([][2]uint64{}, n.logRanges...)
+}
+
+// fakeEthService implements the eth namespace of the fake node
+type fakeEthService struct {
+	node *fakeNode
+}
+
+type fakeLogQuery struct {
+	FromBlock *hexutil.Big `json:"fromBlock"`
+	ToBlock   *hexutil.Big `json:"toBlock"`
+}
+
+func (s *fakeEthService) GetBlockByNumber(number rpc.BlockNumber, _ bool) (*ethtypes.Header, error) {
+	s.node.lock.Lock()
+	defer s.node.lock.Unlock()
+	if s.node.onHeader != nil {
+		s.node.onHeader(uint64(number))
+	}
+	return s.node.headers[uint64(number)], nil
+}
+
+func (s *fakeEthService) GetLogs(query fakeLogQuery) ([]ethtypes.Log, error) {
+	s.node.lock.Lock()
+	defer s.node.lock.Unlock()
+	s.node.logRanges = append(s.node.logRanges, [2]uint64{query.FromBlock.ToInt().Uint64(), query.ToBlock.ToInt().Uint64()})
+	return []ethtypes.Log{}, nil
+}
+
+func (s *fakeEthService) GetTransactionReceipt(hash common.Hash) (*ethtypes.Receipt, error) {
+	s.node.lock.Lock()
+	defer s.node.lock.Unlock()
+	return s.node.receipts[hash], nil
+}
+
+func (s *fakeEthService) GetTransactionCount(_ common.Address, _ string) (hexutil.Uint64, error) {
+	s.node.lock.Lock()
+	defer s.node.lock.Unlock()
+	return hexutil.Uint64(s.node.nonce), nil
+}
+
+var _ Connection = &fakeConnection{}
+
+// fakeConnection is a Connection to a fakeNode
+type fakeConnection struct {
+	node   *fakeNode
+	client *ethclient.Client
+	opts   *bind.TransactOpts
+}
+
+func newFakeConnection(t *testing.T, node *fakeNode) *fakeConnection {
+	server := rpc.NewServer()
+	err := server.RegisterName("eth", &fakeEthService{node: node})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	return &fakeConnection{
+		node:   node,
+		client: ethclient.NewClient(rpc.DialInProc(server)),
+		opts:   &bind.TransactOpts{From: AliceKp.CommonAddress(), GasPrice: big.NewInt(100)},
+	}
+}
+
+func (c *fakeConnection) Connect() error                            { return nil }
+func (c *fakeConnection) Keypair() *secp256k1.Keypair               { return AliceKp }
+func (c *fakeConnection) Opts() *bind.TransactOpts                  { return c.opts }
+func (c *fakeConnection) CallOpts() *bind.CallOpts                  { return &bind.CallOpts{} }
+func (c *fakeConnection) LockAndUpdateOpts() error                  { return nil }
+func (c *fakeConnection) UnlockOpts()                               {}
+func (c *fakeConnection) Client() *ethclient.Client                 { return c.client }
+func (c *fakeConnection) EnsureHasBytecode(_ common.Address) error  { return nil }
+func (c *fakeConnection) WaitForBlock(_ *big.Int, _ *big.Int) error { return nil }
+func (c *fakeConnection) Close()                                    {}
+
+func (c *fakeConnection) FilterLogs(ctx context.Context, query eth.FilterQuery) ([]ethtypes.Log, error) {
+	return c.client.FilterLogs(ctx, query)
+}
+
+func (c *fakeConnection) LatestBlock() (*big.Int, error) {
+	c.node.lock.Lock()
+	defer c.node.lock.Unlock()
+	if c.node.onLatest != nil {
+		c.node.onLatest()
+	}
+	return new(big.Int).SetUint64(c.node.latest), nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// Time between checks for a transaction receipt
+var TxReceiptPollInterval = time.Second * 5
+
+// Time a transaction may be pending before it is replaced with a higher fee
+var TxReplacementTimeout = time.Minute * 3
+
+// Percentage the fee of a replacement transaction is increased by. Nodes require at least 10%.
+var TxFeeBumpPercent int64 = 20
+
+var ErrMaxGasPriceReached = errors.New("fee already at max gas price")
+
+type txOutcome string
+
+const (
+	TxMined     txOutcome = "mined"     // The transaction was included and succeeded
+	TxReverted  txOutcome = "reverted"  // The transaction (or its replacement) was included but reverted
+	TxReplaced  txOutcome = "replaced"  // A replacement with a higher fee was included and succeeded
+	TxAbandoned txOutcome = "abandoned" // The transaction could not be replaced further or its nonce was used by another transaction
+)
+
+// resubmitFunc sends a transaction again using the provided opts
+type resubmitFunc func(opts *bind.TransactOpts) (*ethtypes.Transaction, error)
+
+// trackTx waits for the receipt of tx. If the transaction is still pending after TxReplacementTimeout it is resubmitted
+// with the same nonce and a bumped fee, up to maxGasPrice. The final outcome is logged and counted in the metrics.
+func (w *writer) trackTx(tx *ethtypes.Transaction, resubmit resubmitFunc, log log15.Logger) txOutcome {
+	outcome, receipt := w.waitForReceipt(tx, resubmit, log)
+	if w.ethMetrics != nil {
+		w.ethMetrics.txOutcome(outcome)
+	}
+
+	switch outcome {
+	case TxMined, TxReplaced:
+		log.Info("Transaction mined", "outcome", outcome, "tx", receipt.TxHash, "block", receipt.BlockNumber, "gasUsed", receipt.GasUsed)
+	case TxReverted:
+		log.Error("Transaction reverted", "outcome", outcome, "tx", receipt.TxHash, "block", receipt.BlockNumber, "gasUsed", receipt.GasUsed)
+	default:
+		log.Error("Transaction abandoned", "outcome", outcome, "tx", tx.Hash(), "nonce", tx.Nonce())
+	}
+	return outcome
+}
+
+// waitForReceipt polls for the receipt of tx and any of its replacements
+func (w *writer) waitForReceipt(tx *ethtypes.Transaction, resubmit resubmitFunc, log log15.Logger) (txOutcome, *ethtypes.Receipt) {
+	txs := []*ethtypes.Transaction{tx}
+	replaceAt := time.Now().Add(TxReplacementTimeout)
+
+	for {
+		select {
+		case <-w.stop:
+			return TxAbandoned, nil
+		case <-time.After(TxReceiptPollInterval):
+		}
+
+		// Any of the submitted transactions may be included
+		for i, t := range txs {
+			receipt, err := w.conn.Client().TransactionReceipt(context.Background(), t.Hash())
+			if err != nil {
+				continue
+			}
+			if receipt.Status == ethtypes.ReceiptStatusFailed {
+				return TxReverted, receipt
+			} else if i != 0 {
+				return TxReplaced, receipt
+			}
+			return TxMined, receipt
+		}
+
+		// If the nonce has been used and none of our transactions were included another transaction took its place
+		nonce, err := w.conn.Client().NonceAt(context.Background(), w.conn.Opts().From, nil)
+		if err == nil && nonce > tx.Nonce() && !w.anyTxIncluded(txs) {
+			log.Warn("Transaction nonce used by another transaction", "nonce", tx.Nonce())
+			return TxAbandoned, nil
+		}
+
+		if time.Now().Before(replaceAt) {
+			continue
+		}
+
+		replacement, err := w.replaceTx(txs[len(txs)-1], resubmit)
+		if err != nil {
+			log.Warn("Unable to replace pending transaction", "tx", txs[len(txs)-1].Hash(), "err", err)
+			return TxAbandoned, nil
+		}
+		log.Info("Replaced pending transaction", "old", txs[len(txs)-1].Hash(), "new", replacement.Hash(), "nonce", replacement.Nonce())
+		txs = append(txs, replacement)
+		replaceAt = time.Now().Add(TxReplacementTimeout)
+	}
+}
+
+// anyTxIncluded checks once more for receipts, as a transaction may have been included since the last check
+func (w *writer) anyTxIncluded(txs []*ethtypes.Transaction) bool {
+	for _, t := range txs {
+		if _, err := w.conn.Client().TransactionReceipt(context.Background(), t.Hash()); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceTx resubmits tx with the same nonce and bumped fees
+func (w *writer) replaceTx(tx *ethtypes.Transaction, resubmit resubmitFunc) (*ethtypes.Transaction, error) {
+	err := w.conn.LockAndUpdateOpts()
+	if err != nil {
+		return nil, err
+	}
+	defer w.conn.UnlockOpts()
+
+	opts := *w.conn.Opts()
+	opts.Nonce = new(big.Int).SetUint64(tx.Nonce())
+
+	if tx.Type() == ethtypes.DynamicFeeTxType {
+		feeCap := bumpFee(tx.GasFeeCap(), w.cfg.maxGasPrice)
+		if feeCap == nil {
+			return nil, ErrMaxGasPriceReached
+		}
+		// The tip must be bumped in full as well, otherwise the replacement is rejected as underpriced
+		tipCap := minBumpedFee(tx.GasTipCap())
+		if tipCap.Cmp(feeCap) == 1 {
+			return nil, ErrMaxGasPriceReached
+		}
+		opts.GasPrice = nil
+		opts.GasFeeCap = feeCap
+		opts.GasTipCap = tipCap
+	} else {
+		gasPrice := bumpFee(tx.GasPrice(), w.cfg.maxGasPrice)
+		if gasPrice == nil {
+			return nil, ErrMaxGasPriceReached
+		}
+		opts.GasPrice = gasPrice
+		opts.GasFeeCap = nil
+		opts.GasTipCap = nil
+	}
+
+	return resubmit(&opts)
+}
+
+// bumpFee increases fee by TxFeeBumpPercent, limited to max. Nil is returned if the fee is already at the limit.
+func bumpFee(fee, max *big.Int) *big.Int {
+	if fee.Cmp(max) >= 0 {
+		return nil
+	}
+	bumped := minBumpedFee(fee)
+	if bumped.Cmp(max) == 1 {
+		bumped.Set(max)
+	}
+	return bumped
+}
+
+// minBumpedFee returns fee increased by TxFeeBumpPercent, and by at least one
+func minBumpedFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+TxFeeBumpPercent))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(fee) == 0 {
+		bumped.Add(bumped, big.NewInt(1))
+	}
+	return bumped
+}
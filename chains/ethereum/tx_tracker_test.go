@@ -0,0 +1,215 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/outbox"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func setTrackerIntervals(t *testing.T, poll, replacement time.Duration) {
+	pollInterval, replacementTimeout := TxReceiptPollInterval, TxReplacementTimeout
+	t.Cleanup(func() {
+		TxReceiptPollInterval, TxReplacementTimeout = pollInterval, replacementTimeout
+	})
+	TxReceiptPollInterval, TxReplacementTimeout = poll, replacement
+}
+
+func newTrackerWriter(t *testing.T, node *fakeNode, maxGasPrice int64) *writer {
+	cfg := createConfig("alice", nil, nil)
+	cfg.maxGasPrice = big.NewInt(maxGasPrice)
+	stop := make(chan int)
+	t.Cleanup(func() { close(stop) })
+	w := NewWriter(newFakeConnection(t, node), cfg, TestLogger, stop, nil, nil)
+	w.setEthMetrics(newTestEthMetrics())
+	return w
+}
+
+func legacyTx(nonce uint64, gasPrice int64) *ethtypes.Transaction {
+	return ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(gasPrice), Gas: 21000})
+}
+
+// noResubmit fails the test if a replacement is attempted
+func noResubmit(t *testing.T) resubmitFunc {
+	return func(_ *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		t.Error("Unexpected replacement")
+		return nil, ErrMaxGasPriceReached
+	}
+}
+
+// assertCounted checks that only the counter of the outcome was incremented
+func assertCounted(t *testing.T, m *ethMetrics, outcome txOutcome) {
+	counters := map[txOutcome]prometheus.Counter{
+		TxMined:     m.TxsMined,
+		TxReverted:  m.TxsReverted,
+		TxReplaced:  m.TxsReplaced,
+		TxAbandoned: m.TxsAbandoned,
+	}
+	for o, c := range counters {
+		expected := 0.0
+		if o == outcome {
+			expected = 1
+		}
+		if res := testutil.ToFloat64(c); res != expected {
+			t.Errorf("Unexpected count of %s transactions. Expected: %v Got: %v", o, expected, res)
+		}
+	}
+}
+
+func dynamicFeeTx(nonce uint64, feeCap, tipCap int64) *ethtypes.Transaction {
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{Nonce: nonce, GasFeeCap: big.NewInt(feeCap), GasTipCap: big.NewInt(tipCap), Gas: 21000})
+}
+
+func TestReplaceTx_dynamicFee(t *testing.T) {
+	testCases := []struct {
+		name        string
+		maxGasPrice int64
+		feeCap      int64
+		tipCap      int64
+		err         error
+	}{
+		{"bumped", 1000, 120, 120, nil},
+		// The fee cap can only be raised to 110, leaving no room for the tip to be bumped in full
+		{"tip capped", 110, 0, 0, ErrMaxGasPriceReached},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := newTrackerWriter(t, newFakeNode(), tc.maxGasPrice)
+			resubmit := func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+				return dynamicFeeTx(opts.Nonce.Uint64(), opts.GasFeeCap.Int64(), opts.GasTipCap.Int64()), nil
+			}
+
+			replacement, err := w.replaceTx(dynamicFeeTx(5, 100, 100), resubmit)
+			if err != tc.err {
+				t.Fatalf("Expected error: %v Got: %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if replacement.GasFeeCap().Int64() != tc.feeCap || replacement.GasTipCap().Int64() != tc.tipCap {
+				t.Fatalf("Unexpected fee cap %s and tip cap %s", replacement.GasFeeCap(), replacement.GasTipCap())
+			}
+		})
+	}
+}
+
+func TestTrackTx_receipt(t *testing.T) {
+	setTrackerIntervals(t, time.Millisecond, time.Hour)
+
+	testCases := []struct {
+		name     string
+		status   uint64
+		expected txOutcome
+	}{
+		{"mined", ethtypes.ReceiptStatusSuccessful, TxMined},
+		{"reverted", ethtypes.ReceiptStatusFailed, TxReverted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node := newFakeNode()
+			w := newTrackerWriter(t, node, 1000)
+			tx := legacyTx(5, 100)
+
+			// The receipt is only available after a few polls
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				node.include(tx, tc.status)
+			}()
+			if outcome := w.trackTx(tx, noResubmit(t), TestLogger); outcome != tc.expected {
+				t.Fatalf("Expected: %s Got: %s", tc.expected, outcome)
+			}
+			assertCounted(t, w.ethMetrics, tc.expected)
+		})
+	}
+}
+
+func TestTrackTx_replacement(t *testing.T) {
+	setTrackerIntervals(t, time.Millisecond, 20*time.Millisecond)
+
+	node := newFakeNode()
+	w := newTrackerWriter(t, node, 1000)
+	tx := legacyTx(5, 100)
+
+	var replacements []*ethtypes.Transaction
+	resubmit := func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		replacement := legacyTx(opts.Nonce.Uint64(), opts.GasPrice.Int64())
+		replacements = append(replacements, replacement)
+		node.include(replacement, ethtypes.ReceiptStatusSuccessful)
+		return replacement, nil
+	}
+
+	if outcome := w.trackTx(tx, resubmit, TestLogger); outcome != TxReplaced {
+		t.Fatalf("Expected: %s Got: %s", TxReplaced, outcome)
+	}
+	if len(replacements) != 1 {
+		t.Fatalf("Expected 1 replacement, got %d", len(replacements))
+	}
+	// The replacement keeps the nonce and bumps the fee
+	if replacements[0].Nonce() != 5 || replacements[0].GasPrice().Int64() != 120 {
+		t.Fatalf("Unexpected replacement nonce %d and gas price %s", replacements[0].Nonce(), replacements[0].GasPrice())
+	}
+	assertCounted(t, w.ethMetrics, TxReplaced)
+}
+
+func TestTrackTx_abandoned(t *testing.T) {
+	t.Run("max gas price", func(t *testing.T) {
+		setTrackerIntervals(t, time.Millisecond, 10*time.Millisecond)
+		node := newFakeNode()
+		w := newTrackerWriter(t, node, 100)
+
+		if outcome := w.trackTx(legacyTx(5, 100), noResubmit(t), TestLogger); outcome != TxAbandoned {
+			t.Fatalf("Expected: %s Got: %s", TxAbandoned, outcome)
+		}
+		assertCounted(t, w.ethMetrics, TxAbandoned)
+	})
+
+	t.Run("nonce used", func(t *testing.T) {
+		setTrackerIntervals(t, time.Millisecond, time.Hour)
+		node := newFakeNode()
+		node.nonce = 6
+		w := newTrackerWriter(t, node, 1000)
+
+		if outcome := w.trackTx(legacyTx(5, 100), noResubmit(t), TestLogger); outcome != TxAbandoned {
+			t.Fatalf("Expected: %s Got: %s", TxAbandoned, outcome)
+		}
+		assertCounted(t, w.ethMetrics, TxAbandoned)
+	})
+}
+
+func TestTrackExecution_executedOnReceipt(t *testing.T) {
+	setTrackerIntervals(t, time.Millisecond, time.Hour)
+
+	node := newFakeNode()
+	w := newTrackerWriter(t, node, 1000)
+	watcher := outbox.NewWatcher(&outbox.EmptyOutbox{})
+	w.setOutbox(watcher)
+	m := msg.NewGenericTransfer(1, 0, 3, msg.ResourceIdFromSlice([]byte{1}), []byte{})
+	tx := legacyTx(5, 100)
+
+	done := make(chan struct{})
+	go func() {
+		w.trackExecution(m, nil, [32]byte{}, tx)
+		close(done)
+	}()
+
+	// Submitting the execution doesn't complete the message
+	if e, ok := watcher.Wait(m, 20*time.Millisecond, outbox.Executed); ok {
+		t.Fatalf("Message marked %s before the execution was included", e.State)
+	}
+	node.include(tx, ethtypes.ReceiptStatusSuccessful)
+	if _, ok := watcher.Wait(m, TestTimeout, outbox.Executed); !ok {
+		t.Fatal("Expected the message to be executed once the receipt is available")
+	}
+	<-done
+}
@@ -4,7 +4,10 @@
 package ethereum
 
 import (
+	"fmt"
+
 	"github.com/ChainSafe/ChainBridge/bindings/Bridge"
+	"github.com/ChainSafe/ChainBridge/outbox"
 	"github.com/ChainSafe/chainbridge-utils/core"
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/chainbridge-utils/msg"
@@ -26,6 +29,9 @@ type writer struct {
 	stop           <-chan int
 	sysErr         chan<- error // Reports fatal error to core
 	metrics        *metrics.ChainMetrics
+	ethMetrics     *ethMetrics
+	outbox         outbox.Outboxer // Persists the state of messages so they can be resumed after a restart
+	held           *heldMessages   // Retries of messages held due to failed simulations
 }
 
 // NewWriter creates and returns writer
@@ -37,11 +43,14 @@ func NewWriter(conn Connection, cfg *Config, log log15.Logger, stop <-chan int,
 		stop:    stop,
 		sysErr:  sysErr,
 		metrics: m,
+		outbox:  &outbox.EmptyOutbox{},
+		held:    newHeldMessages(),
 	}
 }
 
 func (w *writer) start() error {
 	w.log.Debug("Starting ethereum writer...")
+	go w.resumeUnfinished()
 	return nil
 }
 
@@ -50,10 +59,30 @@ func (w *writer) setContract(bridge *Bridge.Bridge) {
 	w.bridgeContract = bridge
 }
 
+// setOutbox sets the outbox used to persist message states
+func (w *writer) setOutbox(o outbox.Outboxer) {
+	w.outbox = o
+}
+
+// setEthMetrics sets the ethereum specific metrics
+func (w *writer) setEthMetrics(m *ethMetrics) {
+	w.ethMetrics = m
+}
+
 // ResolveMessage handles any given message based on type
 // A bool is returned to indicate failure/success, this should be ignored except for within tests.
 func (w *writer) ResolveMessage(m msg.Message) bool {
 	w.log.Info("Attempting to resolve message", "type", m.Type, "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce, "rId", m.ResourceId.Hex())
+	w.updateOutbox(m, outbox.Received, "")
+
+	if m.Type == msg.FungibleTransfer || m.Type == msg.NonFungibleTransfer {
+		err := validateRecipient(m)
+		if err != nil {
+			w.log.Warn("Parking message with invalid recipient", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+			w.updateOutbox(m, outbox.Held, err.Error())
+			return false
+		}
+	}
 
 	switch m.Type {
 	case msg.FungibleTransfer:
@@ -64,6 +93,28 @@ func (w *writer) ResolveMessage(m msg.Message) bool {
 		return w.createGenericDepositProposal(m)
 	default:
 		w.log.Error("Unknown message type received", "type", m.Type)
+		w.updateOutbox(m, outbox.Failed, fmt.Sprintf("unknown message type %s", m.Type))
 		return false
 	}
 }
+
+// updateOutbox records the state of a message, failures are only logged
+func (w *writer) updateOutbox(m msg.Message, state outbox.State, reason string) {
+	err := w.outbox.Update(m, state, reason)
+	if err != nil {
+		w.log.Error("Failed to update outbox", "src", m.Source, "nonce", m.DepositNonce, "state", state, "err", err)
+	}
+}
+
+// resumeUnfinished resumes all messages of the outbox that were not executed before the last shutdown
+func (w *writer) resumeUnfinished() {
+	for _, entry := range w.outbox.Unfinished() {
+		select {
+		case <-w.stop:
+			return
+		default:
+			w.log.Info("Resuming message from outbox", "src", entry.Message.Source, "nonce", entry.Message.DepositNonce, "state", entry.State)
+			w.resumeMessage(entry.Message)
+		}
+	}
+}
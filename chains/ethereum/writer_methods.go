@@ -6,12 +6,17 @@ package ethereum
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/ChainSafe/ChainBridge/outbox"
 	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
 	"github.com/ChainSafe/chainbridge-utils/msg"
 	log "github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 )
 
 // Number of blocks to wait for an finalization event
@@ -72,12 +77,16 @@ func (w *writer) shouldVote(m msg.Message, dataHash [32]byte) bool {
 	// Check if proposal has passed and skip if Passed or Transferred
 	if w.proposalIsComplete(m.Source, m.DepositNonce, dataHash) {
 		w.log.Info("Proposal complete, not voting", "src", m.Source, "nonce", m.DepositNonce)
+		if w.proposalIsFinalized(m.Source, m.DepositNonce, dataHash) {
+			w.updateOutbox(m, outbox.Executed, "")
+		}
 		return false
 	}
 
 	// Check if relayer has previously voted
 	if w.hasVoted(m.Source, m.DepositNonce, dataHash) {
 		w.log.Info("Relayer has already voted, not voting", "src", m.Source, "nonce", m.DepositNonce)
+		w.updateOutbox(m, outbox.Voted, "")
 		return false
 	}
 
@@ -89,8 +98,14 @@ func (w *writer) shouldVote(m msg.Message, dataHash [32]byte) bool {
 func (w *writer) createErc20Proposal(m msg.Message) bool {
 	w.log.Info("Creating erc20 proposal", "src", m.Source, "nonce", m.DepositNonce)
 
-	data := ConstructErc20ProposalData(m.Payload[0].([]byte), m.Payload[1].([]byte))
-	dataHash := utils.Hash(append(w.cfg.erc20HandlerContract.Bytes(), data...))
+	data, err := w.erc20ProposalData(m)
+	if err != nil {
+		w.log.Error("Unable to create erc20 proposal", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+		w.updateOutbox(m, outbox.Failed, err.Error())
+		return false
+	}
+	handler := w.handlerAddress(m.ResourceId, w.cfg.erc20HandlerContract)
+	dataHash := utils.Hash(append(handler.Bytes(), data...))
 
 	if !w.shouldVote(m, dataHash) {
 		if w.proposalIsPassed(m.Source, m.DepositNonce, dataHash) {
@@ -102,6 +117,11 @@ func (w *writer) createErc20Proposal(m msg.Message) bool {
 		}
 	}
 
+	// Make sure the proposal can be executed before voting for it
+	if !w.checkSimulation(m, handler, data) {
+		return false
+	}
+
 	// Capture latest block so when know where to watch from
 	latestBlock, err := w.conn.LatestBlock()
 	if err != nil {
@@ -117,13 +137,47 @@ func (w *writer) createErc20Proposal(m msg.Message) bool {
 	return true
 }
 
+// validateRecipient checks that the recipient of a token transfer is an address. Tokens sent to a recipient of any
+// other length would be lost or the proposal could never be executed.
+func validateRecipient(m msg.Message) error {
+	if len(m.Payload) < 2 {
+		return fmt.Errorf("invalid recipient: payload has %d elements", len(m.Payload))
+	}
+	recipient, ok := m.Payload[1].([]byte)
+	if !ok {
+		return fmt.Errorf("invalid recipient: expected bytes, got %T", m.Payload[1])
+	}
+	if len(recipient) != ethcommon.AddressLength {
+		return fmt.Errorf("invalid recipient %#x: expected a %d byte address, got %d bytes", recipient, ethcommon.AddressLength, len(recipient))
+	}
+	return nil
+}
+
+// erc20ProposalData returns the erc20 proposal data of the message, with the amount scaled to the decimals of the
+// resource on this chain
+func (w *writer) erc20ProposalData(m msg.Message) ([]byte, error) {
+	amount := big.NewInt(0).SetBytes(m.Payload[0].([]byte))
+	scaled, dust, err := w.cfg.decimals.ScaleAmount(m.ResourceId, amount, w.cfg.dustPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if dust.Sign() != 0 {
+		w.log.Warn("Rounded down erc20 amount, dust remains on the source chain", "src", m.Source, "nonce", m.DepositNonce, "amount", amount, "scaled", scaled, "dust", dust)
+	}
+	if scaled.BitLen() > 256 {
+		return nil, fmt.Errorf("amount %s exceeds uint256", scaled)
+	}
+	return ConstructErc20ProposalData(scaled.Bytes(), m.Payload[1].([]byte)), nil
+}
+
 // createErc721Proposal creates an Erc721 proposal.
 // Returns true if the proposal is succesfully created or is complete
 func (w *writer) createErc721Proposal(m msg.Message) bool {
 	w.log.Info("Creating erc721 proposal", "src", m.Source, "nonce", m.DepositNonce)
 
 	data := ConstructErc721ProposalData(m.Payload[0].([]byte), m.Payload[1].([]byte), m.Payload[2].([]byte))
-	dataHash := utils.Hash(append(w.cfg.erc721HandlerContract.Bytes(), data...))
+	handler := w.handlerAddress(m.ResourceId, w.cfg.erc721HandlerContract)
+	dataHash := utils.Hash(append(handler.Bytes(), data...))
 
 	if !w.shouldVote(m, dataHash) {
 		if w.proposalIsPassed(m.Source, m.DepositNonce, dataHash) {
@@ -135,6 +189,11 @@ func (w *writer) createErc721Proposal(m msg.Message) bool {
 		}
 	}
 
+	// Make sure the proposal can be executed before voting for it
+	if !w.checkSimulation(m, handler, data) {
+		return false
+	}
+
 	// Capture latest block so we know where to watch from
 	latestBlock, err := w.conn.LatestBlock()
 	if err != nil {
@@ -157,7 +216,8 @@ func (w *writer) createGenericDepositProposal(m msg.Message) bool {
 
 	metadata := m.Payload[0].([]byte)
 	data := ConstructGenericProposalData(metadata)
-	toHash := append(w.cfg.genericHandlerContract.Bytes(), data...)
+	handler := w.handlerAddress(m.ResourceId, w.cfg.genericHandlerContract)
+	toHash := append(handler.Bytes(), data...)
 	dataHash := utils.Hash(toHash)
 
 	if !w.shouldVote(m, dataHash) {
@@ -170,6 +230,11 @@ func (w *writer) createGenericDepositProposal(m msg.Message) bool {
 		}
 	}
 
+	// Make sure the proposal can be executed before voting for it
+	if !w.checkSimulation(m, handler, data) {
+		return false
+	}
+
 	// Capture latest block so when know where to watch from
 	latestBlock, err := w.conn.LatestBlock()
 	if err != nil {
@@ -213,7 +278,7 @@ func (w *writer) watchThenExecute(m msg.Message, data []byte, dataHash [32]byte,
 
 			// query for logs
 			query := buildQuery(w.cfg.bridgeContract, utils.ProposalEvent, latestBlock, latestBlock)
-			evts, err := w.conn.Client().FilterLogs(context.Background(), query)
+			evts, err := w.conn.FilterLogs(context.Background(), query)
 			if err != nil {
 				w.log.Error("Failed to fetch logs", "err", err)
 				return
@@ -269,6 +334,8 @@ func (w *writer) voteProposal(m msg.Message, dataHash [32]byte) {
 				if w.metrics != nil {
 					w.metrics.VotesSubmitted.Inc()
 				}
+				w.updateOutbox(m, outbox.Voted, "")
+				go w.trackVote(m, dataHash, tx)
 				return
 			} else if err.Error() == ErrNonceTooLow.Error() || err.Error() == ErrTxUnderpriced.Error() {
 				w.log.Debug("Nonce too low, will retry")
@@ -286,6 +353,7 @@ func (w *writer) voteProposal(m msg.Message, dataHash [32]byte) {
 		}
 	}
 	w.log.Error("Submission of Vote transaction failed", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce)
+	w.updateOutbox(m, outbox.Failed, "vote submission failed")
 	w.sysErr <- ErrFatalTx
 }
 
@@ -313,6 +381,7 @@ func (w *writer) executeProposal(m msg.Message, data []byte, dataHash [32]byte)
 
 			if err == nil {
 				w.log.Info("Submitted proposal execution", "tx", tx.Hash(), "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+				go w.trackExecution(m, data, dataHash, tx)
 				return
 			} else if err.Error() == ErrNonceTooLow.Error() || err.Error() == ErrTxUnderpriced.Error() {
 				w.log.Error("Nonce too low, will retry")
@@ -326,10 +395,110 @@ func (w *writer) executeProposal(m msg.Message, data []byte, dataHash [32]byte)
 			// but there is no need to retry
 			if w.proposalIsFinalized(m.Source, m.DepositNonce, dataHash) {
 				w.log.Info("Proposal finalized on chain", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+				w.updateOutbox(m, outbox.Executed, "")
 				return
 			}
 		}
 	}
 	w.log.Error("Submission of Execute transaction failed", "source", m.Source, "dest", m.Destination, "depositNonce", m.DepositNonce)
+	w.updateOutbox(m, outbox.Failed, "execution submission failed")
 	w.sysErr <- ErrFatalTx
 }
+
+// trackVote waits for the vote transaction to be included, replacing it if it is stuck
+func (w *writer) trackVote(m msg.Message, dataHash [32]byte, tx *ethtypes.Transaction) {
+	resubmit := func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return w.bridgeContract.VoteProposal(opts, uint8(m.Source), uint64(m.DepositNonce), m.ResourceId, dataHash)
+	}
+	outcome := w.trackTx(tx, resubmit, w.log.New("action", "vote", "src", m.Source, "nonce", m.DepositNonce))
+	if outcome == TxReverted && !w.proposalIsComplete(m.Source, m.DepositNonce, dataHash) {
+		w.log.Error("Vote reverted while proposal is still open", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+	}
+}
+
+// trackExecution waits for the execution transaction to be included, replacing it if it is stuck. The message is
+// marked Executed once the transaction, or another relayer's execution, is included. Abandoned executions are left
+// Voted so they are resumed after a restart.
+func (w *writer) trackExecution(m msg.Message, data []byte, dataHash [32]byte, tx *ethtypes.Transaction) {
+	resubmit := func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return w.bridgeContract.ExecuteProposal(opts, uint8(m.Source), uint64(m.DepositNonce), data, m.ResourceId)
+	}
+	outcome := w.trackTx(tx, resubmit, w.log.New("action", "execute", "src", m.Source, "nonce", m.DepositNonce))
+	switch {
+	case outcome == TxMined || outcome == TxReplaced:
+		w.updateOutbox(m, outbox.Executed, "")
+	case w.proposalIsFinalized(m.Source, m.DepositNonce, dataHash):
+		w.log.Info("Proposal finalized on chain", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+		w.updateOutbox(m, outbox.Executed, "")
+	case outcome == TxReverted:
+		w.log.Error("Execution reverted while proposal is not finalized", "src", m.Source, "dst", m.Destination, "nonce", m.DepositNonce)
+		w.updateOutbox(m, outbox.Failed, "execution reverted")
+	}
+}
+
+// proposalData returns the proposal data for the message and the hash voted on
+func (w *writer) proposalData(m msg.Message) ([]byte, [32]byte, error) {
+	var data []byte
+	var handler ethcommon.Address
+	switch m.Type {
+	case msg.FungibleTransfer:
+		var err error
+		data, err = w.erc20ProposalData(m)
+		if err != nil {
+			return nil, [32]byte{}, err
+		}
+		handler = w.cfg.erc20HandlerContract
+	case msg.NonFungibleTransfer:
+		data = ConstructErc721ProposalData(m.Payload[0].([]byte), m.Payload[1].([]byte), m.Payload[2].([]byte))
+		handler = w.cfg.erc721HandlerContract
+	case msg.GenericTransfer:
+		data = ConstructGenericProposalData(m.Payload[0].([]byte))
+		handler = w.cfg.genericHandlerContract
+	default:
+		return nil, [32]byte{}, fmt.Errorf("unknown message type %s", m.Type)
+	}
+	return data, utils.Hash(append(w.handlerAddress(m.ResourceId, handler).Bytes(), data...)), nil
+}
+
+// handlerAddress returns the handler contract the bridge uses for the resource ID. The configured handler of the
+// message type is returned if the lookup fails.
+func (w *writer) handlerAddress(rId msg.ResourceId, fallback ethcommon.Address) ethcommon.Address {
+	addr, err := w.bridgeContract.ResourceIDToHandlerAddress(w.conn.CallOpts(), rId)
+	if err != nil {
+		w.log.Warn("Failed to get handler from resource ID, using configured handler", "rId", rId.Hex(), "err", err)
+		return fallback
+	}
+	if addr == utils.ZeroAddress {
+		return fallback
+	}
+	return addr
+}
+
+// resumeMessage continues processing a message loaded from the outbox. Proposals that passed are executed, proposals
+// this relayer already voted on are watched for finalization and all others are resolved as new messages.
+func (w *writer) resumeMessage(m msg.Message) {
+	data, dataHash, err := w.proposalData(m)
+	if err != nil {
+		w.log.Error("Unable to resume message", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+		w.updateOutbox(m, outbox.Failed, err.Error())
+		return
+	}
+
+	// Capture latest block before checking the proposal so no finalization event is missed
+	latestBlock, err := w.conn.LatestBlock()
+	if err != nil {
+		w.log.Error("Unable to fetch latest block", "err", err)
+		return
+	}
+
+	if w.proposalIsFinalized(m.Source, m.DepositNonce, dataHash) {
+		w.log.Info("Proposal already finalized", "src", m.Source, "nonce", m.DepositNonce)
+		w.updateOutbox(m, outbox.Executed, "")
+	} else if w.proposalIsPassed(m.Source, m.DepositNonce, dataHash) {
+		w.executeProposal(m, data, dataHash)
+	} else if w.hasVoted(m.Source, m.DepositNonce, dataHash) {
+		go w.watchThenExecute(m, data, dataHash, latestBlock)
+	} else {
+		w.ResolveMessage(m)
+	}
+}
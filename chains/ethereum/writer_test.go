@@ -4,12 +4,15 @@
 package ethereum
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
 
 	"github.com/ChainSafe/ChainBridge/bindings/Bridge"
+	"github.com/ChainSafe/ChainBridge/chains"
 	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
 	ethtest "github.com/ChainSafe/ChainBridge/shared/ethereum/testing"
 	"github.com/ChainSafe/chainbridge-utils/msg"
@@ -298,3 +301,88 @@ func TestDuplicateMessage(t *testing.T) {
 	}
 
 }
+
+func TestBumpFee(t *testing.T) {
+	testCases := []struct {
+		fee      int64
+		max      int64
+		expected *big.Int
+	}{
+		{100, 1000, big.NewInt(120)},
+		{100, 110, big.NewInt(110)},
+		{110, 110, nil},
+		{0, 10, big.NewInt(1)},
+	}
+
+	for _, tc := range testCases {
+		bumped := bumpFee(big.NewInt(tc.fee), big.NewInt(tc.max))
+		if (bumped == nil) != (tc.expected == nil) || (bumped != nil && bumped.Cmp(tc.expected) != 0) {
+			t.Errorf("Unexpected bumped fee for %d (max %d). Expected: %v Got: %v", tc.fee, tc.max, tc.expected, bumped)
+		}
+	}
+}
+
+func TestWriter_erc20ProposalData(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{0x01})
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes()
+	w := &writer{
+		cfg: Config{
+			decimals:   chains.ResourceDecimals{rId: {Source: 12, Destination: 18}},
+			dustPolicy: chains.RejectDust,
+		},
+		log: log15.Root(),
+	}
+
+	m := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(5), rId, recipient)
+	data, err := w.erc20ProposalData(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ConstructErc20ProposalData(big.NewInt(5000000).Bytes(), recipient)
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("Unexpected proposal data.\n\tExpected: %x\n\tGot: %x", expected, data)
+	}
+
+	w.cfg.decimals[rId] = chains.Decimals{Source: 18, Destination: 12}
+	m = msg.NewFungibleTransfer(1, 2, 3, big.NewInt(5000001), rId, recipient)
+	_, err = w.erc20ProposalData(m)
+	var dustErr *chains.DustError
+	if !errors.As(err, &dustErr) {
+		t.Fatalf("Expected DustError, got %v", err)
+	}
+
+	w.cfg.dustPolicy = chains.RoundDust
+	data, err = w.erc20ProposalData(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = ConstructErc20ProposalData(big.NewInt(5).Bytes(), recipient)
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("Unexpected proposal data.\n\tExpected: %x\n\tGot: %x", expected, data)
+	}
+}
+
+func TestValidateRecipient(t *testing.T) {
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes()
+	valid := []msg.Message{
+		msg.NewFungibleTransfer(1, 2, 3, big.NewInt(5), msg.ResourceId{}, recipient),
+		msg.NewNonFungibleTransfer(1, 2, 3, msg.ResourceId{}, big.NewInt(5), recipient, nil),
+	}
+	for _, m := range valid {
+		if err := validateRecipient(m); err != nil {
+			t.Errorf("Unexpected error for %s: %s", m.Type, err)
+		}
+	}
+
+	invalid := []msg.Message{
+		msg.NewFungibleTransfer(1, 2, 3, big.NewInt(5), msg.ResourceId{}, append(recipient, 0x00)),
+		msg.NewFungibleTransfer(1, 2, 3, big.NewInt(5), msg.ResourceId{}, make([]byte, 32)),
+		msg.NewNonFungibleTransfer(1, 2, 3, msg.ResourceId{}, big.NewInt(5), []byte("5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"), nil),
+		{Type: msg.FungibleTransfer, Payload: []interface{}{big.NewInt(5).Bytes()}},
+	}
+	for _, m := range invalid {
+		if err := validateRecipient(m); err == nil {
+			t.Errorf("Expected recipient %v to be rejected", m.Payload)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+// Deposit is a transfer found on its source chain and the message the listener routes for it
+type Deposit struct {
+	Message msg.Message
+	Block   uint64
+	Tx      string // Hash of the transaction making the deposit, empty if it is not known
+}
+
+// Proposal is the state of the proposal for a deposit on the destination chain
+type Proposal struct {
+	Data         string   // Data hash voted on by ethereum relayers, the proposed call on substrate chains
+	Status       string   // Status of the proposal, inactive if no relayer voted yet
+	VotesFor     []string // Relayers that voted for the proposal
+	VotesAgainst []string // Relayers that voted against the proposal
+	Execution    string   // Transaction or block executing the proposal, empty if it was not found
+}
+
+// Collector is a Router keeping the messages sent to it, so the messages a listener builds can be inspected
+type Collector struct {
+	Messages []msg.Message
+}
+
+func (c *Collector) Send(m msg.Message) error {
+	c.Messages = append(c.Messages, m)
+	return nil
+}
+
+// FirstBlock returns the first block between from and to (inclusive) for which reached is true, assuming it stays
+// true for all later blocks. False is returned if it is not reached by block to.
+func FirstBlock(from, to uint64, reached func(block uint64) (bool, error)) (uint64, bool, error) {
+	if from > to {
+		return 0, false, nil
+	}
+	ok, err := reached(to)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	for from < to {
+		mid := from + (to-from)/2
+		ok, err = reached(mid)
+		if err != nil {
+			return 0, false, err
+		}
+		if ok {
+			to = mid
+		} else {
+			from = mid + 1
+		}
+	}
+	return to, true, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFirstBlock(t *testing.T) {
+	testCases := []struct {
+		from, to uint64
+		first    uint64
+		found    bool
+	}{
+		{10, 20, 15, true},
+		{10, 20, 10, true},
+		{10, 20, 5, true},
+		{10, 20, 20, true},
+		{10, 20, 21, false},
+		{15, 15, 15, true},
+		{20, 10, 15, false},
+	}
+
+	for _, tc := range testCases {
+		queries := 0
+		block, ok, err := FirstBlock(tc.from, tc.to, func(block uint64) (bool, error) {
+			queries++
+			return block >= tc.first, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := tc.first
+		if expected < tc.from {
+			expected = tc.from
+		}
+		if ok != tc.found || (ok && block != expected) {
+			t.Errorf("Searching %d in [%d, %d]. Got: %d, %t Expected: %d, %t", tc.first, tc.from, tc.to, block, ok, expected, tc.found)
+		}
+		if queries > 6 {
+			t.Errorf("Searching %d in [%d, %d] took %d queries", tc.first, tc.from, tc.to, queries)
+		}
+	}
+
+	queryErr := errors.New("pruned state")
+	_, _, err := FirstBlock(0, 100, func(block uint64) (bool, error) {
+		if block < 50 {
+			return false, queryErr
+		}
+		return true, nil
+	})
+	if !errors.Is(err, queryErr) {
+		t.Fatalf("Expected query error, got %v", err)
+	}
+}
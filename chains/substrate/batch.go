@@ -0,0 +1,110 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"time"
+
+	"github.com/ChainSafe/log15"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+var DefaultBatchWindow = 2 * time.Second
+var DefaultMaxBatchSize = 50
+
+type batchSubmitFunc func(calls []types.Call) ([]error, error)
+
+type pendingCall struct {
+	call   types.Call
+	result chan error
+}
+
+// callBatcher collects calls that arrive within a window and submits them together as one extrinsic
+type callBatcher struct {
+	submit  batchSubmitFunc
+	log     log15.Logger
+	window  time.Duration
+	maxSize int
+	calls   chan *pendingCall
+	stop    <-chan int
+}
+
+func newCallBatcher(submit batchSubmitFunc, log log15.Logger, window time.Duration, maxSize int, stop <-chan int) *callBatcher {
+	return &callBatcher{
+		submit:  submit,
+		log:     log,
+		window:  window,
+		maxSize: maxSize,
+		calls:   make(chan *pendingCall),
+		stop:    stop,
+	}
+}
+
+func (b *callBatcher) start() {
+	go b.run()
+}
+
+// add queues a call and blocks until the outcome of the batch containing it is known
+func (b *callBatcher) add(call types.Call) error {
+	p := &pendingCall{call: call, result: make(chan error, 1)}
+	select {
+	case b.calls <- p:
+	case <-b.stop:
+		return TerminatedError
+	}
+
+	select {
+	case err := <-p.result:
+		return err
+	case <-b.stop:
+		return TerminatedError
+	}
+}
+
+// run collects calls until the window after the first call elapses or the batch is full, then submits them
+func (b *callBatcher) run() {
+	for {
+		var batch []*pendingCall
+		select {
+		case <-b.stop:
+			return
+		case p := <-b.calls:
+			batch = append(batch, p)
+		}
+
+		timer := time.NewTimer(b.window)
+	collect:
+		for len(batch) < b.maxSize {
+			select {
+			case p := <-b.calls:
+				batch = append(batch, p)
+			case <-timer.C:
+				break collect
+			case <-b.stop:
+				timer.Stop()
+				return
+			}
+		}
+		timer.Stop()
+
+		b.submitBatch(batch)
+	}
+}
+
+func (b *callBatcher) submitBatch(batch []*pendingCall) {
+	calls := make([]types.Call, len(batch))
+	for i, p := range batch {
+		calls[i] = p.call
+	}
+
+	b.log.Debug("Submitting batch", "calls", len(calls))
+	results, err := b.submit(calls)
+	for i, p := range batch {
+		if err != nil {
+			p.result <- err
+		} else {
+			p.result <- results[i]
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func TestCallBatcher(t *testing.T) {
+	var lock sync.Mutex
+	var batches [][]types.Call
+	failure := errors.New("failed")
+	submit := func(calls []types.Call) ([]error, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		batches = append(batches, calls)
+		res := make([]error, len(calls))
+		for i, call := range calls {
+			if call.Args[0] == 2 {
+				res[i] = failure
+			}
+		}
+		return res, nil
+	}
+
+	stop := make(chan int)
+	defer close(stop)
+	b := newCallBatcher(submit, AliceTestLogger, 100*time.Millisecond, 3, stop)
+	b.start()
+
+	results := make([]error, 5)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = b.add(types.Call{Args: []byte{byte(i)}})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range results {
+		if i == 2 && err != failure {
+			t.Fatalf("expected call 2 to fail, got %v", err)
+		} else if i != 2 && err != nil {
+			t.Fatalf("expected call %d to succeed, got %s", i, err)
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 2 {
+		t.Fatalf("expected batches of 3 and 2 calls, got %d batches", len(batches))
+	}
+}
+
+func TestCallBatcherStop(t *testing.T) {
+	stop := make(chan int)
+	b := newCallBatcher(func(calls []types.Call) ([]error, error) { return nil, nil }, AliceTestLogger, time.Second, 3, stop)
+	close(stop)
+
+	err := b.add(types.Call{})
+	if err != TerminatedError {
+		t.Fatalf("expected TerminatedError, got %v", err)
+	}
+}
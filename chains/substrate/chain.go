@@ -14,16 +14,23 @@ It also handles state queries. The connection is shared by the writer and listen
 
 Listener
 
-The substrate listener polls blocks and parses the associated events for the three transfer types. It then forwards these into the router.
+The substrate listener polls blocks, or follows the finalized heads announced by the node, and parses the associated events for the three transfer types. It then forwards these into the router.
 
 Writer
 
 As the writer receives messages from the router, it constructs proposals. If a proposal is still active, the writer will attempt to vote on it. Resource IDs are resolved to method name on-chain, which are then used in the proposals when constructing the resulting Call struct.
 
+The state of each message is recorded in an outbox. Messages that were not voted on or completed before a restart are resolved again when the writer starts.
+
+Runtime profiles
+
+Chains differ in the transfer types they support and in the arguments of the calls executing transfers. These differences are described by a Profile, which is selected with the runtime option. Profiles are provided for the ChainSafe example pallet, Acala and Karura.
+
 */
 package substrate
 
 import (
+	"github.com/ChainSafe/ChainBridge/outbox"
 	"github.com/ChainSafe/chainbridge-utils/blockstore"
 	"github.com/ChainSafe/chainbridge-utils/core"
 	"github.com/ChainSafe/chainbridge-utils/crypto/sr25519"
@@ -31,6 +38,7 @@ import (
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/chainbridge-utils/msg"
 	"github.com/ChainSafe/log15"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/signature"
 )
 
 var _ core.Chain = &Chain{}
@@ -41,6 +49,7 @@ type Chain struct {
 	listener *listener         // The listener of this chain
 	writer   *writer           // The writer of the chain
 	stop     chan<- int
+	relayed  *outbox.Watcher // Tracks messages passed to the writer by Relay, nil until Relay is called
 }
 
 // checkBlockstore queries the blockstore for the latest known block. If the latest block is
@@ -79,10 +88,37 @@ func InitializeChain(cfg *core.ChainConfig, logger log15.Logger, sysErr chan<- e
 		}
 	}
 
+	ob, err := outbox.NewOutbox(cfg.BlockstorePath, cfg.Id, kp.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	return newChain(cfg, krp, startBlock, bs, ob, logger, sysErr, m)
+}
+
+// InspectChain connects to the chain to look up its deposits and proposals. No key is loaded, so no keystore is
+// required, and neither the blockstore nor the outbox are loaded. The returned chain must not be started.
+func InspectChain(cfg *core.ChainConfig, logger log15.Logger) (*Chain, error) {
+	c, err := newChain(cfg, &signature.KeyringPair{}, parseStartBlock(cfg), &blockstore.EmptyStore{}, &outbox.EmptyOutbox{}, logger, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = c.listener.registerSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newChain connects to the chain and sets up the listener and writer
+func newChain(cfg *core.ChainConfig, krp *signature.KeyringPair, startBlock uint64, bs blockstore.Blockstorer, ob outbox.Outboxer, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics) (*Chain, error) {
 	stop := make(chan int)
 	// Setup connection
 	conn := NewConnection(cfg.Endpoint, cfg.Name, krp, logger, stop, sysErr)
-	err = conn.Connect()
+	conn.setWaitForFinality(parseWaitForFinality(cfg))
+	conn.setMortalPeriod(parseMortalPeriod(cfg))
+	conn.setTip(parseTip(cfg))
+	err := conn.Connect()
 	if err != nil {
 		return nil, err
 	}
@@ -101,10 +137,23 @@ func InitializeChain(cfg *core.ChainConfig, logger log15.Logger, sysErr chan<- e
 	}
 
 	ue := parseUseExtended(cfg)
+	profile := parseProfile(cfg)
+	profile.register()
 
 	// Setup listener & writer
 	l := NewListener(conn, cfg.Name, cfg.Id, startBlock, logger, bs, stop, sysErr, m)
+	l.setSubscribe(parseSubscribeFinalizedHeads(cfg))
+	l.setProfile(profile)
 	w := NewWriter(conn, logger, sysErr, m, ue)
+	w.setProfile(profile)
+	w.setOutbox(ob)
+	w.setDecimals(parseDecimals(cfg), parseDustPolicy(cfg))
+	w.setDecodeSS58(parseDecodeSS58(cfg))
+
+	if parseBatchVotes(cfg) {
+		w.setBatcher(newCallBatcher(conn.SubmitBatch, logger, parseBatchWindow(cfg), parseMaxBatchSize(cfg), stop))
+	}
+
 	return &Chain{
 		cfg:      cfg,
 		conn:     conn,
@@ -119,6 +168,12 @@ func (c *Chain) Start() error {
 	if err != nil {
 		return err
 	}
+
+	err = c.writer.start()
+	if err != nil {
+		return err
+	}
+
 	c.conn.log.Debug("Successfully started chain", "chainId", c.cfg.Id)
 	return nil
 }
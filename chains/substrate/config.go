@@ -4,8 +4,13 @@
 package substrate
 
 import (
+	"fmt"
+	"math/big"
 	"strconv"
+	"time"
 
+	"github.com/ChainSafe/ChainBridge/chains"
+	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
 	"github.com/ChainSafe/chainbridge-utils/core"
 )
 
@@ -30,3 +35,124 @@ func parseUseExtended(cfg *core.ChainConfig) bool {
 	}
 	return false
 }
+
+func parseWaitForFinality(cfg *core.ChainConfig) bool {
+	if b, ok := cfg.Opts["waitForFinality"]; ok {
+		res, err := strconv.ParseBool(b)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+	return false
+}
+
+func parseMortalPeriod(cfg *core.ChainConfig) uint64 {
+	if period, ok := cfg.Opts["mortalPeriod"]; ok {
+		res, err := strconv.ParseUint(period, 10, 32)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+	return utils.DefaultMortalPeriod
+}
+
+func parseTip(cfg *core.ChainConfig) *big.Int {
+	if tip, ok := cfg.Opts["tip"]; ok {
+		res, ok := big.NewInt(0).SetString(tip, 10)
+		if !ok || res.Sign() < 0 {
+			panic(fmt.Sprintf("unable to parse tip %s", tip))
+		}
+		return res
+	}
+	return big.NewInt(0)
+}
+
+func parseProfile(cfg *core.ChainConfig) *Profile {
+	if name, ok := cfg.Opts["runtime"]; ok {
+		p, err := lookupProfile(name)
+		if err != nil {
+			panic(err)
+		}
+		return p
+	}
+	return DefaultProfile
+}
+
+func parseSubscribeFinalizedHeads(cfg *core.ChainConfig) bool {
+	if b, ok := cfg.Opts["subscribeFinalizedHeads"]; ok {
+		res, err := strconv.ParseBool(b)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+	return false
+}
+
+func parseBatchVotes(cfg *core.ChainConfig) bool {
+	if b, ok := cfg.Opts["batchVotes"]; ok {
+		res, err := strconv.ParseBool(b)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+	return false
+}
+
+func parseBatchWindow(cfg *core.ChainConfig) time.Duration {
+	if w, ok := cfg.Opts["batchWindow"]; ok {
+		res, err := time.ParseDuration(w)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+	return DefaultBatchWindow
+}
+
+func parseMaxBatchSize(cfg *core.ChainConfig) int {
+	if size, ok := cfg.Opts["maxBatchSize"]; ok {
+		res, err := strconv.ParseUint(size, 10, 32)
+		if err != nil {
+			panic(err)
+		}
+		if res == 0 {
+			panic("maxBatchSize must be greater than 0")
+		}
+		return int(res)
+	}
+	return DefaultMaxBatchSize
+}
+
+func parseDecimals(cfg *core.ChainConfig) chains.ResourceDecimals {
+	if decimals, ok := cfg.Opts["decimals"]; ok {
+		res, err := chains.ParseResourceDecimals(decimals)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+	return chains.ResourceDecimals{}
+}
+
+func parseDustPolicy(cfg *core.ChainConfig) string {
+	res, err := chains.ParseDustPolicy(cfg.Opts["dustPolicy"])
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func parseDecodeSS58(cfg *core.ChainConfig) bool {
+	if b, ok := cfg.Opts["ss58Recipients"]; ok {
+		res, err := strconv.ParseBool(b)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+	return false
+}
@@ -6,7 +6,11 @@ package substrate
 import (
 	"testing"
 
+	"github.com/ChainSafe/ChainBridge/chains"
+	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
 	"github.com/ChainSafe/chainbridge-utils/core"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
 )
 
 func TestParseStartBlock(t *testing.T) {
@@ -28,3 +32,69 @@ func TestParseStartBlock(t *testing.T) {
 		t.Fatalf("Got: %d Expected: %d", blk, 0)
 	}
 }
+
+func TestParseSubscribeFinalizedHeads(t *testing.T) {
+	if parseSubscribeFinalizedHeads(&core.ChainConfig{Opts: map[string]string{}}) {
+		t.Fatal("expected polling by default")
+	}
+	if !parseSubscribeFinalizedHeads(&core.ChainConfig{Opts: map[string]string{"subscribeFinalizedHeads": "true"}}) {
+		t.Fatal("expected subscription to be enabled")
+	}
+}
+
+func TestParseMortalPeriodAndTip(t *testing.T) {
+	cfg := &core.ChainConfig{Opts: map[string]string{}}
+	if period := parseMortalPeriod(cfg); period != utils.DefaultMortalPeriod {
+		t.Fatalf("Got: %d Expected: %d", period, utils.DefaultMortalPeriod)
+	}
+	if tip := parseTip(cfg); tip.Sign() != 0 {
+		t.Fatalf("Got: %s Expected: 0", tip)
+	}
+
+	cfg = &core.ChainConfig{Opts: map[string]string{"mortalPeriod": "0", "tip": "1000000000000"}}
+	if period := parseMortalPeriod(cfg); period != 0 {
+		t.Fatalf("Got: %d Expected: 0", period)
+	}
+	if tip := parseTip(cfg); tip.String() != "1000000000000" {
+		t.Fatalf("Got: %s Expected: 1000000000000", tip)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected negative tip to panic")
+		}
+	}()
+	parseTip(&core.ChainConfig{Opts: map[string]string{"tip": "-1"}})
+}
+
+func TestParseDecimals(t *testing.T) {
+	rId := "0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00"
+	cfg := &core.ChainConfig{Opts: map[string]string{"decimals": rId + ":18:12", "dustPolicy": "round"}}
+	decimals := parseDecimals(cfg)
+	expected := chains.Decimals{Source: 18, Destination: 12}
+	if d := decimals[msg.ResourceIdFromSlice(types.MustHexDecodeString(rId))]; d != expected {
+		t.Fatalf("Got: %v Expected: %v", d, expected)
+	}
+	if policy := parseDustPolicy(cfg); policy != chains.RoundDust {
+		t.Fatalf("Got: %s Expected: %s", policy, chains.RoundDust)
+	}
+	if policy := parseDustPolicy(&core.ChainConfig{Opts: map[string]string{}}); policy != chains.RejectDust {
+		t.Fatalf("Got: %s Expected: %s", policy, chains.RejectDust)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected invalid decimals to panic")
+		}
+	}()
+	parseDecimals(&core.ChainConfig{Opts: map[string]string{"decimals": rId + ":18"}})
+}
+
+func TestParseDecodeSS58(t *testing.T) {
+	if parseDecodeSS58(&core.ChainConfig{Opts: map[string]string{}}) {
+		t.Fatal("expected SS58 decoding to be disabled by default")
+	}
+	if !parseDecodeSS58(&core.ChainConfig{Opts: map[string]string{"ss58Recipients": "true"}}) {
+		t.Fatal("expected SS58 decoding to be enabled")
+	}
+}
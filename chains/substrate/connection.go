@@ -5,7 +5,9 @@ package substrate
 
 import (
 	"fmt"
+	"math/big"
 	"sync"
+	"time"
 
 	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
 	"github.com/ChainSafe/chainbridge-utils/msg"
@@ -29,10 +31,28 @@ type Connection struct {
 	nonceLock   sync.Mutex             // Locks nonce for updates
 	stop        <-chan int             // Signals system shutdown, should be observed in all selects and loops
 	sysErr      chan<- error           // Propagates fatal errors to core
+	finality    bool                   // Wait for submitted extrinsics to be finalized rather than included in a block
+	period      uint64                 // Number of blocks submitted extrinsics stay valid for, 0 for immortal extrinsics
+	tip         *big.Int               // Tip added to submitted extrinsics
 }
 
 func NewConnection(url string, name string, key *signature.KeyringPair, log log15.Logger, stop <-chan int, sysErr chan<- error) *Connection {
-	return &Connection{url: url, name: name, key: key, log: log, stop: stop, sysErr: sysErr}
+	return &Connection{url: url, name: name, key: key, log: log, stop: stop, sysErr: sysErr, period: utils.DefaultMortalPeriod, tip: big.NewInt(0)}
+}
+
+// setMortalPeriod configures the number of blocks submitted extrinsics stay valid for, 0 signs immortal extrinsics
+func (c *Connection) setMortalPeriod(period uint64) {
+	c.period = period
+}
+
+// setTip configures the tip added to submitted extrinsics to prioritise them
+func (c *Connection) setTip(tip *big.Int) {
+	c.tip = tip
+}
+
+// setWaitForFinality configures whether SubmitTx waits for the extrinsic to be finalized
+func (c *Connection) setWaitForFinality(wait bool) {
+	c.finality = wait
 }
 
 func (c *Connection) getMetadata() (meta types.Metadata) {
@@ -44,7 +64,7 @@ func (c *Connection) getMetadata() (meta types.Metadata) {
 
 func (c *Connection) updateMetatdata() error {
 	c.metaLock.Lock()
-	meta, err := c.api.RPC.State.GetMetadataLatest()
+	meta, err := utils.FetchMetadata(c.api)
 	if err != nil {
 		c.metaLock.Unlock()
 		return err
@@ -63,7 +83,7 @@ func (c *Connection) Connect() error {
 	c.api = api
 
 	// Fetch metadata
-	meta, err := api.RPC.State.GetMetadataLatest()
+	meta, err := utils.FetchMetadata(api)
 	if err != nil {
 		return err
 	}
@@ -85,50 +105,84 @@ func (c *Connection) Connect() error {
 func (c *Connection) SubmitTx(method utils.Method, args ...interface{}) error {
 	c.log.Debug("Submitting substrate call...", "method", method, "sender", c.key.Address)
 
-	meta := c.getMetadata()
-
-	// Create call and extrinsic
-	call, err := types.NewCall(
-		&meta,
-		string(method),
-		args...,
-	)
+	call, err := c.newCall(method, args...)
 	if err != nil {
 		return fmt.Errorf("failed to construct call: %w", err)
 	}
-	ext := types.NewExtrinsic(call)
 
-	// Get latest runtime version
-	rv, err := c.api.RPC.State.GetRuntimeVersionLatest()
+	hash, ext, err := c.submitCall(call)
 	if err != nil {
 		return err
 	}
+	return c.checkExtrinsicResult(hash, ext)
+}
+
+// SubmitBatch submits the calls as a single Utility.batch_all extrinsic and returns the outcome of each call.
+// A single call is submitted on its own.
+func (c *Connection) SubmitBatch(calls []types.Call) ([]error, error) {
+	if len(calls) == 1 {
+		hash, ext, err := c.submitCall(calls[0])
+		if err != nil {
+			return nil, err
+		}
+		return []error{c.checkExtrinsicResult(hash, ext)}, nil
+	}
+
+	c.log.Debug("Submitting substrate batch...", "calls", len(calls), "sender", c.key.Address)
+	call, err := c.newCall(BatchAll, calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct batch call: %w", err)
+	}
+
+	hash, ext, err := c.submitCall(call)
+	if err != nil {
+		return nil, err
+	}
+	index, err := c.findExtrinsic(hash, ext)
+	if err != nil {
+		return nil, err
+	}
+	e, err := c.extrinsicEvents(hash, index)
+	if err != nil {
+		return nil, err
+	}
+	meta := c.getMetadata()
+	return batchResults(&meta, e, hash, index, len(calls)), nil
+}
+
+// newCall constructs a call of the method with the current metadata
+func (c *Connection) newCall(method utils.Method, args ...interface{}) (types.Call, error) {
+	meta := c.getMetadata()
+	return types.NewCall(&meta, string(method), args...)
+}
+
+// submitCall signs and submits an extrinsic for the call. It returns the hash of the block the extrinsic
+// was included in, or finalized in if configured, along with the signed extrinsic.
+func (c *Connection) submitCall(call types.Call) (types.Hash, types.Extrinsic, error) {
+	ext := types.NewExtrinsic(call)
+
+	// Anchor the extrinsic at a recent block and use the latest runtime version
+	o, err := utils.NewSignatureOptions(c.api, c.genesisHash, 0, c.period, c.tip)
+	if err != nil {
+		return types.Hash{}, ext, err
+	}
 
 	c.nonceLock.Lock()
 	latestNonce, err := c.getLatestNonce()
 	if err != nil {
 		c.nonceLock.Unlock()
-		return err
+		return types.Hash{}, ext, err
 	}
 	if latestNonce > c.nonce {
 		c.nonce = latestNonce
 	}
 
 	// Sign the extrinsic
-	o := types.SignatureOptions{
-		BlockHash:          c.genesisHash,
-		Era:                types.ExtrinsicEra{IsMortalEra: false},
-		GenesisHash:        c.genesisHash,
-		Nonce:              types.NewUCompactFromUInt(uint64(c.nonce)),
-		SpecVersion:        rv.SpecVersion,
-		Tip:                types.NewUCompactFromUInt(0),
-		TransactionVersion: 1,
-	}
-
+	o.Nonce = types.NewUCompactFromUInt(uint64(c.nonce))
 	err = ext.Sign(*c.key, o)
 	if err != nil {
 		c.nonceLock.Unlock()
-		return err
+		return types.Hash{}, ext, err
 	}
 
 	// Submit and watch the extrinsic
@@ -136,38 +190,129 @@ func (c *Connection) SubmitTx(method utils.Method, args ...interface{}) error {
 	c.nonce++
 	c.nonceLock.Unlock()
 	if err != nil {
-		return fmt.Errorf("submission of extrinsic failed: %w", err)
+		return types.Hash{}, ext, fmt.Errorf("submission of extrinsic failed: %w", err)
 	}
 	c.log.Trace("Extrinsic submission succeeded")
 	defer sub.Unsubscribe()
 
-	return c.watchSubmission(sub)
+	hash, err := c.watchSubmission(sub)
+	return hash, ext, err
 }
 
-func (c *Connection) watchSubmission(sub *author.ExtrinsicStatusSubscription) error {
+// watchSubmission waits until the extrinsic is included in a block, or finalized if configured, and returns the block hash
+func (c *Connection) watchSubmission(sub *author.ExtrinsicStatusSubscription) (types.Hash, error) {
 	for {
 		select {
 		case <-c.stop:
-			return TerminatedError
+			return types.Hash{}, TerminatedError
 		case status := <-sub.Chan():
 			switch {
 			case status.IsInBlock:
 				c.log.Trace("Extrinsic included in block", "block", status.AsInBlock.Hex())
-				return nil
+				if !c.finality {
+					return status.AsInBlock, nil
+				}
+			case status.IsFinalized:
+				c.log.Trace("Extrinsic finalized", "block", status.AsFinalized.Hex())
+				return status.AsFinalized, nil
+			case status.IsFinalityTimeout:
+				return types.Hash{}, fmt.Errorf("extrinsic finality timeout: %s", status.AsFinalityTimeout.Hex())
+			case status.IsUsurped:
+				return types.Hash{}, fmt.Errorf("extrinsic usurped: %s", status.AsUsurped.Hex())
 			case status.IsRetracted:
-				return fmt.Errorf("extrinsic retracted: %s", status.AsRetracted.Hex())
+				return types.Hash{}, fmt.Errorf("extrinsic retracted: %s", status.AsRetracted.Hex())
 			case status.IsDropped:
-				return fmt.Errorf("extrinsic dropped from network")
+				return types.Hash{}, fmt.Errorf("extrinsic dropped from network")
 			case status.IsInvalid:
-				return fmt.Errorf("extrinsic invalid")
+				return types.Hash{}, fmt.Errorf("extrinsic invalid")
 			}
 		case err := <-sub.Err():
 			c.log.Trace("Extrinsic subscription error", "err", err)
-			return err
+			return types.Hash{}, err
+		}
+	}
+}
+
+// checkExtrinsicResult fetches the block and its events to determine whether ext was dispatched successfully.
+// An ExtrinsicFailedError is returned if the dispatch failed.
+func (c *Connection) checkExtrinsicResult(hash types.Hash, ext types.Extrinsic) error {
+	index, err := c.findExtrinsic(hash, ext)
+	if err != nil {
+		return err
+	}
+	e, err := c.extrinsicEvents(hash, index)
+	if err != nil {
+		return err
+	}
+	meta := c.getMetadata()
+	return extrinsicResult(&meta, e, hash, index)
+}
+
+// extrinsicEvents fetches the events of the block of an included extrinsic. Failures are retried up to
+// BlockRetryLimit times, after which an error is returned as the outcome of the extrinsic is unknown.
+func (c *Connection) extrinsicEvents(hash types.Hash, index uint32) (*extrinsicEvents, error) {
+	for i := 1; ; i++ {
+		e, err := c.blockEvents(hash)
+		if err == nil {
+			return e, nil
+		}
+		if i >= BlockRetryLimit {
+			return nil, fmt.Errorf("unable to verify result of extrinsic %d in block %s: %w", index, hash.Hex(), err)
+		}
+		c.log.Warn("Unable to fetch events, retrying", "block", hash.Hex(), "index", index, "err", err)
+		select {
+		case <-c.stop:
+			return nil, TerminatedError
+		case <-time.After(BlockRetryInterval):
 		}
 	}
 }
 
+// findExtrinsic returns the index of ext within the block
+func (c *Connection) findExtrinsic(hash types.Hash, ext types.Extrinsic) (uint32, error) {
+	block, err := c.api.RPC.Chain.GetBlock(hash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch block %s: %w", hash.Hex(), err)
+	}
+	index, ok, err := extrinsicIndex(&block.Block, ext)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("extrinsic not found in block %s", hash.Hex())
+	}
+	return index, nil
+}
+
+// blockEvents fetches and decodes the events of a block
+func (c *Connection) blockEvents(hash types.Hash) (*extrinsicEvents, error) {
+	e := extrinsicEvents{}
+	err := c.decodeEvents(hash, &e)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// decodeEvents decodes the events of a block into the slices of target that are named after them
+func (c *Connection) decodeEvents(hash types.Hash, target interface{}) error {
+	meta := c.getMetadata()
+	key, err := types.CreateStorageKey(&meta, "System", "Events", nil, nil)
+	if err != nil {
+		return err
+	}
+	var records types.EventRecordsRaw
+	_, err = c.api.RPC.State.GetStorage(key, &records, hash)
+	if err != nil {
+		return fmt.Errorf("failed to fetch events of block %s: %w", hash.Hex(), err)
+	}
+	err = utils.DecodeEvents(&meta, records, target)
+	if err != nil {
+		return fmt.Errorf("failed to decode events of block %s: %w", hash.Hex(), err)
+	}
+	return nil
+}
+
 // queryStorage performs a storage lookup. Arguments may be nil, result must be a pointer.
 func (c *Connection) queryStorage(prefix, method string, arg1, arg2 []byte, result interface{}) (bool, error) {
 	// Fetch account nonce
@@ -179,23 +324,23 @@ func (c *Connection) queryStorage(prefix, method string, arg1, arg2 []byte, resu
 	return c.api.RPC.State.GetStorageLatest(key, result)
 }
 
-// TODO: Add this to GSRPC
-func getConst(meta *types.Metadata, prefix, name string, res interface{}) error {
-	for _, mod := range meta.AsMetadataV12.Modules {
-		if string(mod.Name) == prefix {
-			for _, cons := range mod.Constants {
-				if string(cons.Name) == name {
-					return types.DecodeFromBytes(cons.Value, res)
-				}
-			}
-		}
+// queryStorageAt performs a storage lookup in the state of the block with the given number
+func (c *Connection) queryStorageAt(block uint64, prefix, method string, arg1, arg2 []byte, result interface{}) (bool, error) {
+	data := c.getMetadata()
+	key, err := types.CreateStorageKey(&data, prefix, method, arg1, arg2)
+	if err != nil {
+		return false, err
+	}
+	hash, err := c.api.RPC.Chain.GetBlockHash(block)
+	if err != nil {
+		return false, err
 	}
-	return fmt.Errorf("could not find constant %s.%s", prefix, name)
+	return c.api.RPC.State.GetStorage(key, result, hash)
 }
 
 func (c *Connection) getConst(prefix, name string, res interface{}) error {
 	meta := c.getMetadata()
-	return getConst(&meta, prefix, name, res)
+	return utils.GetConst(&meta, prefix, name, res)
 }
 
 func (c *Connection) checkChainId(expected msg.ChainId) error {
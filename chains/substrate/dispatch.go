@@ -0,0 +1,153 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// ErrBatchNotExecuted is returned for calls of a batch that were not applied because another call of the batch failed
+var ErrBatchNotExecuted = errors.New("call of batch not executed")
+
+// ExtrinsicFailedError is returned when an extrinsic was included in a block but its dispatch failed
+type ExtrinsicFailedError struct {
+	Block         types.Hash          // Block the extrinsic was included in
+	Index         uint32              // Index of the extrinsic within the block
+	DispatchError types.DispatchError // Raw dispatch error from the System.ExtrinsicFailed event
+	Module        string              // Name of the module that returned the error, empty if unknown
+	Name          string              // Name of the error, empty if unknown
+}
+
+func (e *ExtrinsicFailedError) Error() string {
+	if e.Module == "" {
+		return fmt.Sprintf("extrinsic %d in block %s failed: %+v", e.Index, e.Block.Hex(), e.DispatchError)
+	}
+	return fmt.Sprintf("extrinsic %d in block %s failed: %s.%s", e.Index, e.Block.Hex(), e.Module, e.Name)
+}
+
+// extrinsicEvents are the events used to determine the outcome of submitted extrinsics
+type extrinsicEvents struct {
+	System_ExtrinsicSuccess  []types.EventSystemExtrinsicSuccess  //nolint:stylecheck,golint
+	System_ExtrinsicFailed   []types.EventSystemExtrinsicFailed   //nolint:stylecheck,golint
+	Utility_BatchInterrupted []types.EventUtilityBatchInterrupted //nolint:stylecheck,golint
+	Utility_ItemCompleted    []utils.EventUtilityItemCompleted    //nolint:stylecheck,golint
+}
+
+// decodeDispatchError resolves the module and error names of a module dispatch error from the metadata.
+// Empty strings are returned if the error is not a module error or cannot be found.
+func decodeDispatchError(meta *types.Metadata, dispatchErr types.DispatchError) (string, string) {
+	if !dispatchErr.HasModule {
+		return "", ""
+	}
+	for _, mod := range meta.AsMetadataV12.Modules {
+		if mod.Index != dispatchErr.Module {
+			continue
+		}
+		if int(dispatchErr.Error) < len(mod.Errors) {
+			return string(mod.Name), string(mod.Errors[dispatchErr.Error].Name)
+		}
+		return string(mod.Name), ""
+	}
+	return "", ""
+}
+
+// extrinsicIndex returns the position of ext within the block
+func extrinsicIndex(block *types.Block, ext types.Extrinsic) (uint32, bool, error) {
+	target, err := types.EncodeToBytes(ext)
+	if err != nil {
+		return 0, false, err
+	}
+	for i, e := range block.Extrinsics {
+		enc, err := types.EncodeToBytes(e)
+		if err != nil {
+			return 0, false, err
+		}
+		if bytes.Equal(enc, target) {
+			return uint32(i), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// extrinsicResult finds the System.ExtrinsicSuccess or System.ExtrinsicFailed event of the extrinsic at index.
+// An ExtrinsicFailedError is returned if the dispatch failed.
+func extrinsicResult(meta *types.Metadata, evts *extrinsicEvents, block types.Hash, index uint32) error {
+	for _, evt := range evts.System_ExtrinsicSuccess {
+		if evt.Phase.IsApplyExtrinsic && evt.Phase.AsApplyExtrinsic == index {
+			return nil
+		}
+	}
+	for _, evt := range evts.System_ExtrinsicFailed {
+		if evt.Phase.IsApplyExtrinsic && evt.Phase.AsApplyExtrinsic == index {
+			module, name := decodeDispatchError(meta, evt.DispatchError)
+			return &ExtrinsicFailedError{
+				Block:         block,
+				Index:         index,
+				DispatchError: evt.DispatchError,
+				Module:        module,
+				Name:          name,
+			}
+		}
+	}
+	return fmt.Errorf("no result event found for extrinsic %d in block %s", index, block.Hex())
+}
+
+// batchResults resolves the outcome of each of the n calls of the batch extrinsic at index. If the extrinsic
+// failed, batch_all reverted all calls. Otherwise the calls before a Utility.BatchInterrupted event completed,
+// the interrupting call failed and the remaining calls were not executed.
+func batchResults(meta *types.Metadata, evts *extrinsicEvents, block types.Hash, index uint32, n int) []error {
+	res := make([]error, n)
+
+	err := extrinsicResult(meta, evts, block, index)
+	if err != nil {
+		var dispatchErr *ExtrinsicFailedError
+		if errors.As(err, &dispatchErr) {
+			err = fmt.Errorf("%w: %s", ErrBatchNotExecuted, err)
+		}
+		for i := range res {
+			res[i] = err
+		}
+		return res
+	}
+
+	for _, evt := range evts.Utility_BatchInterrupted {
+		if !evt.Phase.IsApplyExtrinsic || evt.Phase.AsApplyExtrinsic != index {
+			continue
+		}
+		failed := int(evt.Index)
+		for i := failed; i < n; i++ {
+			res[i] = ErrBatchNotExecuted
+		}
+		if failed < n {
+			module, name := decodeDispatchError(meta, evt.DispatchError)
+			res[failed] = &ExtrinsicFailedError{
+				Block:         block,
+				Index:         index,
+				DispatchError: evt.DispatchError,
+				Module:        module,
+				Name:          name,
+			}
+		}
+		return res
+	}
+
+	// Runtimes that emit Utility.ItemCompleted report every executed call
+	completed := 0
+	for _, evt := range evts.Utility_ItemCompleted {
+		if evt.Phase.IsApplyExtrinsic && evt.Phase.AsApplyExtrinsic == index {
+			completed++
+		}
+	}
+	if completed > 0 {
+		for i := completed; i < n; i++ {
+			res[i] = ErrBatchNotExecuted
+		}
+	}
+	return res
+}
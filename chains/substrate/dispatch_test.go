@@ -0,0 +1,161 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"errors"
+	"testing"
+
+	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func testMetadata() *types.Metadata {
+	return &types.Metadata{
+		Version:       12,
+		IsMetadataV12: true,
+		AsMetadataV12: types.MetadataV12{
+			Modules: []types.ModuleMetadataV12{
+				{Name: "System", Index: 0},
+				{
+					Name:  "ChainBridge",
+					Index: 7,
+					Errors: []types.ErrorMetadataV8{
+						{Name: "ThresholdNotSet"},
+						{Name: "InvalidChainId"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDecodeDispatchError(t *testing.T) {
+	meta := testMetadata()
+	tests := []struct {
+		name   string
+		err    types.DispatchError
+		module string
+		error  string
+	}{
+		{"module error", types.DispatchError{HasModule: true, Module: 7, Error: 1}, "ChainBridge", "InvalidChainId"},
+		{"unknown error", types.DispatchError{HasModule: true, Module: 7, Error: 5}, "ChainBridge", ""},
+		{"unknown module", types.DispatchError{HasModule: true, Module: 3, Error: 0}, "", ""},
+		{"not a module error", types.DispatchError{Error: 1}, "", ""},
+	}
+
+	for _, tt := range tests {
+		module, name := decodeDispatchError(meta, tt.err)
+		if module != tt.module || name != tt.error {
+			t.Errorf("%s: expected %s.%s, got %s.%s", tt.name, tt.module, tt.error, module, name)
+		}
+	}
+}
+
+func TestExtrinsicResult(t *testing.T) {
+	meta := testMetadata()
+	block := types.NewHash([]byte{1})
+	evts := &extrinsicEvents{}
+	evts.System_ExtrinsicSuccess = []types.EventSystemExtrinsicSuccess{
+		{Phase: types.Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: 0}},
+	}
+	evts.System_ExtrinsicFailed = []types.EventSystemExtrinsicFailed{
+		{
+			Phase:         types.Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: 1},
+			DispatchError: types.DispatchError{HasModule: true, Module: 7, Error: 0},
+		},
+	}
+
+	err := extrinsicResult(meta, evts, block, 0)
+	if err != nil {
+		t.Fatalf("expected success, got %s", err)
+	}
+
+	err = extrinsicResult(meta, evts, block, 1)
+	var dispatchErr *ExtrinsicFailedError
+	if !errors.As(err, &dispatchErr) {
+		t.Fatalf("expected ExtrinsicFailedError, got %v", err)
+	}
+	if dispatchErr.Module != "ChainBridge" || dispatchErr.Name != "ThresholdNotSet" || dispatchErr.Index != 1 || dispatchErr.Block != block {
+		t.Fatalf("unexpected error: %+v", dispatchErr)
+	}
+
+	err = extrinsicResult(meta, evts, block, 2)
+	if err == nil || errors.As(err, &dispatchErr) {
+		t.Fatalf("expected missing event error, got %v", err)
+	}
+}
+
+func TestExtrinsicIndex(t *testing.T) {
+	a := types.Extrinsic{Version: types.ExtrinsicVersion4, Method: types.Call{Args: []byte{1}}}
+	b := types.Extrinsic{Version: types.ExtrinsicVersion4, Method: types.Call{Args: []byte{2}}}
+	block := &types.Block{Extrinsics: []types.Extrinsic{a, b}}
+
+	index, ok, err := extrinsicIndex(block, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || index != 1 {
+		t.Fatalf("expected index 1, got %d (found: %t)", index, ok)
+	}
+
+	c := types.Extrinsic{Version: types.ExtrinsicVersion4, Method: types.Call{Args: []byte{3}}}
+	_, ok, err = extrinsicIndex(block, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected extrinsic not to be found")
+	}
+}
+
+func TestBatchResults(t *testing.T) {
+	meta := testMetadata()
+	block := types.NewHash([]byte{1})
+	applyExtrinsic := func(index uint32) types.Phase {
+		return types.Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: index}
+	}
+	dispatchErr := types.DispatchError{HasModule: true, Module: 7, Error: 1}
+
+	// Completed batch
+	evts := &extrinsicEvents{}
+	evts.System_ExtrinsicSuccess = []types.EventSystemExtrinsicSuccess{{Phase: applyExtrinsic(1)}}
+	for _, err := range batchResults(meta, evts, block, 1, 3) {
+		if err != nil {
+			t.Fatalf("expected all calls to succeed, got %s", err)
+		}
+	}
+
+	// Interrupted at the second call, items of other extrinsics are ignored
+	evts = &extrinsicEvents{}
+	evts.System_ExtrinsicSuccess = []types.EventSystemExtrinsicSuccess{{Phase: applyExtrinsic(1)}}
+	evts.Utility_ItemCompleted = []utils.EventUtilityItemCompleted{{Phase: applyExtrinsic(0)}, {Phase: applyExtrinsic(1)}}
+	evts.Utility_BatchInterrupted = []types.EventUtilityBatchInterrupted{{Phase: applyExtrinsic(1), Index: 1, DispatchError: dispatchErr}}
+	res := batchResults(meta, evts, block, 1, 3)
+	var failed *ExtrinsicFailedError
+	if res[0] != nil || !errors.As(res[1], &failed) || !errors.Is(res[2], ErrBatchNotExecuted) {
+		t.Fatalf("unexpected results for interrupted batch: %v", res)
+	}
+	if failed.Name != "InvalidChainId" {
+		t.Fatalf("expected InvalidChainId, got %s", failed.Name)
+	}
+
+	// Reverted batch_all
+	evts = &extrinsicEvents{}
+	evts.System_ExtrinsicFailed = []types.EventSystemExtrinsicFailed{{Phase: applyExtrinsic(1), DispatchError: dispatchErr}}
+	for _, err := range batchResults(meta, evts, block, 1, 2) {
+		if !errors.Is(err, ErrBatchNotExecuted) {
+			t.Fatalf("expected ErrBatchNotExecuted, got %v", err)
+		}
+	}
+
+	// Fewer items completed than submitted
+	evts = &extrinsicEvents{}
+	evts.System_ExtrinsicSuccess = []types.EventSystemExtrinsicSuccess{{Phase: applyExtrinsic(1)}}
+	evts.Utility_ItemCompleted = []utils.EventUtilityItemCompleted{{Phase: applyExtrinsic(1)}}
+	res = batchResults(meta, evts, block, 1, 2)
+	if res[0] != nil || !errors.Is(res[1], ErrBatchNotExecuted) {
+		t.Fatalf("unexpected results: %v", res)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	events "github.com/ChainSafe/chainbridge-substrate-events"
 	"github.com/ChainSafe/chainbridge-utils/msg"
 	"github.com/ChainSafe/log15"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
 )
 
 type eventName string
@@ -19,13 +20,28 @@ const FungibleTransfer eventName = "FungibleTransfer"
 const NonFungibleTransfer eventName = "NonFungibleTransfer"
 const GenericTransfer eventName = "GenericTransfer"
 
+// bridgeEvents are the events handled by the listener. All other events of a block are skipped when decoding.
+type bridgeEvents struct {
+	ChainBridge_FungibleTransfer    []events.EventFungibleTransfer    //nolint:stylecheck,golint
+	ChainBridge_NonFungibleTransfer []events.EventNonFungibleTransfer //nolint:stylecheck,golint
+	ChainBridge_GenericTransfer     []events.EventGenericTransfer     //nolint:stylecheck,golint
+	System_CodeUpdated              []types.EventSystemCodeUpdated    //nolint:stylecheck,golint
+}
+
+// proposalEvents are the events reporting the outcome of an approved proposal
+type proposalEvents struct {
+	ChainBridge_ProposalSucceeded []events.EventProposalSucceeded //nolint:stylecheck,golint
+	ChainBridge_ProposalFailed    []events.EventProposalFailed    //nolint:stylecheck,golint
+}
+
 var Subscriptions = []struct {
-	name    eventName
-	handler eventHandler
+	name     eventName
+	transfer msg.TransferType
+	handler  eventHandler
 }{
-	{FungibleTransfer, fungibleTransferHandler},
-	{NonFungibleTransfer, nonFungibleTransferHandler},
-	{GenericTransfer, genericTransferHandler},
+	{FungibleTransfer, msg.FungibleTransfer, fungibleTransferHandler},
+	{NonFungibleTransfer, msg.NonFungibleTransfer, nonFungibleTransferHandler},
+	{GenericTransfer, msg.GenericTransfer, genericTransferHandler},
 }
 
 func fungibleTransferHandler(evtI interface{}, log log15.Logger) (msg.Message, error) {
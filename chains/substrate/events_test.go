@@ -0,0 +1,89 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	events "github.com/ChainSafe/chainbridge-substrate-events"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func Test_fungibleTransferHandler(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{1})
+	recipient := []byte{0xab, 0xcd}
+	amount := big.NewInt(1000000)
+	evt := events.EventFungibleTransfer{
+		Destination:  types.U8(ForeignChain),
+		DepositNonce: types.U64(1),
+		ResourceId:   types.NewBytes32(rId),
+		Amount:       types.NewU256(*amount),
+		Recipient:    recipient,
+	}
+
+	expected := msg.NewFungibleTransfer(0, ForeignChain, 1, amount, rId, recipient)
+	m, err := fungibleTransferHandler(evt, AliceTestLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, m) {
+		t.Fatalf("Message doesn't match.\n\tExpected: %#v\n\tGot: %#v\n", expected, m)
+	}
+}
+
+func Test_nonFungibleTransferHandler(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{2})
+	tokenId := big.NewInt(1212)
+	recipient := []byte{0xab, 0xcd}
+	metadata := big.NewInt(0x808080808).Bytes()
+	evt := events.EventNonFungibleTransfer{
+		Destination:  types.U8(ForeignChain),
+		DepositNonce: types.U64(2),
+		ResourceId:   types.NewBytes32(rId),
+		TokenId:      tokenId.Bytes(),
+		Recipient:    recipient,
+		Metadata:     metadata,
+	}
+
+	expected := msg.NewNonFungibleTransfer(0, ForeignChain, 2, rId, tokenId, recipient, metadata)
+	m, err := nonFungibleTransferHandler(evt, AliceTestLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, m) {
+		t.Fatalf("Message doesn't match.\n\tExpected: %#v\n\tGot: %#v\n", expected, m)
+	}
+}
+
+func Test_genericTransferHandler(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{3})
+	hash := types.MustHexDecodeString("0x16078eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f2")
+	evt := events.EventGenericTransfer{
+		Destination:  types.U8(ForeignChain),
+		DepositNonce: types.U64(3),
+		ResourceId:   types.NewBytes32(rId),
+		Metadata:     hash,
+	}
+
+	expected := msg.NewGenericTransfer(0, ForeignChain, 3, rId, hash)
+	m, err := genericTransferHandler(evt, AliceTestLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, m) {
+		t.Fatalf("Message doesn't match.\n\tExpected: %#v\n\tGot: %#v\n", expected, m)
+	}
+}
+
+func Test_HandlerRejectsWrongEventType(t *testing.T) {
+	for _, sub := range Subscriptions {
+		_, err := sub.handler(struct{}{}, AliceTestLogger)
+		if err == nil {
+			t.Fatalf("%s handler should fail to cast an unknown event", sub.name)
+		}
+	}
+}
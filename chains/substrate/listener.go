@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/ChainSafe/ChainBridge/chains"
-	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
 	"github.com/ChainSafe/chainbridge-utils/blockstore"
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/chainbridge-utils/msg"
@@ -22,6 +21,9 @@ type listener struct {
 	name          string
 	chainId       msg.ChainId
 	startBlock    uint64
+	currentBlock  uint64 // Next block to process
+	subscribe     bool   // Process blocks as finalized heads are announced instead of polling
+	profile       *Profile
 	blockstore    blockstore.Blockstorer
 	conn          *Connection
 	subscriptions map[eventName]eventHandler // Handlers for specific events
@@ -37,11 +39,17 @@ type listener struct {
 var BlockRetryInterval = time.Second * 5
 var BlockRetryLimit = 5
 
+// Initial and maximum delay before re-subscribing to finalized heads after the subscription failed
+var ResubscribeInterval = time.Second * 5
+var MaxResubscribeInterval = time.Minute * 5
+
 func NewListener(conn *Connection, name string, id msg.ChainId, startBlock uint64, log log15.Logger, bs blockstore.Blockstorer, stop <-chan int, sysErr chan<- error, m *metrics.ChainMetrics) *listener {
 	return &listener{
 		name:          name,
 		chainId:       id,
 		startBlock:    startBlock,
+		currentBlock:  startBlock,
+		profile:       DefaultProfile,
 		blockstore:    bs,
 		conn:          conn,
 		subscriptions: make(map[eventName]eventHandler),
@@ -57,6 +65,15 @@ func (l *listener) setRouter(r chains.Router) {
 	l.router = r
 }
 
+// setProfile sets the runtime of the chain, events of unsupported transfer types are ignored
+func (l *listener) setProfile(p *Profile) {
+	l.profile = p
+}
+
+func (l *listener) setSubscribe(subscribe bool) {
+	l.subscribe = subscribe
+}
+
 // start creates the initial subscription for all events
 func (l *listener) start() error {
 	// Check whether latest is less than starting block
@@ -68,15 +85,18 @@ func (l *listener) start() error {
 		return fmt.Errorf("starting block (%d) is greater than latest known block (%d)", l.startBlock, header.Number)
 	}
 
-	for _, sub := range Subscriptions {
-		err := l.registerEventHandler(sub.name, sub.handler)
-		if err != nil {
-			return err
-		}
+	err = l.registerSubscriptions()
+	if err != nil {
+		return err
 	}
 
 	go func() {
-		err := l.pollBlocks()
+		var err error
+		if l.subscribe {
+			err = l.subscribeBlocks()
+		} else {
+			err = l.pollBlocks()
+		}
 		if err != nil {
 			l.log.Error("Polling blocks failed", "err", err)
 		}
@@ -85,6 +105,20 @@ func (l *listener) start() error {
 	return nil
 }
 
+// registerSubscriptions enables the handlers of all transfer types supported by the runtime
+func (l *listener) registerSubscriptions() error {
+	for _, sub := range Subscriptions {
+		if !l.profile.supports(sub.transfer) {
+			continue
+		}
+		err := l.registerEventHandler(sub.name, sub.handler)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // registerEventHandler enables a handler for a given event. This cannot be used after Start is called.
 func (l *listener) registerEventHandler(name eventName, handler eventHandler) error {
 	if l.subscriptions[name] != nil {
@@ -96,17 +130,29 @@ func (l *listener) registerEventHandler(name eventName, handler eventHandler) er
 
 var ErrBlockNotReady = errors.New("required result to be 32 bytes, but got 0")
 
+// errPollDeadline is returned by pollBlocksUntil once its deadline has passed
+var errPollDeadline = errors.New("polling deadline reached")
+
 // pollBlocks will poll for the latest block and proceed to parse the associated events as it sees new blocks.
-// Polling begins at the block defined in `l.startBlock`. Failed attempts to fetch the latest block or parse
+// Polling begins at the block defined in `l.currentBlock`. Failed attempts to fetch the latest block or parse
 // a block will be retried up to BlockRetryLimit times before returning with an error.
 func (l *listener) pollBlocks() error {
-	var currentBlock = l.startBlock
+	return l.pollBlocksUntil(time.Time{})
+}
+
+// pollBlocksUntil polls like pollBlocks, but returns errPollDeadline once the deadline has passed. A zero deadline
+// polls indefinitely.
+func (l *listener) pollBlocksUntil(deadline time.Time) error {
 	var retry = BlockRetryLimit
 	for {
 		select {
 		case <-l.stop:
 			return errors.New("terminated")
 		default:
+			if !deadline.IsZero() && time.Now().After(deadline) {
+				return errPollDeadline
+			}
+
 			// No more retries, goto next block
 			if retry == 0 {
 				l.sysErr <- fmt.Errorf("event polling retries exceeded (chain=%d, name=%s)", l.chainId, l.name)
@@ -136,95 +182,197 @@ func (l *listener) pollBlocks() error {
 			}
 
 			// Sleep if the block we want comes after the most recently finalized block
-			if currentBlock > uint64(finalizedHeader.Number) {
-				l.log.Trace("Block not yet finalized", "target", currentBlock, "latest", finalizedHeader.Number)
+			if l.currentBlock > uint64(finalizedHeader.Number) {
+				l.log.Trace("Block not yet finalized", "target", l.currentBlock, "latest", finalizedHeader.Number)
 				time.Sleep(BlockRetryInterval)
 				continue
 			}
 
 			// Get hash for latest block, sleep and retry if not ready
-			hash, err := l.conn.api.RPC.Chain.GetBlockHash(currentBlock)
+			hash, err := l.conn.api.RPC.Chain.GetBlockHash(l.currentBlock)
 			if err != nil && err.Error() == ErrBlockNotReady.Error() {
 				time.Sleep(BlockRetryInterval)
 				continue
 			} else if err != nil {
-				l.log.Error("Failed to query latest block", "block", currentBlock, "err", err)
+				l.log.Error("Failed to query latest block", "block", l.currentBlock, "err", err)
 				retry--
 				time.Sleep(BlockRetryInterval)
 				continue
 			}
 
-			err = l.processEvents(hash)
+			err = l.processBlock(hash)
 			if err != nil {
-				l.log.Error("Failed to process events in block", "block", currentBlock, "err", err)
+				l.log.Error("Failed to process events in block", "block", l.currentBlock, "err", err)
 				retry--
 				continue
 			}
+			retry = BlockRetryLimit
+		}
+	}
+}
 
-			// Write to blockstore
-			err = l.blockstore.StoreBlock(big.NewInt(0).SetUint64(currentBlock))
-			if err != nil {
-				l.log.Error("Failed to write to blockstore", "err", err)
-			}
+// subscriptionError is returned by followFinalizedHeads when the subscription cannot be created or is dropped
+type subscriptionError struct {
+	err error
+}
+
+func (e *subscriptionError) Error() string {
+	return e.err.Error()
+}
 
+// subscribeBlocks processes blocks as finalized heads are announced by the node. If the subscription cannot be
+// created or is dropped, blocks are polled until re-subscribing, with the delay doubling up to MaxResubscribeInterval
+// while the subscription keeps failing.
+func (l *listener) subscribeBlocks() error {
+	backoff := ResubscribeInterval
+	for {
+		processed := l.currentBlock
+		err := l.followFinalizedHeads()
+		var subErr *subscriptionError
+		if !errors.As(err, &subErr) {
+			return err
+		}
+		// Only back off further if the subscription failed without delivering any blocks
+		if l.currentBlock > processed {
+			backoff = ResubscribeInterval
+		}
+
+		l.log.Warn("Finalized head subscription failed, polling until re-subscribing", "err", subErr.err, "retry", backoff)
+		err = l.pollBlocksUntil(time.Now().Add(backoff))
+		if err != errPollDeadline {
+			return err
+		}
+		backoff *= 2
+		if backoff > MaxResubscribeInterval {
+			backoff = MaxResubscribeInterval
+		}
+	}
+}
+
+// followFinalizedHeads subscribes to finalized heads and processes blocks as they are announced. Blocks between the
+// last processed block and an announced head are fetched by number, as the node may skip heads.
+func (l *listener) followFinalizedHeads() error {
+	sub, err := l.conn.api.RPC.Chain.SubscribeFinalizedHeads()
+	if err != nil {
+		return &subscriptionError{err}
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-l.stop:
+			return errors.New("terminated")
+		case err := <-sub.Err():
+			return &subscriptionError{err}
+		case head := <-sub.Chan():
 			if l.metrics != nil {
-				l.metrics.BlocksProcessed.Inc()
-				l.metrics.LatestProcessedBlock.Set(float64(currentBlock))
+				l.metrics.LatestKnownBlock.Set(float64(head.Number))
 			}
 
-			currentBlock++
-			l.latestBlock.Height = big.NewInt(0).SetUint64(currentBlock)
-			l.latestBlock.LastUpdated = time.Now()
-			retry = BlockRetryLimit
+			err = l.processUntil(uint64(head.Number))
+			if err != nil {
+				l.sysErr <- err
+				return nil
+			}
 		}
 	}
 }
 
-// processEvents fetches a block and parses out the events, calling Listener.handleEvents()
-func (l *listener) processEvents(hash types.Hash) error {
-	l.log.Trace("Fetching block for events", "hash", hash.Hex())
-	meta := l.conn.getMetadata()
-	key, err := types.CreateStorageKey(&meta, "System", "Events", nil, nil)
+// processUntil processes all blocks from l.currentBlock up to and including the finalized block number. Failed
+// attempts to fetch or parse a block will be retried up to BlockRetryLimit times before returning with an error.
+func (l *listener) processUntil(finalized uint64) error {
+	var retry = BlockRetryLimit
+	for l.currentBlock <= finalized {
+		select {
+		case <-l.stop:
+			return nil
+		default:
+		}
+
+		if retry == 0 {
+			return fmt.Errorf("event polling retries exceeded (chain=%d, name=%s)", l.chainId, l.name)
+		}
+
+		hash, err := l.conn.api.RPC.Chain.GetBlockHash(l.currentBlock)
+		if err != nil {
+			l.log.Error("Failed to query block hash", "block", l.currentBlock, "err", err)
+			retry--
+			time.Sleep(BlockRetryInterval)
+			continue
+		}
+
+		err = l.processBlock(hash)
+		if err != nil {
+			l.log.Error("Failed to process events in block", "block", l.currentBlock, "err", err)
+			retry--
+			time.Sleep(BlockRetryInterval)
+			continue
+		}
+		retry = BlockRetryLimit
+	}
+	return nil
+}
+
+// processBlock processes the events of l.currentBlock, records it in the blockstore and moves on to the next block
+func (l *listener) processBlock(hash types.Hash) error {
+	err := l.processEvents(hash)
 	if err != nil {
 		return err
 	}
 
-	var records types.EventRecordsRaw
-	_, err = l.conn.api.RPC.State.GetStorage(key, &records, hash)
+	// Write to blockstore
+	err = l.blockstore.StoreBlock(big.NewInt(0).SetUint64(l.currentBlock))
 	if err != nil {
-		return err
+		l.log.Error("Failed to write to blockstore", "err", err)
+	}
+
+	if l.metrics != nil {
+		l.metrics.BlocksProcessed.Inc()
+		l.metrics.LatestProcessedBlock.Set(float64(l.currentBlock))
 	}
 
-	e := utils.Events{}
-	err = records.DecodeEventRecords(&meta, &e)
+	l.currentBlock++
+	l.latestBlock.Height = big.NewInt(0).SetUint64(l.currentBlock)
+	l.latestBlock.LastUpdated = time.Now()
+	return nil
+}
+
+// processEvents fetches a block and parses out the events, calling Listener.handleEvents()
+func (l *listener) processEvents(hash types.Hash) error {
+	l.log.Trace("Fetching block for events", "hash", hash.Hex())
+	e := bridgeEvents{}
+	err := l.conn.decodeEvents(hash, &e)
 	if err != nil {
 		return err
 	}
 
-	l.handleEvents(e)
+	l.handleEvents(e, l.router)
 	l.log.Trace("Finished processing events", "block", hash.Hex())
 
 	return nil
 }
 
-// handleEvents calls the associated handler for all registered event types
-func (l *listener) handleEvents(evts utils.Events) {
+// handleEvents calls the associated handler for all registered event types and sends the messages to the router
+func (l *listener) handleEvents(evts bridgeEvents, router chains.Router) {
+	submit := func(m msg.Message, err error) {
+		l.submitMessage(router, m, err)
+	}
 	if l.subscriptions[FungibleTransfer] != nil {
 		for _, evt := range evts.ChainBridge_FungibleTransfer {
 			l.log.Trace("Handling FungibleTransfer event")
-			l.submitMessage(l.subscriptions[FungibleTransfer](evt, l.log))
+			submit(l.subscriptions[FungibleTransfer](evt, l.log))
 		}
 	}
 	if l.subscriptions[NonFungibleTransfer] != nil {
 		for _, evt := range evts.ChainBridge_NonFungibleTransfer {
 			l.log.Trace("Handling NonFungibleTransfer event")
-			l.submitMessage(l.subscriptions[NonFungibleTransfer](evt, l.log))
+			submit(l.subscriptions[NonFungibleTransfer](evt, l.log))
 		}
 	}
 	if l.subscriptions[GenericTransfer] != nil {
 		for _, evt := range evts.ChainBridge_GenericTransfer {
 			l.log.Trace("Handling GenericTransfer event")
-			l.submitMessage(l.subscriptions[GenericTransfer](evt, l.log))
+			submit(l.subscriptions[GenericTransfer](evt, l.log))
 		}
 	}
 
@@ -238,13 +386,13 @@ func (l *listener) handleEvents(evts utils.Events) {
 }
 
 // submitMessage inserts the chainId into the msg and sends it to the router
-func (l *listener) submitMessage(m msg.Message, err error) {
+func (l *listener) submitMessage(router chains.Router, m msg.Message, err error) {
 	if err != nil {
 		log15.Error("Critical error processing event", "err", err)
 		return
 	}
 	m.Source = l.chainId
-	err = l.router.Send(m)
+	err = router.Send(m)
 	if err != nil {
 		log15.Error("failed to process event", "err", err)
 	}
@@ -4,16 +4,20 @@
 package substrate
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
+	acala "github.com/ChainSafe/ChainBridge/shared/acala"
 	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
 	subtest "github.com/ChainSafe/ChainBridge/shared/substrate/testing"
+	events "github.com/ChainSafe/chainbridge-substrate-events"
 	"github.com/ChainSafe/chainbridge-utils/blockstore"
 	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/scale"
 	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
 )
 
@@ -134,3 +138,151 @@ func Test_GenericTransferEvent(t *testing.T) {
 
 	verifyResultingMessage(t, context.router, context.lSysErr, expected)
 }
+
+func Test_SubscribedListener(t *testing.T) {
+	conn, _, err := createAliceConnection()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	startBlock, err := context.client.LatestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &mockRouter{msgs: make(chan msg.Message)}
+	errs := make(chan error)
+	stop := make(chan int)
+	defer close(stop)
+	l := NewListener(conn, "Alice", 1, startBlock, AliceTestLogger, &blockstore.EmptyStore{}, stop, errs, nil)
+	l.setRouter(r)
+	l.setSubscribe(true)
+	err = l.start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rId msg.ResourceId
+	subtest.QueryConst(t, context.client, "Example", "HashId", &rId)
+	hash := types.NewHash(types.MustHexDecodeString("0x16078eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f2"))
+	context.latestOutNonce = context.latestOutNonce + 1
+	expected := msg.NewGenericTransfer(ThisChain, ForeignChain, context.latestOutNonce, rId, hash[:])
+
+	subtest.InitiateHashTransfer(t, context.client, hash, ForeignChain)
+
+	// Both the polling and the subscribed listener observe the transfer
+	verifyResultingMessage(t, r, errs, expected)
+	verifyResultingMessage(t, context.router, context.lSysErr, expected)
+}
+
+func TestListener_handleEvents(t *testing.T) {
+	amount := big.NewInt(10)
+	tokenId := big.NewInt(1212)
+	recipient := []byte{0xab, 0xcd}
+	metadata := []byte{0x01}
+	hash := types.MustHexDecodeString("0x16078eaf04151687736326c9fea17e25fc5287613693c912909cb226aa4794f2")
+	fungibleId := msg.ResourceIdFromSlice([]byte{1})
+	nonFungibleId := msg.ResourceIdFromSlice([]byte{2})
+	genericId := msg.ResourceIdFromSlice([]byte{3})
+
+	evts := bridgeEvents{}
+	evts.ChainBridge_FungibleTransfer = []events.EventFungibleTransfer{{
+		Destination:  types.U8(ForeignChain),
+		DepositNonce: 1,
+		ResourceId:   types.NewBytes32(fungibleId),
+		Amount:       types.NewU256(*amount),
+		Recipient:    recipient,
+	}}
+	evts.ChainBridge_NonFungibleTransfer = []events.EventNonFungibleTransfer{{
+		Destination:  types.U8(ForeignChain),
+		DepositNonce: 2,
+		ResourceId:   types.NewBytes32(nonFungibleId),
+		TokenId:      tokenId.Bytes(),
+		Recipient:    recipient,
+		Metadata:     metadata,
+	}}
+	evts.ChainBridge_GenericTransfer = []events.EventGenericTransfer{{
+		Destination:  types.U8(ForeignChain),
+		DepositNonce: 3,
+		ResourceId:   types.NewBytes32(genericId),
+		Metadata:     hash,
+	}}
+
+	fungible := msg.NewFungibleTransfer(ThisChain, ForeignChain, 1, amount, fungibleId, recipient)
+	all := []msg.Message{
+		fungible,
+		msg.NewNonFungibleTransfer(ThisChain, ForeignChain, 2, nonFungibleId, tokenId, recipient, metadata),
+		msg.NewGenericTransfer(ThisChain, ForeignChain, 3, genericId, hash),
+	}
+	tests := []struct {
+		profile  *Profile
+		expected []msg.Message
+	}{
+		{ChainSafeProfile, all},
+		{AcalaProfile, all},
+		{KaruraProfile, all},
+		// Transfer types the runtime does not support are ignored
+		{&Profile{Name: "fungible", Transfers: []msg.TransferType{msg.FungibleTransfer}}, []msg.Message{fungible}},
+	}
+
+	for _, tt := range tests {
+		r := &mockRouter{msgs: make(chan msg.Message, 3)}
+		l := NewListener(nil, "Alice", ThisChain, 0, AliceTestLogger, &blockstore.EmptyStore{}, make(chan int), make(chan error), nil)
+		l.setProfile(tt.profile)
+		err := l.registerSubscriptions()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		l.handleEvents(evts, r)
+		close(r.msgs)
+
+		var msgs []msg.Message
+		for m := range r.msgs {
+			msgs = append(msgs, m)
+		}
+		if !reflect.DeepEqual(tt.expected, msgs) {
+			t.Fatalf("%s: messages don't match.\n\tExpected: %#v\n\tGot: %#v\n", tt.profile.Name, tt.expected, msgs)
+		}
+	}
+}
+
+func TestDecodeBridgeEvents(t *testing.T) {
+	AcalaProfile.register()
+
+	meta := types.NewMetadataV12()
+	meta.AsMetadataV12.Modules = []types.ModuleMetadataV12{
+		{Name: "Currencies", Index: 12, HasEvents: true, Events: []types.EventMetadataV4{
+			{Name: "Transferred", Args: []types.Type{"CurrencyIdOf<T>", "T::AccountId", "T::AccountId", "BalanceOf<T>"}},
+		}},
+		{Name: "ChainBridge", Index: 8, HasEvents: true, Events: []types.EventMetadataV4{
+			{Name: "GenericTransfer", Args: []types.Type{"ChainId", "DepositNonce", "ResourceId", "Vec<u8>"}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	encoder := scale.NewEncoder(&buf)
+	phase := types.Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: 2}
+	dexShare := acala.CurrencyId{IsDEXShare: true}
+	dexShare.AsDEXShare.Share_0 = acala.DEXShare{IsToken: true}
+	dexShare.AsDEXShare.Share_1 = acala.DEXShare{IsToken: true, AsToken: 1}
+	for _, v := range []interface{}{
+		types.NewUCompactFromUInt(2),
+		phase, types.EventID{12, 0}, dexShare, types.NewAccountID(make([]byte, 32)), types.NewAccountID(make([]byte, 32)), types.NewU128(*big.NewInt(5)), []types.Hash{},
+		phase, types.EventID{8, 0}, types.U8(2), types.U64(3), types.NewBytes32([32]byte{1}), types.NewBytes([]byte{0xab}), []types.Hash{},
+	} {
+		err := encoder.Encode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e := bridgeEvents{}
+	err := utils.DecodeEvents(meta, buf.Bytes(), &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.ChainBridge_GenericTransfer) != 1 || e.ChainBridge_GenericTransfer[0].DepositNonce != 3 {
+		t.Fatalf("unexpected events %+v", e)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"fmt"
+
+	acala "github.com/ChainSafe/ChainBridge/shared/acala"
+	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// Profile describes the parts of a runtime that differ between chains using the ChainBridge pallet. Events are
+// decoded with the chain metadata, so profiles only name the runtime types used by events that are not known by
+// default.
+type Profile struct {
+	Name string
+	// Transfer types the runtime can send and execute
+	Transfers []msg.TransferType
+	// Arguments of the call executing a fungible transfer
+	FungibleArgs func(recipient types.AccountID, amount types.U128, rId types.Bytes32) []interface{}
+	// Definitions of runtime specific type names, see utils.RegisterTypeDef
+	TypeDefs map[string]string
+}
+
+// supports returns whether the runtime supports a transfer type
+func (p *Profile) supports(t msg.TransferType) bool {
+	for _, transfer := range p.Transfers {
+		if transfer == t {
+			return true
+		}
+	}
+	return false
+}
+
+// register makes the runtime specific types known to the event decoder
+func (p *Profile) register() {
+	for name, def := range p.TypeDefs {
+		utils.RegisterTypeDef(name, def)
+	}
+}
+
+// ChainSafeProfile is the runtime of the ChainSafe example pallet, which supports all transfer types
+var ChainSafeProfile = &Profile{
+	Name:      "chainsafe",
+	Transfers: []msg.TransferType{msg.FungibleTransfer, msg.NonFungibleTransfer, msg.GenericTransfer},
+	FungibleArgs: func(recipient types.AccountID, amount types.U128, _ types.Bytes32) []interface{} {
+		return []interface{}{recipient, amount}
+	},
+}
+
+// AcalaProfile is the Acala runtime, whose ChainSafeTransfer pallet executes fungible transfers by resource id.
+// Non-fungible and generic transfers are executed with the calls of the example pallet.
+var AcalaProfile = &Profile{
+	Name:      "acala",
+	Transfers: []msg.TransferType{msg.FungibleTransfer, msg.NonFungibleTransfer, msg.GenericTransfer},
+	FungibleArgs: func(recipient types.AccountID, amount types.U128, rId types.Bytes32) []interface{} {
+		return []interface{}{recipient, amount, rId}
+	},
+	TypeDefs: acala.TypeDefs,
+}
+
+// KaruraProfile is the Karura runtime, which shares the bridge pallets of Acala
+var KaruraProfile = &Profile{
+	Name:         "karura",
+	Transfers:    AcalaProfile.Transfers,
+	FungibleArgs: AcalaProfile.FungibleArgs,
+	TypeDefs:     acala.TypeDefs,
+}
+
+// Profiles are the runtime profiles that can be selected with the runtime option
+var Profiles = map[string]*Profile{
+	ChainSafeProfile.Name: ChainSafeProfile,
+	AcalaProfile.Name:     AcalaProfile,
+	KaruraProfile.Name:    KaruraProfile,
+}
+
+// DefaultProfile is used when no runtime is configured
+var DefaultProfile = ChainSafeProfile
+
+// lookupProfile returns the profile with the given name
+func lookupProfile(name string) (*Profile, error) {
+	p, ok := Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown runtime %s", name)
+	}
+	return p, nil
+}
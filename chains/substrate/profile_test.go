@@ -0,0 +1,58 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-utils/core"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func TestParseProfile(t *testing.T) {
+	if p := parseProfile(&core.ChainConfig{Opts: map[string]string{}}); p != DefaultProfile {
+		t.Fatalf("Got: %s Expected: %s", p.Name, DefaultProfile.Name)
+	}
+	for name, expected := range Profiles {
+		p := parseProfile(&core.ChainConfig{Opts: map[string]string{"runtime": name}})
+		if p != expected {
+			t.Fatalf("Got: %s Expected: %s", p.Name, expected.Name)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected unknown runtime to panic")
+		}
+	}()
+	parseProfile(&core.ChainConfig{Opts: map[string]string{"runtime": "polkadot"}})
+}
+
+func TestProfiles(t *testing.T) {
+	recipient := types.NewAccountID(make([]byte, 32))
+	amount := types.NewU128(*big.NewInt(10))
+	rId := types.NewBytes32([32]byte{1})
+
+	tests := []struct {
+		profile *Profile
+		args    int
+	}{
+		{ChainSafeProfile, 2},
+		{AcalaProfile, 3},
+		{KaruraProfile, 3},
+	}
+
+	for _, tt := range tests {
+		for _, transfer := range []msg.TransferType{msg.FungibleTransfer, msg.NonFungibleTransfer, msg.GenericTransfer} {
+			if !tt.profile.supports(transfer) {
+				t.Errorf("%s: expected %s to be supported", tt.profile.Name, transfer)
+			}
+		}
+		if args := tt.profile.FungibleArgs(recipient, amount, rId); len(args) != tt.args {
+			t.Errorf("%s: expected %d call arguments, got %d", tt.profile.Name, tt.args, len(args))
+		}
+	}
+}
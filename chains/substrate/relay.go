@@ -0,0 +1,40 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/outbox"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+// DepositsInBlock builds the messages for the deposits of a block as the listener does
+func (c *Chain) DepositsInBlock(block uint64) ([]msg.Message, error) {
+	hash, err := c.conn.api.RPC.Chain.GetBlockHash(block)
+	if err != nil {
+		return nil, err
+	}
+	return c.listener.depositMessages(hash)
+}
+
+// DepositsInTx is not supported, as extrinsics can't be looked up by hash without an indexer
+func (c *Chain) DepositsInTx(_ string) ([]msg.Message, error) {
+	return nil, errors.New("substrate extrinsics can't be looked up by hash, use the block of the deposit instead")
+}
+
+// Relay passes a message to the writer once, as the router does, and returns the latest outbox entry of the
+// message. The writer resolves messages synchronously, so the timeout is not used. Votes are submitted on their
+// own, as the batcher only runs while the chain is started.
+func (c *Chain) Relay(m msg.Message, _ time.Duration) outbox.Entry {
+	if c.relayed == nil {
+		c.relayed = outbox.NewWatcher(c.writer.outbox)
+		c.writer.setOutbox(c.relayed)
+		c.writer.setBatcher(nil)
+	}
+	c.writer.ResolveMessage(m)
+	e, _ := c.relayed.Latest(m)
+	return e
+}
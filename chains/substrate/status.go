@@ -0,0 +1,194 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/ChainBridge/chains"
+	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// FindDeposit looks up the deposit with the nonce to the destination chain and builds its message as the listener does
+func (c *Chain) FindDeposit(dest msg.ChainId, nonce msg.Nonce) (*chains.Deposit, error) {
+	return c.listener.findDeposit(dest, nonce)
+}
+
+// ProposalStatus reports the state of the proposal for a message on this chain
+func (c *Chain) ProposalStatus(m msg.Message) (*chains.Proposal, error) {
+	return c.writer.proposalStatus(m)
+}
+
+// depositMessages returns the messages the listener routes for the deposits of a block
+func (l *listener) depositMessages(hash types.Hash) ([]msg.Message, error) {
+	e := bridgeEvents{}
+	err := l.conn.decodeEvents(hash, &e)
+	if err != nil {
+		return nil, err
+	}
+	collector := &chains.Collector{}
+	l.handleEvents(e, collector)
+	return collector.Messages, nil
+}
+
+// findDeposit searches the nonces of the pallet in historical state for the block of a deposit, so an archive node
+// is required. Blocks before the start block are not searched.
+func (l *listener) findDeposit(dest msg.ChainId, nonce msg.Nonce) (*chains.Deposit, error) {
+	var count types.U64
+	exists, err := l.conn.queryStorage(utils.BridgeStoragePrefix, "ChainNonces", []byte{byte(dest)}, nil, &count)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("chain %d is not whitelisted", dest)
+	}
+	if nonce == 0 || uint64(nonce) > uint64(count) {
+		return nil, fmt.Errorf("no deposit with nonce %d to chain %d, the latest nonce is %d", nonce, dest, count)
+	}
+	header, err := l.conn.api.RPC.Chain.GetHeaderLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	// Search from the block before the start block, to tell deposits made before it apart
+	from := l.startBlock
+	if from > 0 {
+		from--
+	}
+	block, ok, err := chains.FirstBlock(from, uint64(header.Number), func(block uint64) (bool, error) {
+		var count types.U64
+		_, err := l.conn.queryStorageAt(block, utils.BridgeStoragePrefix, "ChainNonces", []byte{byte(dest)}, nil, &count)
+		return uint64(count) >= uint64(nonce), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search chain nonces, an archive node is required: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("deposit with nonce %d to chain %d not found", nonce, dest)
+	}
+	if block < l.startBlock {
+		return nil, fmt.Errorf("deposit with nonce %d to chain %d was made before the start block %d", nonce, dest, l.startBlock)
+	}
+
+	hash, err := l.conn.api.RPC.Chain.GetBlockHash(block)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := l.depositMessages(hash)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range msgs {
+		if m.Destination == dest && m.DepositNonce == nonce {
+			return &chains.Deposit{Message: m, Block: block}, nil
+		}
+	}
+	return nil, fmt.Errorf("deposit event with nonce %d to chain %d not found in block %d, its transfer type may not be supported by the %s runtime", nonce, dest, block, l.profile.Name)
+}
+
+// proposalStatus constructs the proposal for a message as it is voted on and reports its votes
+func (w *writer) proposalStatus(m msg.Message) (*chains.Proposal, error) {
+	prop, err := w.createProposal(m)
+	if err != nil {
+		return nil, err
+	}
+	call, err := types.EncodeToBytes(prop.call)
+	if err != nil {
+		return nil, err
+	}
+	var votes voteState
+	exists, err := w.queryVotes(prop, &votes)
+	if err != nil {
+		return nil, err
+	}
+
+	status := proposalFromVotes(votes, exists)
+	status.Data = fmt.Sprintf("%s %#x", prop.method, call)
+	if exists && votes.Status.IsApproved {
+		err = w.findExecution(prop, status)
+		if err != nil {
+			w.log.Warn("Unable to find execution of proposal", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+		}
+	}
+	return status, nil
+}
+
+// findExecution searches the votes in historical state for the block the proposal was approved in, which is the
+// block it was executed in. The execution is reported as block-extrinsic, proposals whose call failed are marked
+// as failed.
+func (w *writer) findExecution(prop *proposal, status *chains.Proposal) error {
+	srcId, propBz, err := prop.votesKey()
+	if err != nil {
+		return err
+	}
+	header, err := w.conn.api.RPC.Chain.GetHeaderLatest()
+	if err != nil {
+		return err
+	}
+	block, ok, err := chains.FirstBlock(0, uint64(header.Number), func(block uint64) (bool, error) {
+		var votes voteState
+		exists, err := w.conn.queryStorageAt(block, utils.BridgeStoragePrefix, "Votes", srcId, propBz, &votes)
+		return exists && votes.Status.IsApproved, err
+	})
+	if err != nil || !ok {
+		return err
+	}
+
+	hash, err := w.conn.api.RPC.Chain.GetBlockHash(block)
+	if err != nil {
+		return err
+	}
+	e := proposalEvents{}
+	err = w.conn.decodeEvents(hash, &e)
+	if err != nil {
+		return err
+	}
+	for _, evt := range e.ChainBridge_ProposalSucceeded {
+		if evt.SourceId == prop.sourceId && evt.DepositNonce == prop.depositNonce {
+			status.Execution = executionLocation(block, evt.Phase)
+			return nil
+		}
+	}
+	for _, evt := range e.ChainBridge_ProposalFailed {
+		if evt.SourceId == prop.sourceId && evt.DepositNonce == prop.depositNonce {
+			status.Execution = executionLocation(block, evt.Phase)
+			status.Status = "failed"
+			return nil
+		}
+	}
+	return fmt.Errorf("execution event not found in block %d", block)
+}
+
+// proposalFromVotes reports the status and voters of a proposal
+func proposalFromVotes(votes voteState, exists bool) *chains.Proposal {
+	status := &chains.Proposal{Status: "inactive"}
+	if !exists {
+		return status
+	}
+	switch {
+	case votes.Status.IsActive:
+		status.Status = "active"
+	case votes.Status.IsApproved:
+		status.Status = "approved"
+	case votes.Status.IsRejected:
+		status.Status = "rejected"
+	}
+	for _, v := range votes.VotesFor {
+		status.VotesFor = append(status.VotesFor, types.HexEncodeToString(v[:]))
+	}
+	for _, v := range votes.VotesAgainst {
+		status.VotesAgainst = append(status.VotesAgainst, types.HexEncodeToString(v[:]))
+	}
+	return status
+}
+
+// executionLocation identifies the extrinsic emitting an event as block-index
+func executionLocation(block uint64, phase types.Phase) string {
+	if !phase.IsApplyExtrinsic {
+		return fmt.Sprintf("%d", block)
+	}
+	return fmt.Sprintf("%d-%d", block, phase.AsApplyExtrinsic)
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ChainSafe/ChainBridge/chains"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func TestProposalFromVotes(t *testing.T) {
+	alice := types.NewAccountID([]byte{0x01})
+	bob := types.NewAccountID([]byte{0x02})
+
+	testCases := []struct {
+		name     string
+		votes    voteState
+		exists   bool
+		expected *chains.Proposal
+	}{
+		{"no votes", voteState{}, false, &chains.Proposal{Status: "inactive"}},
+		{
+			"active",
+			voteState{VotesFor: []types.AccountID{alice}, VotesAgainst: []types.AccountID{bob}, Status: voteStatus{IsActive: true}},
+			true,
+			&chains.Proposal{Status: "active", VotesFor: []string{types.HexEncodeToString(alice[:])}, VotesAgainst: []string{types.HexEncodeToString(bob[:])}},
+		},
+		{
+			"approved",
+			voteState{VotesFor: []types.AccountID{alice, bob}, Status: voteStatus{IsApproved: true}},
+			true,
+			&chains.Proposal{Status: "approved", VotesFor: []string{types.HexEncodeToString(alice[:]), types.HexEncodeToString(bob[:])}},
+		},
+		{"rejected", voteState{Status: voteStatus{IsRejected: true}}, true, &chains.Proposal{Status: "rejected"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := proposalFromVotes(tc.votes, tc.exists)
+			if !reflect.DeepEqual(tc.expected, res) {
+				t.Fatalf("Expected: %#v\n\tGot: %#v", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestExecutionLocation(t *testing.T) {
+	if res := executionLocation(120, types.Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: 2}); res != "120-2" {
+		t.Errorf("Unexpected location of extrinsic event: %s", res)
+	}
+	if res := executionLocation(120, types.Phase{IsFinalization: true}); res != "120" {
+		t.Errorf("Unexpected location of finalization event: %s", res)
+	}
+}
@@ -4,8 +4,10 @@
 package substrate
 
 import (
+	"fmt"
 	"math/big"
 
+	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
 	"github.com/ChainSafe/chainbridge-utils/msg"
 	"github.com/centrifuge/go-substrate-rpc-client/v3/scale"
 	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
@@ -58,10 +60,101 @@ func (p *proposal) encode() ([]byte, error) {
 	}{p.depositNonce, p.call})
 }
 
+// votesKey returns the keys of the proposal in the Votes storage
+func (p *proposal) votesKey() ([]byte, []byte, error) {
+	srcId, err := types.EncodeToBytes(p.sourceId)
+	if err != nil {
+		return nil, nil, err
+	}
+	propBz, err := p.encode()
+	if err != nil {
+		return nil, nil, err
+	}
+	return srcId, propBz, nil
+}
+
+// InvalidProposalError is returned if a message cannot be turned into a proposal, because it is malformed
+// or refers to a resource that cannot be executed on this chain
+type InvalidProposalError struct {
+	Reason string
+}
+
+func (e *InvalidProposalError) Error() string {
+	return fmt.Sprintf("invalid proposal: %s", e.Reason)
+}
+
+// payloadBytes returns the payload element at index i, which is expected to be a byte slice
+func payloadBytes(m msg.Message, i int) ([]byte, error) {
+	if len(m.Payload) <= i {
+		return nil, &InvalidProposalError{Reason: fmt.Sprintf("payload has %d elements, expected at least %d", len(m.Payload), i+1)}
+	}
+	bz, ok := m.Payload[i].([]byte)
+	if !ok {
+		return nil, &InvalidProposalError{Reason: fmt.Sprintf("payload element %d has type %T, expected bytes", i, m.Payload[i])}
+	}
+	return bz, nil
+}
+
+// recipientAccount returns the account ID a transfer is sent to. Recipients must be 32 byte account IDs, or SS58
+// addresses if decoding is enabled, as any other bytes would send the funds to an account nobody controls.
+func (w *writer) recipientAccount(recipient []byte) (types.AccountID, error) {
+	if len(recipient) == len(types.AccountID{}) {
+		return types.NewAccountID(recipient), nil
+	}
+	if w.ss58 {
+		account, _, err := utils.DecodeSS58(string(recipient))
+		if err != nil {
+			return types.AccountID{}, &InvalidProposalError{Reason: fmt.Sprintf("invalid recipient: %s", err)}
+		}
+		return account, nil
+	}
+	return types.AccountID{}, &InvalidProposalError{Reason: fmt.Sprintf("invalid recipient %#x: expected a 32 byte account ID, got %d bytes", recipient, len(recipient))}
+}
+
+// newProposalCall constructs the call executed by a proposal. Failures are caused by the method or arguments
+// not matching the metadata, so they are reported as invalid proposals.
+func newProposalCall(meta *types.Metadata, method string, args ...interface{}) (types.Call, error) {
+	call, err := types.NewCall(meta, method, args...)
+	if err != nil {
+		return types.Call{}, &InvalidProposalError{Reason: fmt.Sprintf("cannot construct call %s: %s", method, err)}
+	}
+	return call, nil
+}
+
+// scaleAmount converts the amount of a fungible transfer to the decimals of the resource on this chain. Transfers
+// leaving dust under the reject policy and amounts that do not fit into a u128 are invalid proposals.
+func (w *writer) scaleAmount(m msg.Message, amount *big.Int) (*big.Int, error) {
+	scaled, dust, err := w.decimals.ScaleAmount(m.ResourceId, amount, w.dustPolicy)
+	if err != nil {
+		return nil, &InvalidProposalError{Reason: err.Error()}
+	}
+	if dust.Sign() != 0 {
+		w.log.Warn("Rounded down fungible amount, dust remains on the source chain", "src", m.Source, "nonce", m.DepositNonce, "amount", amount, "scaled", scaled, "dust", dust)
+	}
+	if scaled.BitLen() > 128 {
+		return nil, &InvalidProposalError{Reason: fmt.Sprintf("amount %s exceeds u128", scaled)}
+	}
+	return scaled, nil
+}
+
 func (w *writer) createFungibleProposal(m msg.Message) (*proposal, error) {
-	bigAmt := big.NewInt(0).SetBytes(m.Payload[0].([]byte))
+	amt, err := payloadBytes(m, 0)
+	if err != nil {
+		return nil, err
+	}
+	recip, err := payloadBytes(m, 1)
+	if err != nil {
+		return nil, err
+	}
+	bigAmt, err := w.scaleAmount(m, big.NewInt(0).SetBytes(amt))
+	if err != nil {
+		return nil, err
+	}
 	amount := types.NewU128(*bigAmt)
-	recipient := types.NewAccountID(m.Payload[1].([]byte))
+	recipient, err := w.recipientAccount(recip)
+	if err != nil {
+		return nil, err
+	}
 	depositNonce := types.U64(m.DepositNonce)
 
 	meta := w.conn.getMetadata()
@@ -69,11 +162,10 @@ func (w *writer) createFungibleProposal(m msg.Message) (*proposal, error) {
 	if err != nil {
 		return nil, err
 	}
-	call, err := types.NewCall(
+	call, err := newProposalCall(
 		&meta,
 		method,
-		recipient,
-		amount,
+		w.profile.FungibleArgs(recipient, amount, types.NewBytes32(m.ResourceId))...,
 	)
 	if err != nil {
 		return nil, err
@@ -96,9 +188,24 @@ func (w *writer) createFungibleProposal(m msg.Message) (*proposal, error) {
 }
 
 func (w *writer) createNonFungibleProposal(m msg.Message) (*proposal, error) {
-	tokenId := types.NewU256(*big.NewInt(0).SetBytes(m.Payload[0].([]byte)))
-	recipient := types.NewAccountID(m.Payload[1].([]byte))
-	metadata := types.Bytes(m.Payload[2].([]byte))
+	id, err := payloadBytes(m, 0)
+	if err != nil {
+		return nil, err
+	}
+	recip, err := payloadBytes(m, 1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := payloadBytes(m, 2)
+	if err != nil {
+		return nil, err
+	}
+	tokenId := types.NewU256(*big.NewInt(0).SetBytes(id))
+	recipient, err := w.recipientAccount(recip)
+	if err != nil {
+		return nil, err
+	}
+	metadata := types.Bytes(data)
 	depositNonce := types.U64(m.DepositNonce)
 
 	meta := w.conn.getMetadata()
@@ -107,7 +214,7 @@ func (w *writer) createNonFungibleProposal(m msg.Message) (*proposal, error) {
 		return nil, err
 	}
 
-	call, err := types.NewCall(
+	call, err := newProposalCall(
 		&meta,
 		method,
 		recipient,
@@ -135,16 +242,21 @@ func (w *writer) createNonFungibleProposal(m msg.Message) (*proposal, error) {
 }
 
 func (w *writer) createGenericProposal(m msg.Message) (*proposal, error) {
+	hash, err := payloadBytes(m, 0)
+	if err != nil {
+		return nil, err
+	}
+
 	meta := w.conn.getMetadata()
 	method, err := w.resolveResourceId(m.ResourceId)
 	if err != nil {
 		return nil, err
 	}
 
-	call, err := types.NewCall(
+	call, err := newProposalCall(
 		&meta,
 		method,
-		types.NewHash(m.Payload[0].([]byte)),
+		types.NewHash(hash),
 	)
 	if err != nil {
 		return nil, err
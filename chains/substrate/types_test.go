@@ -0,0 +1,110 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package substrate
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ChainSafe/ChainBridge/chains"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func TestPayloadBytes(t *testing.T) {
+	m := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(10), msg.ResourceId{}, []byte{0xab})
+
+	bz, err := payloadBytes(m, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bz, []byte{0xab}) {
+		t.Fatalf("unexpected payload %x", bz)
+	}
+
+	var invalid *InvalidProposalError
+	_, err = payloadBytes(m, 2)
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidProposalError for missing element, got %v", err)
+	}
+
+	m.Payload[0] = "not bytes"
+	_, err = payloadBytes(m, 0)
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidProposalError for wrong type, got %v", err)
+	}
+}
+
+func TestWriter_scaleAmount(t *testing.T) {
+	rId := msg.ResourceIdFromSlice([]byte{0x01})
+	w := NewWriter(nil, AliceTestLogger, nil, nil, false)
+	w.setDecimals(chains.ResourceDecimals{rId: {Source: 18, Destination: 12}}, chains.RejectDust)
+
+	m := msg.NewFungibleTransfer(1, 2, 3, big.NewInt(0), rId, []byte{0xab})
+	amount, err := w.scaleAmount(m, big.NewInt(5000000000000000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount.Cmp(big.NewInt(5000000000000)) != 0 {
+		t.Fatalf("Got: %s Expected: 5000000000000", amount)
+	}
+
+	var invalid *InvalidProposalError
+	_, err = w.scaleAmount(m, big.NewInt(5000000000000000001))
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidProposalError for dust, got %v", err)
+	}
+
+	w.setDecimals(w.decimals, chains.RoundDust)
+	amount, err = w.scaleAmount(m, big.NewInt(5000000000000000001))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount.Cmp(big.NewInt(5000000000000)) != 0 {
+		t.Fatalf("Got: %s Expected: 5000000000000", amount)
+	}
+
+	// Amounts of unconfigured resources are passed through, but must fit into a u128
+	m.ResourceId = msg.ResourceId{}
+	_, err = w.scaleAmount(m, new(big.Int).Lsh(big.NewInt(1), 128))
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidProposalError for u128 overflow, got %v", err)
+	}
+}
+
+func TestWriter_recipientAccount(t *testing.T) {
+	w := NewWriter(nil, AliceTestLogger, nil, nil, false)
+	alice := types.NewAccountID(AliceKey.PublicKey)
+
+	account, err := w.recipientAccount(AliceKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account != alice {
+		t.Fatalf("Got: %x Expected: %x", account, alice)
+	}
+
+	var invalid *InvalidProposalError
+	for _, recipient := range [][]byte{make([]byte, 20), []byte(AliceKey.Address)} {
+		_, err = w.recipientAccount(recipient)
+		if !errors.As(err, &invalid) {
+			t.Errorf("expected InvalidProposalError for %x, got %v", recipient, err)
+		}
+	}
+
+	w.setDecodeSS58(true)
+	account, err = w.recipientAccount([]byte(AliceKey.Address))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account != alice {
+		t.Fatalf("Got: %x Expected: %x", account, alice)
+	}
+	_, err = w.recipientAccount([]byte("not an address"))
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidProposalError for invalid address, got %v", err)
+	}
+}
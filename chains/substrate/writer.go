@@ -11,6 +11,8 @@ import (
 
 	"github.com/ChainSafe/chainbridge-utils/core"
 
+	"github.com/ChainSafe/ChainBridge/chains"
+	"github.com/ChainSafe/ChainBridge/outbox"
 	utils "github.com/ChainSafe/ChainBridge/shared/substrate"
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/chainbridge-utils/msg"
@@ -21,14 +23,24 @@ import (
 var _ core.Writer = &writer{}
 
 var AcknowledgeProposal utils.Method = utils.BridgePalletName + ".acknowledge_proposal"
+var BatchAll utils.Method = "Utility.batch_all"
 var TerminatedError = errors.New("terminated")
 
+const AlreadyVotedReason = "already voted"
+const ProposalCompleteReason = "proposal complete"
+
 type writer struct {
 	conn       *Connection
 	log        log15.Logger
 	sysErr     chan<- error
 	metrics    *metrics.ChainMetrics
-	extendCall bool // Extend extrinsic calls to substrate with ResourceID.Used for backward compatibility with example pallet.
+	extendCall bool                    // Extend extrinsic calls to substrate with ResourceID.Used for backward compatibility with example pallet.
+	outbox     outbox.Outboxer         // Persists the state of messages so they can be resumed after a restart
+	batcher    *callBatcher            // Combines votes into batch extrinsics, nil if batching is disabled
+	profile    *Profile                // Runtime the proposals are constructed for
+	decimals   chains.ResourceDecimals // Decimals used to scale the amounts of fungible transfers
+	dustPolicy string                  // Handling of amounts that lose precision when scaled
+	ss58       bool                    // Accept recipients given as SS58 encoded addresses
 }
 
 func NewWriter(conn *Connection, log log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics, extendCall bool) *writer {
@@ -38,28 +50,73 @@ func NewWriter(conn *Connection, log log15.Logger, sysErr chan<- error, m *metri
 		sysErr:     sysErr,
 		metrics:    m,
 		extendCall: extendCall,
+		outbox:     &outbox.EmptyOutbox{},
+		profile:    DefaultProfile,
+		decimals:   chains.ResourceDecimals{},
+		dustPolicy: chains.RejectDust,
 	}
 }
 
-func (w *writer) ResolveMessage(m msg.Message) bool {
-	var prop *proposal
-	var err error
-
-	// Construct the proposal
-	switch m.Type {
-	case msg.FungibleTransfer:
-		prop, err = w.createFungibleProposal(m)
-	case msg.NonFungibleTransfer:
-		prop, err = w.createNonFungibleProposal(m)
-	case msg.GenericTransfer:
-		prop, err = w.createGenericProposal(m)
-	default:
-		w.sysErr <- fmt.Errorf("unrecognized message type received (chain=%d, name=%s)", m.Destination, w.conn.name)
-		return false
+// setOutbox sets the outbox used to persist message states
+func (w *writer) setOutbox(o outbox.Outboxer) {
+	w.outbox = o
+}
+
+// setProfile sets the runtime the proposals are constructed for
+func (w *writer) setProfile(p *Profile) {
+	w.profile = p
+}
+
+// setDecimals sets the decimals used to scale fungible amounts and how dust lost by scaling is handled
+func (w *writer) setDecimals(decimals chains.ResourceDecimals, dustPolicy string) {
+	w.decimals = decimals
+	w.dustPolicy = dustPolicy
+}
+
+// setDecodeSS58 configures whether recipients given as SS58 addresses are decoded instead of rejected
+func (w *writer) setDecodeSS58(decode bool) {
+	w.ss58 = decode
+}
+
+// setBatcher enables batching of votes
+func (w *writer) setBatcher(b *callBatcher) {
+	w.batcher = b
+}
+
+// start resumes all messages of the outbox that were not completed before the last shutdown
+func (w *writer) start() error {
+	if w.batcher != nil {
+		w.batcher.start()
 	}
 
+	go func() {
+		for _, entry := range w.outbox.Unfinished() {
+			w.log.Info("Resuming message from outbox", "src", entry.Message.Source, "nonce", entry.Message.DepositNonce, "state", entry.State)
+			w.ResolveMessage(entry.Message)
+		}
+	}()
+	return nil
+}
+
+// updateOutbox records the state of a message, failures are only logged
+func (w *writer) updateOutbox(m msg.Message, state outbox.State, reason string) {
+	err := w.outbox.Update(m, state, reason)
 	if err != nil {
-		w.sysErr <- fmt.Errorf("failed to construct proposal (chain=%d, name=%s) Error: %w", m.Destination, w.conn.name, err)
+		w.log.Error("Failed to update outbox", "src", m.Source, "nonce", m.DepositNonce, "state", state, "err", err)
+	}
+}
+
+func (w *writer) ResolveMessage(m msg.Message) bool {
+	w.updateOutbox(m, outbox.Received, "")
+
+	prop, err := w.constructProposal(m)
+	var invalid *InvalidProposalError
+	if errors.As(err, &invalid) {
+		w.handleInvalidProposal(m, invalid)
+		return false
+	} else if err != nil {
+		// The proposal may be valid, the message is left to be resumed on restart
+		w.log.Error("Failed to construct proposal", "src", m.Source, "nonce", m.DepositNonce, "err", err)
 		return false
 	}
 
@@ -76,9 +133,15 @@ func (w *writer) ResolveMessage(m msg.Message) bool {
 		if valid {
 			w.log.Info("Acknowledging proposal on chain", "nonce", prop.depositNonce, "source", prop.sourceId, "resource", fmt.Sprintf("%x", prop.resourceId), "method", prop.method)
 
-			err = w.conn.SubmitTx(AcknowledgeProposal, prop.depositNonce, prop.sourceId, prop.resourceId, prop.call)
+			err = w.submitVote(prop)
+			var dispatchErr *ExtrinsicFailedError
 			if err != nil && err.Error() == TerminatedError.Error() {
 				return false
+			} else if errors.As(err, &dispatchErr) {
+				// The vote was rejected by the chain, resubmitting it would fail the same way
+				w.log.Error("Proposal vote failed", "nonce", prop.depositNonce, "source", prop.sourceId, "module", dispatchErr.Module, "error", dispatchErr.Name, "block", dispatchErr.Block.Hex())
+				w.updateOutbox(m, outbox.Failed, err.Error())
+				return false
 			} else if err != nil {
 				w.log.Error("Failed to execute extrinsic", "err", err)
 				time.Sleep(BlockRetryInterval)
@@ -87,15 +150,76 @@ func (w *writer) ResolveMessage(m msg.Message) bool {
 			if w.metrics != nil {
 				w.metrics.VotesSubmitted.Inc()
 			}
+			w.updateOutbox(m, outbox.Voted, "")
 			return true
 		} else {
 			w.log.Info("Ignoring proposal", "reason", reason, "nonce", prop.depositNonce, "source", prop.sourceId, "resource", prop.resourceId)
+			if reason == ProposalCompleteReason {
+				w.updateOutbox(m, outbox.Executed, "")
+			} else {
+				w.updateOutbox(m, outbox.Voted, "")
+			}
 			return true
 		}
 	}
 	return true
 }
 
+// createProposal constructs the proposal for a message of a transfer type supported by the runtime
+func (w *writer) createProposal(m msg.Message) (*proposal, error) {
+	switch {
+	case !w.profile.supports(m.Type):
+		return nil, &InvalidProposalError{Reason: fmt.Sprintf("transfer type %s is not supported by the %s runtime", m.Type, w.profile.Name)}
+	case m.Type == msg.FungibleTransfer:
+		return w.createFungibleProposal(m)
+	case m.Type == msg.NonFungibleTransfer:
+		return w.createNonFungibleProposal(m)
+	case m.Type == msg.GenericTransfer:
+		return w.createGenericProposal(m)
+	default:
+		return nil, &InvalidProposalError{Reason: fmt.Sprintf("unrecognized message type %s", m.Type)}
+	}
+}
+
+// constructProposal creates the proposal for a message. Errors other than InvalidProposalError, such as failed
+// queries of the chain, are retried up to BlockRetryLimit times.
+func (w *writer) constructProposal(m msg.Message) (*proposal, error) {
+	var invalid *InvalidProposalError
+	for i := 1; ; i++ {
+		prop, err := w.createProposal(m)
+		if err == nil || errors.As(err, &invalid) || i >= BlockRetryLimit {
+			return prop, err
+		}
+		w.log.Error("Failed to construct proposal, retrying", "src", m.Source, "nonce", m.DepositNonce, "err", err)
+		time.Sleep(BlockRetryInterval)
+	}
+}
+
+// handleInvalidProposal parks a message whose proposal could not be constructed in the outbox, with the reason
+func (w *writer) handleInvalidProposal(m msg.Message, invalid *InvalidProposalError) {
+	w.log.Error("Parking invalid proposal", "src", m.Source, "nonce", m.DepositNonce, "reason", invalid.Reason)
+	w.updateOutbox(m, outbox.Held, invalid.Reason)
+}
+
+// submitVote acknowledges the proposal on chain, as part of a batch if batching is enabled
+func (w *writer) submitVote(prop *proposal) error {
+	if w.batcher == nil {
+		return w.conn.SubmitTx(AcknowledgeProposal, prop.depositNonce, prop.sourceId, prop.resourceId, prop.call)
+	}
+
+	call, err := w.conn.newCall(AcknowledgeProposal, prop.depositNonce, prop.sourceId, prop.resourceId, prop.call)
+	if err != nil {
+		return err
+	}
+	err = w.batcher.add(call)
+	if errors.Is(err, ErrBatchNotExecuted) {
+		// Another vote of the batch failed, submit this one on its own so its own outcome is known
+		w.log.Debug("Vote was not executed in batch, submitting individually", "nonce", prop.depositNonce, "source", prop.sourceId)
+		return w.conn.SubmitTx(AcknowledgeProposal, prop.depositNonce, prop.sourceId, prop.resourceId, prop.call)
+	}
+	return err
+}
+
 func (w *writer) resolveResourceId(id [32]byte) (string, error) {
 	var res []byte
 	exists, err := w.conn.queryStorage(utils.BridgeStoragePrefix, "Resources", id[:], nil, &res)
@@ -103,7 +227,7 @@ func (w *writer) resolveResourceId(id [32]byte) (string, error) {
 		return "", err
 	}
 	if !exists {
-		return "", fmt.Errorf("resource %x not found on chain", id)
+		return "", &InvalidProposalError{Reason: fmt.Sprintf("resource %x not found on chain", id)}
 	}
 	return string(res), nil
 }
@@ -112,15 +236,7 @@ func (w *writer) resolveResourceId(id [32]byte) (string, error) {
 // has not voted, it will return true. Otherwise, it will return false with a reason string.
 func (w *writer) proposalValid(prop *proposal) (bool, string, error) {
 	var voteRes voteState
-	srcId, err := types.EncodeToBytes(prop.sourceId)
-	if err != nil {
-		return false, "", err
-	}
-	propBz, err := prop.encode()
-	if err != nil {
-		return false, "", err
-	}
-	exists, err := w.conn.queryStorage(utils.BridgeStoragePrefix, "Votes", srcId, propBz, &voteRes)
+	exists, err := w.queryVotes(prop, &voteRes)
 	if err != nil {
 		return false, "", err
 	}
@@ -130,13 +246,22 @@ func (w *writer) proposalValid(prop *proposal) (bool, string, error) {
 	} else if voteRes.Status.IsActive {
 		if containsVote(voteRes.VotesFor, types.NewAccountID(w.conn.key.PublicKey)) ||
 			containsVote(voteRes.VotesAgainst, types.NewAccountID(w.conn.key.PublicKey)) {
-			return false, "already voted", nil
+			return false, AlreadyVotedReason, nil
 		} else {
 			return true, "", nil
 		}
 	} else {
-		return false, "proposal complete", nil
+		return false, ProposalCompleteReason, nil
+	}
+}
+
+// queryVotes looks up the votes on a proposal, false is returned if no relayer voted yet
+func (w *writer) queryVotes(prop *proposal, votes *voteState) (bool, error) {
+	srcId, propBz, err := prop.votesKey()
+	if err != nil {
+		return false, err
 	}
+	return w.conn.queryStorage(utils.BridgeStoragePrefix, "Votes", srcId, propBz, votes)
 }
 
 func containsVote(votes []types.AccountID, voter types.AccountID) bool {
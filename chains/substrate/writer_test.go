@@ -275,3 +275,20 @@ func TestWriter_ResolveMessage_Duplicate(t *testing.T) {
 	}
 
 }
+
+func TestWriter_ResolveMessage_UnknownResource(t *testing.T) {
+	// Resource is not registered, the message should be parked without a fatal error
+	rId := message.ResourceIdFromSlice([]byte("unknown resource"))
+	m := message.NewFungibleTransfer(ForeignChain, ThisChain, 0, big.NewInt(10), rId, context.writerBob.conn.key.PublicKey)
+
+	ok := context.writerAlice.ResolveMessage(m)
+	if ok {
+		t.Fatal("expected message with unknown resource not to be resolved")
+	}
+
+	select {
+	case err := <-context.wSysErr:
+		t.Fatal(err)
+	default:
+	}
+}
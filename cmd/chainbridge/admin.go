@@ -0,0 +1,62 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/urfave/cli/v2"
+)
+
+var adminCommand = cli.Command{
+	Name:  "admin",
+	Usage: "administer bridge contracts and pallets",
+	Description: "The admin command is used to send administrative transactions to a bridge.\n" +
+		"\tTransactions are signed with a key of the bridge keystore, selected with --from.\n" +
+		"\tTo administer an ethereum bridge contract: chainbridge admin ethereum\n" +
+		"\tTo administer a substrate bridge pallet: chainbridge admin substrate",
+	Subcommands: []*cli.Command{
+		&ethAdminCommand,
+		&subAdminCommand,
+	},
+}
+
+// requireString returns the value of a flag that must be set
+func requireString(ctx *cli.Context, flag *cli.StringFlag) (string, error) {
+	val := ctx.String(flag.Name)
+	if val == "" {
+		return "", fmt.Errorf("--%s is required", flag.Name)
+	}
+	return val, nil
+}
+
+// parseResourceId parses a resource ID given as 32 bytes of hex, with or without a 0x prefix
+func parseResourceId(ctx *cli.Context, flag *cli.StringFlag) (msg.ResourceId, error) {
+	val, err := requireString(ctx, flag)
+	if err != nil {
+		return msg.ResourceId{}, err
+	}
+	bz, err := hex.DecodeString(strings.TrimPrefix(val, "0x"))
+	if err != nil || len(bz) != 32 {
+		return msg.ResourceId{}, fmt.Errorf("invalid --%s %s, expected 32 bytes of hex", flag.Name, val)
+	}
+	return msg.ResourceIdFromSlice(bz), nil
+}
+
+// parseBigInt parses a non-negative decimal integer
+func parseBigInt(ctx *cli.Context, flag *cli.StringFlag) (*big.Int, error) {
+	val, err := requireString(ctx, flag)
+	if err != nil {
+		return nil, err
+	}
+	res, ok := big.NewInt(0).SetString(val, 10)
+	if !ok || res.Sign() < 0 {
+		return nil, fmt.Errorf("invalid --%s %s, expected a non-negative integer", flag.Name, val)
+	}
+	return res, nil
+}
@@ -0,0 +1,416 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/bindings/Bridge"
+	"github.com/ChainSafe/ChainBridge/config"
+	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
+	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
+	"github.com/ChainSafe/chainbridge-utils/keystore"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli/v2"
+)
+
+// Time to wait for the receipt of an admin transaction
+var EthAdminTxTimeout = time.Minute * 5
+
+var ethAdminFlags = []cli.Flag{
+	config.EndpointFlag,
+	config.FromFlag,
+	config.BridgeFlag,
+	config.AdminGasLimitFlag,
+	config.AdminGasPriceFlag,
+}
+
+var ethAdminCommand = cli.Command{
+	Name:  "ethereum",
+	Usage: "administer an ethereum bridge contract",
+	Description: "The ethereum subcommand sends admin transactions to a bridge contract and prints the tx hash and receipt status.\n" +
+		"\tAll subcommands require --url, --from and --bridge. --from selects a secp256k1 key of the keystore,\n" +
+		"\tor use the global --testkey flag to sign with a test key.",
+	Subcommands: []*cli.Command{
+		{
+			Action: wrapHandler(handleEthAddRelayerCmd),
+			Name:   "add-relayer",
+			Usage:  "add a relayer",
+			Flags:  append([]cli.Flag{config.RelayerFlag}, ethAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleEthRemoveRelayerCmd),
+			Name:   "remove-relayer",
+			Usage:  "remove a relayer",
+			Flags:  append([]cli.Flag{config.RelayerFlag}, ethAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleEthSetThresholdCmd),
+			Name:   "set-threshold",
+			Usage:  "set the number of votes required to pass a proposal",
+			Flags:  append([]cli.Flag{config.ThresholdFlag}, ethAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleEthRegisterResourceCmd),
+			Name:   "register-resource",
+			Usage:  "register a resource ID with an ERC20 or ERC721 handler",
+			Flags:  append([]cli.Flag{config.HandlerFlag, config.ResourceIdFlag, config.TargetFlag}, ethAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleEthRegisterGenericResourceCmd),
+			Name:   "register-generic-resource",
+			Usage:  "register a resource ID with the generic handler",
+			Flags:  append([]cli.Flag{config.HandlerFlag, config.ResourceIdFlag, config.TargetFlag, config.DepositSigFlag, config.ExecuteSigFlag}, ethAdminFlags...),
+			Description: "The register-generic-resource subcommand registers a contract with the generic handler.\n" +
+				"\t--deposit and --execute name the functions called on deposits and executions, leave them empty to call no function.",
+		},
+		{
+			Action: wrapHandler(handleEthSetBurnableCmd),
+			Name:   "set-burnable",
+			Usage:  "mark a token contract as burnable in a handler",
+			Flags:  append([]cli.Flag{config.HandlerFlag, config.TargetFlag}, ethAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleEthPauseCmd),
+			Name:   "pause",
+			Usage:  "pause deposits and proposals",
+			Flags:  ethAdminFlags,
+		},
+		{
+			Action: wrapHandler(handleEthUnpauseCmd),
+			Name:   "unpause",
+			Usage:  "unpause deposits and proposals",
+			Flags:  ethAdminFlags,
+		},
+		{
+			Action: wrapHandler(handleEthSetFeeCmd),
+			Name:   "set-fee",
+			Usage:  "set the deposit fee",
+			Flags:  append([]cli.Flag{config.FeeFlag}, ethAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleEthWithdrawCmd),
+			Name:   "withdraw",
+			Usage:  "withdraw tokens held by a handler",
+			Flags:  append([]cli.Flag{config.HandlerFlag, config.TargetFlag, config.RecipientFlag, config.AmountFlag}, ethAdminFlags...),
+		},
+	},
+}
+
+// ethAdmin sends admin transactions to a bridge contract
+type ethAdmin struct {
+	client *utils.Client
+	bridge *Bridge.Bridge
+}
+
+// newEthAdmin connects to the chain and loads the signing key from the keystore
+func newEthAdmin(ctx *cli.Context, dHandler *dataHandler) (*ethAdmin, error) {
+	url, err := requireString(ctx, config.EndpointFlag)
+	if err != nil {
+		return nil, err
+	}
+	bridgeAddr, err := parseEthAddress(ctx, config.BridgeFlag)
+	if err != nil {
+		return nil, err
+	}
+	kp, err := ethKeypair(ctx, dHandler, ctx.String(config.FromFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	client, err := newEthClient(ctx, url, kp)
+	if err != nil {
+		return nil, err
+	}
+
+	bridge, err := Bridge.NewBridge(bridgeAddr, client.Client)
+	if err != nil {
+		return nil, err
+	}
+	return &ethAdmin{client: client, bridge: bridge}, nil
+}
+
+// ethKeypair loads the secp256k1 key of an address from the keystore, or the test key selected with --testkey
+func ethKeypair(ctx *cli.Context, dHandler *dataHandler, from string) (*secp256k1.Keypair, error) {
+	if key := ctx.String(config.TestKeyFlag.Name); key != "" {
+		kp, err := keystore.KeypairFromAddress("", keystore.EthChain, key, true)
+		if err != nil {
+			return nil, err
+		}
+		return kp.(*secp256k1.Keypair), nil
+	}
+	if from == "" {
+		return nil, fmt.Errorf("--%s is required", config.FromFlag.Name)
+	}
+	kp, err := keystore.KeypairFromAddress(from, keystore.EthChain, dHandler.datadir, false)
+	if err != nil {
+		return nil, err
+	}
+	secpKp, ok := kp.(*secp256k1.Keypair)
+	if !ok {
+		return nil, fmt.Errorf("key %s is not a secp256k1 key", from)
+	}
+	return secpKp, nil
+}
+
+// newEthClient connects to the chain and applies the gas flags
+func newEthClient(ctx *cli.Context, url string, kp *secp256k1.Keypair) (*utils.Client, error) {
+	gasPrice, err := parseBigInt(ctx, config.AdminGasPriceFlag)
+	if err != nil {
+		return nil, err
+	}
+	client, err := utils.NewClient(url, kp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+	}
+	client.Opts.GasLimit = ctx.Uint64(config.AdminGasLimitFlag.Name)
+	client.Opts.GasPrice = gasPrice
+	return client, nil
+}
+
+// send submits a transaction with the next nonce of the admin key, waits for its receipt and prints the result
+func (a *ethAdmin) send(submit func(opts *bind.TransactOpts) (*ethtypes.Transaction, error)) error {
+	err := a.client.LockNonceAndUpdate()
+	if err != nil {
+		return err
+	}
+	tx, err := submit(a.client.Opts)
+	a.client.UnlockNonce()
+	if err != nil {
+		return fmt.Errorf("failed to submit transaction: %w", err)
+	}
+	fmt.Printf("Transaction hash: %s\n", tx.Hash().Hex())
+
+	ctx, cancel := context.WithTimeout(context.Background(), EthAdminTxTimeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, a.client.Client, tx)
+	if err != nil {
+		return fmt.Errorf("failed to get receipt of %s: %w", tx.Hash().Hex(), err)
+	}
+
+	status := "success"
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		status = "failed"
+	}
+	fmt.Printf("Receipt status: %s (block %d, gas used %d)\n", status, receipt.BlockNumber, receipt.GasUsed)
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s failed", tx.Hash().Hex())
+	}
+	return nil
+}
+
+// parseEthAddress parses a required address flag
+func parseEthAddress(ctx *cli.Context, flag *cli.StringFlag) (common.Address, error) {
+	val, err := requireString(ctx, flag)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !common.IsHexAddress(val) {
+		return common.Address{}, fmt.Errorf("invalid --%s %s, expected an address", flag.Name, val)
+	}
+	return common.HexToAddress(val), nil
+}
+
+// parseFunctionSig parses a function selector flag, see decodeFunctionSig
+func parseFunctionSig(ctx *cli.Context, flag *cli.StringFlag) ([4]byte, error) {
+	val := ctx.String(flag.Name)
+	sig, err := decodeFunctionSig(val)
+	if err != nil {
+		return sig, fmt.Errorf("invalid --%s %s, expected a function signature or 4 bytes of hex", flag.Name, val)
+	}
+	return sig, nil
+}
+
+// decodeFunctionSig decodes a function selector given as a signature like store(bytes32) or as 4 bytes of hex.
+// An empty value is the zero selector, which makes the generic handler skip the call.
+func decodeFunctionSig(val string) ([4]byte, error) {
+	var sig [4]byte
+	switch {
+	case val == "":
+		return sig, nil
+	case strings.Contains(val, "("):
+		return utils.CreateFunctionSignature(val), nil
+	default:
+		bz, err := hex.DecodeString(strings.TrimPrefix(val, "0x"))
+		if err != nil || len(bz) != len(sig) {
+			return sig, fmt.Errorf("invalid function selector %s", val)
+		}
+		copy(sig[:], bz)
+		return sig, nil
+	}
+}
+
+func handleEthAddRelayerCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	relayer, err := parseEthAddress(ctx, config.RelayerFlag)
+	if err != nil {
+		return err
+	}
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return admin.bridge.AdminAddRelayer(opts, relayer)
+	})
+}
+
+func handleEthRemoveRelayerCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	relayer, err := parseEthAddress(ctx, config.RelayerFlag)
+	if err != nil {
+		return err
+	}
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return admin.bridge.AdminRemoveRelayer(opts, relayer)
+	})
+}
+
+func handleEthSetThresholdCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	threshold := ctx.Uint64(config.ThresholdFlag.Name)
+	if threshold == 0 {
+		return fmt.Errorf("--%s must be greater than 0", config.ThresholdFlag.Name)
+	}
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return admin.bridge.AdminChangeRelayerThreshold(opts, new(big.Int).SetUint64(threshold))
+	})
+}
+
+func handleEthRegisterResourceCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	handler, err := parseEthAddress(ctx, config.HandlerFlag)
+	if err != nil {
+		return err
+	}
+	rId, err := parseResourceId(ctx, config.ResourceIdFlag)
+	if err != nil {
+		return err
+	}
+	target, err := parseEthAddress(ctx, config.TargetFlag)
+	if err != nil {
+		return err
+	}
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return admin.bridge.AdminSetResource(opts, handler, rId, target)
+	})
+}
+
+func handleEthRegisterGenericResourceCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	handler, err := parseEthAddress(ctx, config.HandlerFlag)
+	if err != nil {
+		return err
+	}
+	rId, err := parseResourceId(ctx, config.ResourceIdFlag)
+	if err != nil {
+		return err
+	}
+	target, err := parseEthAddress(ctx, config.TargetFlag)
+	if err != nil {
+		return err
+	}
+	depositSig, err := parseFunctionSig(ctx, config.DepositSigFlag)
+	if err != nil {
+		return err
+	}
+	executeSig, err := parseFunctionSig(ctx, config.ExecuteSigFlag)
+	if err != nil {
+		return err
+	}
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return admin.bridge.AdminSetGenericResource(opts, handler, rId, target, depositSig, executeSig)
+	})
+}
+
+func handleEthSetBurnableCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	handler, err := parseEthAddress(ctx, config.HandlerFlag)
+	if err != nil {
+		return err
+	}
+	target, err := parseEthAddress(ctx, config.TargetFlag)
+	if err != nil {
+		return err
+	}
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return admin.bridge.AdminSetBurnable(opts, handler, target)
+	})
+}
+
+func handleEthPauseCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(admin.bridge.AdminPauseTransfers)
+}
+
+func handleEthUnpauseCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(admin.bridge.AdminUnpauseTransfers)
+}
+
+func handleEthSetFeeCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	fee, err := parseBigInt(ctx, config.FeeFlag)
+	if err != nil {
+		return err
+	}
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return admin.bridge.AdminChangeFee(opts, fee)
+	})
+}
+
+func handleEthWithdrawCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	handler, err := parseEthAddress(ctx, config.HandlerFlag)
+	if err != nil {
+		return err
+	}
+	target, err := parseEthAddress(ctx, config.TargetFlag)
+	if err != nil {
+		return err
+	}
+	recipient, err := parseEthAddress(ctx, config.RecipientFlag)
+	if err != nil {
+		return err
+	}
+	amount, err := parseBigInt(ctx, config.AmountFlag)
+	if err != nil {
+		return err
+	}
+	admin, err := newEthAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	return admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return admin.bridge.AdminWithdraw(opts, handler, target, recipient, amount)
+	})
+}
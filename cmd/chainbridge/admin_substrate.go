@@ -0,0 +1,351 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ChainSafe/ChainBridge/config"
+	acala "github.com/ChainSafe/ChainBridge/shared/acala"
+	subutils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/ChainSafe/chainbridge-utils/crypto/sr25519"
+	"github.com/ChainSafe/chainbridge-utils/keystore"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+	"github.com/urfave/cli/v2"
+)
+
+var subAdminFlags = []cli.Flag{
+	config.EndpointFlag,
+	config.FromFlag,
+	config.RuntimeFlag,
+	config.BatchFlag,
+}
+
+var subAdminCommand = cli.Command{
+	Name:  "substrate",
+	Usage: "administer a substrate bridge pallet",
+	Description: "The substrate subcommand submits sudo calls to the bridge pallet and prints the result of each call.\n" +
+		"\tAll subcommands require --url and --from. --from selects an sr25519 key of the keystore,\n" +
+		"\tor use the global --testkey flag to sign with a test key. --runtime selects the client of the chain.\n" +
+		"\tValues may be repeated to submit several calls, which are sent one after another or all at once with --batch.",
+	Subcommands: []*cli.Command{
+		{
+			Action: wrapHandler(handleSubAddRelayerCmd),
+			Name:   "add-relayer",
+			Usage:  "add relayers",
+			Flags:  append([]cli.Flag{config.SubRelayerFlag}, subAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleSubSetThresholdCmd),
+			Name:   "set-threshold",
+			Usage:  "set the number of votes required to pass a proposal",
+			Flags:  append([]cli.Flag{config.ThresholdFlag}, subAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleSubWhitelistChainCmd),
+			Name:   "whitelist-chain",
+			Usage:  "whitelist chains as transfer destinations",
+			Flags:  append([]cli.Flag{config.ChainIdFlag}, subAdminFlags...),
+		},
+		{
+			Action: wrapHandler(handleSubRegisterResourceCmd),
+			Name:   "register-resource",
+			Usage:  "register resource IDs with the method executing them",
+			Flags:  append([]cli.Flag{config.ResourceFlag}, subAdminFlags...),
+		},
+	},
+}
+
+// subClient is the client of a runtime, building its sudo wrapped admin calls
+type subClient interface {
+	NewAddRelayerCall(relayer types.AccountID) (types.Call, error)
+	NewSetRelayerThresholdCall(threshold types.U32) (types.Call, error)
+	NewWhitelistChainCall(id msg.ChainId) (types.Call, error)
+	NewRegisterResourceCall(id msg.ResourceId, method string) (types.Call, error)
+	LatestBlock() (uint64, error)
+}
+
+// subCall is a call along with a description printed with its result
+type subCall struct {
+	desc string
+	call types.Call
+}
+
+// subAdmin submits admin calls to a bridge pallet
+type subAdmin struct {
+	client subClient
+	submit func(calls []types.Call) ([]subutils.CallResult, error)
+	query  func(prefix, method string, arg1, arg2 []byte, result interface{}) (bool, error)
+	batch  bool
+}
+
+// newSubAdmin loads the signing key from the keystore and connects to the chain with the client of the runtime
+func newSubAdmin(ctx *cli.Context, dHandler *dataHandler) (*subAdmin, error) {
+	url, err := requireString(ctx, config.EndpointFlag)
+	if err != nil {
+		return nil, err
+	}
+	kp, err := subKeypair(ctx, dHandler, ctx.String(config.FromFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	admin, err := connectSubAdmin(ctx.String(config.RuntimeFlag.Name), url, kp.AsKeyringPair())
+	if err != nil {
+		return nil, err
+	}
+	admin.batch = ctx.Bool(config.BatchFlag.Name)
+	return admin, nil
+}
+
+// subKeypair loads the sr25519 key of an address from the keystore, or the test key selected with --testkey
+func subKeypair(ctx *cli.Context, dHandler *dataHandler, from string) (*sr25519.Keypair, error) {
+	if key := ctx.String(config.TestKeyFlag.Name); key != "" {
+		kp, err := keystore.KeypairFromAddress("", keystore.SubChain, key, true)
+		if err != nil {
+			return nil, err
+		}
+		return kp.(*sr25519.Keypair), nil
+	}
+	if from == "" {
+		return nil, fmt.Errorf("--%s is required", config.FromFlag.Name)
+	}
+	kp, err := keystore.KeypairFromAddress(from, keystore.SubChain, dHandler.datadir, false)
+	if err != nil {
+		return nil, err
+	}
+	srKp, ok := kp.(*sr25519.Keypair)
+	if !ok {
+		return nil, fmt.Errorf("key %s is not an sr25519 key", from)
+	}
+	return srKp, nil
+}
+
+// connectSubAdmin creates the client of a runtime
+func connectSubAdmin(runtime, url string, key *signature.KeyringPair) (*subAdmin, error) {
+	switch runtime {
+	case "", "chainsafe":
+		client, err := subutils.CreateClient(key, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+		}
+		return &subAdmin{
+			client: client,
+			submit: func(calls []types.Call) ([]subutils.CallResult, error) { return subutils.BatchSubmit(client, calls) },
+			query: func(prefix, method string, arg1, arg2 []byte, result interface{}) (bool, error) {
+				return subutils.QueryStorage(client, prefix, method, arg1, arg2, result)
+			},
+		}, nil
+	case "acala", "karura":
+		client, err := acala.CreateClient(key, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+		}
+		return &subAdmin{
+			client: client,
+			submit: func(calls []types.Call) ([]acala.CallResult, error) { return acala.BatchSubmit(client, calls) },
+			query: func(prefix, method string, arg1, arg2 []byte, result interface{}) (bool, error) {
+				return acala.QueryStorage(client, prefix, method, arg1, arg2, result)
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown --%s %s, expected chainsafe, acala or karura", config.RuntimeFlag.Name, runtime)
+	}
+}
+
+// run submits the calls, either all at once in batch mode or one after another, and prints the result of each call.
+// An error is returned if any call failed.
+func (a *subAdmin) run(calls []subCall) error {
+	var results []subutils.CallResult
+	if a.batch {
+		batch := make([]types.Call, len(calls))
+		for i, c := range calls {
+			batch[i] = c.call
+		}
+		res, err := a.submit(batch)
+		if err != nil {
+			return err
+		}
+		results = res
+		for i, res := range results {
+			printSubResult(i, calls[i].desc, res)
+		}
+	} else {
+		for i, c := range calls {
+			res, err := a.submit([]types.Call{c.call})
+			if err != nil {
+				return err
+			}
+			printSubResult(i, c.desc, res[0])
+			results = append(results, res[0])
+		}
+	}
+
+	failed := 0
+	for _, res := range results {
+		if res.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d calls failed", failed, len(calls))
+	}
+	return nil
+}
+
+// printSubResult prints the result of the call at index i
+func printSubResult(i int, desc string, res subutils.CallResult) {
+	if res.Err != nil {
+		fmt.Printf("[%d] %s: failed: %s\n", i, desc, res.Err)
+		return
+	}
+	fmt.Printf("[%d] %s: success (block %s)\n", i, desc, res.Block.Hex())
+}
+
+// requireSlice returns the values of a repeatable flag that must be set at least once
+func requireSlice(ctx *cli.Context, flag *cli.StringSliceFlag) ([]string, error) {
+	vals := ctx.StringSlice(flag.Name)
+	if len(vals) == 0 {
+		return nil, fmt.Errorf("--%s is required", flag.Name)
+	}
+	return vals, nil
+}
+
+// parseSubAccount parses an account flag value, see decodeSubAccount
+func parseSubAccount(flag *cli.StringSliceFlag, val string) (types.AccountID, error) {
+	account, err := decodeSubAccount(val)
+	if err != nil {
+		return types.AccountID{}, fmt.Errorf("invalid --%s: %w", flag.Name, err)
+	}
+	return account, nil
+}
+
+// decodeSubAccount decodes an account given as an SS58 address or as 32 bytes of hex
+func decodeSubAccount(val string) (types.AccountID, error) {
+	if strings.HasPrefix(val, "0x") {
+		bz, err := hex.DecodeString(strings.TrimPrefix(val, "0x"))
+		if err != nil || len(bz) != 32 {
+			return types.AccountID{}, fmt.Errorf("account %s is not 32 bytes of hex", val)
+		}
+		return types.NewAccountID(bz), nil
+	}
+	account, _, err := subutils.DecodeSS58(val)
+	return account, err
+}
+
+// parseSubResource parses a resource given as resourceId:method
+func parseSubResource(flag *cli.StringSliceFlag, val string) (msg.ResourceId, string, error) {
+	parts := strings.SplitN(val, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return msg.ResourceId{}, "", fmt.Errorf("invalid --%s %s, expected resourceId:method", flag.Name, val)
+	}
+	bz, err := hex.DecodeString(strings.TrimPrefix(parts[0], "0x"))
+	if err != nil || len(bz) != 32 {
+		return msg.ResourceId{}, "", fmt.Errorf("invalid --%s %s, expected a resource ID of 32 bytes of hex", flag.Name, val)
+	}
+	return msg.ResourceIdFromSlice(bz), parts[1], nil
+}
+
+func handleSubAddRelayerCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	vals, err := requireSlice(ctx, config.SubRelayerFlag)
+	if err != nil {
+		return err
+	}
+	relayers := make([]types.AccountID, len(vals))
+	for i, val := range vals {
+		relayers[i], err = parseSubAccount(config.SubRelayerFlag, val)
+		if err != nil {
+			return err
+		}
+	}
+	admin, err := newSubAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	calls := make([]subCall, len(relayers))
+	for i, relayer := range relayers {
+		call, err := admin.client.NewAddRelayerCall(relayer)
+		if err != nil {
+			return err
+		}
+		calls[i] = subCall{desc: fmt.Sprintf("add relayer %s", vals[i]), call: call}
+	}
+	return admin.run(calls)
+}
+
+func handleSubSetThresholdCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	threshold := ctx.Uint64(config.ThresholdFlag.Name)
+	if threshold == 0 || threshold > uint64(^uint32(0)) {
+		return fmt.Errorf("--%s must be between 1 and %d", config.ThresholdFlag.Name, ^uint32(0))
+	}
+	admin, err := newSubAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	call, err := admin.client.NewSetRelayerThresholdCall(types.U32(threshold))
+	if err != nil {
+		return err
+	}
+	return admin.run([]subCall{{desc: fmt.Sprintf("set threshold %d", threshold), call: call}})
+}
+
+func handleSubWhitelistChainCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	vals, err := requireSlice(ctx, config.ChainIdFlag)
+	if err != nil {
+		return err
+	}
+	ids := make([]msg.ChainId, len(vals))
+	for i, val := range vals {
+		id, err := strconv.ParseUint(val, 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid --%s %s, expected a chain ID between 0 and 255", config.ChainIdFlag.Name, val)
+		}
+		ids[i] = msg.ChainId(id)
+	}
+	admin, err := newSubAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	calls := make([]subCall, len(ids))
+	for i, id := range ids {
+		call, err := admin.client.NewWhitelistChainCall(id)
+		if err != nil {
+			return err
+		}
+		calls[i] = subCall{desc: fmt.Sprintf("whitelist chain %d", id), call: call}
+	}
+	return admin.run(calls)
+}
+
+func handleSubRegisterResourceCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	vals, err := requireSlice(ctx, config.ResourceFlag)
+	if err != nil {
+		return err
+	}
+	rIds := make([]msg.ResourceId, len(vals))
+	methods := make([]string, len(vals))
+	for i, val := range vals {
+		rIds[i], methods[i], err = parseSubResource(config.ResourceFlag, val)
+		if err != nil {
+			return err
+		}
+	}
+	admin, err := newSubAdmin(ctx, dHandler)
+	if err != nil {
+		return err
+	}
+	calls := make([]subCall, len(rIds))
+	for i := range rIds {
+		call, err := admin.client.NewRegisterResourceCall(rIds[i], methods[i])
+		if err != nil {
+			return err
+		}
+		calls[i] = subCall{desc: fmt.Sprintf("register resource %x as %s", rIds[i], methods[i]), call: call}
+	}
+	return admin.run(calls)
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/ChainBridge/config"
+	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
+	subutils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAdminCommands(t *testing.T) {
+	for _, group := range adminCommand.Subcommands {
+		for _, cmd := range group.Subcommands {
+			if cmd.Action == nil {
+				t.Errorf("admin %s %s has no action", group.Name, cmd.Name)
+			}
+		}
+	}
+}
+
+func TestParseAdminFlags(t *testing.T) {
+	rId := "0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00"
+	ctx, err := newTestContext("admin",
+		[]string{"resourceId", "handler", "amount", "deposit", "execute"},
+		[]interface{}{rId, "0x1111111111111111111111111111111111111111", "1000", "store(bytes32)", "0x12345678"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedRId, err := parseResourceId(ctx, config.ResourceIdFlag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsedRId != msg.ResourceIdFromSlice(common.FromHex(rId)) {
+		t.Fatalf("Unexpected resource ID %x", parsedRId)
+	}
+
+	handler, err := parseEthAddress(ctx, config.HandlerFlag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handler != common.HexToAddress("0x1111111111111111111111111111111111111111") {
+		t.Fatalf("Unexpected handler %s", handler.Hex())
+	}
+
+	amount, err := parseBigInt(ctx, config.AmountFlag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount.Int64() != 1000 {
+		t.Fatalf("Unexpected amount %s", amount)
+	}
+
+	depositSig, err := parseFunctionSig(ctx, config.DepositSigFlag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if depositSig != utils.StoreFunctionSig {
+		t.Fatalf("Unexpected deposit signature %x", depositSig)
+	}
+	executeSig, err := parseFunctionSig(ctx, config.ExecuteSigFlag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if executeSig != [4]byte{0x12, 0x34, 0x56, 0x78} {
+		t.Fatalf("Unexpected execute signature %x", executeSig)
+	}
+}
+
+func TestParseAdminFlags_Invalid(t *testing.T) {
+	ctx, err := newTestContext("admin",
+		[]string{"resourceId", "handler", "amount", "deposit"},
+		[]interface{}{"0x1234", "notanaddress", "-1", "0x1234"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parseResourceId(ctx, config.ResourceIdFlag); err == nil {
+		t.Error("Expected short resource ID to be rejected")
+	}
+	if _, err := parseEthAddress(ctx, config.HandlerFlag); err == nil {
+		t.Error("Expected invalid address to be rejected")
+	}
+	if _, err := parseBigInt(ctx, config.AmountFlag); err == nil {
+		t.Error("Expected negative amount to be rejected")
+	}
+	if _, err := parseFunctionSig(ctx, config.DepositSigFlag); err == nil {
+		t.Error("Expected short function selector to be rejected")
+	}
+
+	// Missing flags
+	ctx, err = newTestContext("admin", []string{"bridge"}, []interface{}{""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseEthAddress(ctx, config.BridgeFlag); err == nil {
+		t.Error("Expected missing bridge to be rejected")
+	}
+}
+
+func TestParseSubAccount(t *testing.T) {
+	alice := types.NewAccountID(common.FromHex("0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d"))
+
+	for _, val := range []string{
+		"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+		"0xd43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d",
+	} {
+		account, err := parseSubAccount(config.SubRelayerFlag, val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if account != alice {
+			t.Errorf("Unexpected account %x for %s", account, val)
+		}
+	}
+
+	for _, val := range []string{"0x1234", "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ", "notanaddress"} {
+		if _, err := parseSubAccount(config.SubRelayerFlag, val); err == nil {
+			t.Errorf("Expected %s to be rejected", val)
+		}
+	}
+}
+
+func TestParseSubResource(t *testing.T) {
+	rId := "0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00"
+	parsedRId, method, err := parseSubResource(config.ResourceFlag, rId+":Example.transfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsedRId != msg.ResourceIdFromSlice(common.FromHex(rId)) {
+		t.Errorf("Unexpected resource ID %x", parsedRId)
+	}
+	if method != "Example.transfer" {
+		t.Errorf("Unexpected method %s", method)
+	}
+
+	for _, val := range []string{rId, rId + ":", "0x1234:Example.transfer"} {
+		if _, _, err := parseSubResource(config.ResourceFlag, val); err == nil {
+			t.Errorf("Expected %s to be rejected", val)
+		}
+	}
+}
+
+func TestSubAdminRun(t *testing.T) {
+	calls := []subCall{{desc: "first"}, {desc: "second"}, {desc: "third"}}
+
+	for _, batch := range []bool{false, true} {
+		var submitted [][]types.Call
+		admin := &subAdmin{
+			batch: batch,
+			submit: func(calls []types.Call) ([]subutils.CallResult, error) {
+				submitted = append(submitted, calls)
+				results := make([]subutils.CallResult, len(calls))
+				for i := range results {
+					// The second call fails in both modes
+					if batch && i == 1 || !batch && len(submitted) == 2 {
+						results[i].Err = errors.New("sudo call failed: Bridge.ResourceDoesNotExist")
+					}
+				}
+				return results, nil
+			},
+		}
+
+		err := admin.run(calls)
+		if err == nil || err.Error() != "1 of 3 calls failed" {
+			t.Errorf("Unexpected error with batch %t: %v", batch, err)
+		}
+		if batch && len(submitted) != 1 || !batch && len(submitted) != 3 {
+			t.Errorf("Unexpected number of submissions with batch %t: %d", batch, len(submitted))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/ChainBridge/chains/ethereum"
+	"github.com/ChainSafe/ChainBridge/config"
+	log "github.com/ChainSafe/log15"
+	"github.com/urfave/cli/v2"
+)
+
+var deployCommand = cli.Command{
+	Action: wrapHandler(handleDeployCmd),
+	Name:   "deploy",
+	Usage:  "deploy and configure a bridge from a deployment spec",
+	Flags:  []cli.Flag{config.DeploySpecFlag, config.AdminGasLimitFlag, config.AdminGasPriceFlag},
+	Description: "The deploy command deploys the bridge contracts of ethereum chains and configures the relayers, threshold\n" +
+		"\tand resources of all chains in the spec. Addresses of deployed contracts are written back to the spec and only\n" +
+		"\tmissing configuration is applied, so the command can be run again after a failure or a change of the spec.\n" +
+		"\tThe relayer config is written to the path of the global --config flag, ./config.json by default.\n" +
+		"\tThe from key of each chain is loaded from the keystore, or use the global --testkey flag to sign with a test key.",
+}
+
+func handleDeployCmd(ctx *cli.Context, dHandler *dataHandler) error {
+	path, err := requireString(ctx, config.DeploySpecFlag)
+	if err != nil {
+		return err
+	}
+	spec, err := config.LoadDeploySpec(path)
+	if err != nil {
+		return err
+	}
+	save := func() error { return spec.Write(path) }
+
+	for i := range spec.Chains {
+		chain := &spec.Chains[i]
+		log.Info("Deploying chain", "id", chain.Id, "name", chain.Name, "type", chain.Type)
+		switch chain.Type {
+		case "ethereum":
+			err = deployEthereum(ctx, dHandler, spec, chain, save)
+		case "substrate":
+			err = deploySubstrate(ctx, dHandler, spec, chain, save)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to deploy chain %s: %w", chain.Id, err)
+		}
+	}
+
+	out := ctx.String(config.ConfigFileFlag.Name)
+	if out == "" {
+		out = config.DefaultConfigPath
+	}
+	err = relayerConfig(spec).Write(out)
+	if err != nil {
+		return fmt.Errorf("failed to write relayer config: %w", err)
+	}
+	log.Info("Wrote relayer config", "path", out)
+	return nil
+}
+
+// relayerConfig builds the relayer config of a deployed spec. The opts of each chain are copied and extended with
+// the contract addresses and the block the bridge was deployed at.
+func relayerConfig(spec *config.DeploySpec) *config.Config {
+	cfg := config.NewConfig()
+	for _, chain := range spec.Chains {
+		opts := make(map[string]string)
+		for k, v := range chain.Opts {
+			opts[k] = v
+		}
+		if chain.StartBlock != "" {
+			opts[ethereum.StartBlockOpt] = chain.StartBlock
+		}
+		if chain.Type == "ethereum" {
+			opts[ethereum.BridgeOpt] = chain.Contracts.Bridge
+			opts[ethereum.Erc20HandlerOpt] = chain.Contracts.Erc20Handler
+			opts[ethereum.Erc721HandlerOpt] = chain.Contracts.Erc721Handler
+			opts[ethereum.GenericHandlerOpt] = chain.Contracts.GenericHandler
+		}
+		cfg.Chains = append(cfg.Chains, config.RawChainConfig{
+			Name:     chain.Name,
+			Type:     chain.Type,
+			Id:       chain.Id,
+			Endpoint: chain.Endpoint,
+			From:     chain.RelayerFrom(),
+			Opts:     opts,
+		})
+	}
+	return cfg
+}
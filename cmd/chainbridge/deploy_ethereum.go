@@ -0,0 +1,315 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ChainSafe/ChainBridge/bindings/Bridge"
+	"github.com/ChainSafe/ChainBridge/bindings/ERC20Handler"
+	ERC20 "github.com/ChainSafe/ChainBridge/bindings/ERC20PresetMinterPauser"
+	"github.com/ChainSafe/ChainBridge/bindings/ERC721Handler"
+	"github.com/ChainSafe/ChainBridge/bindings/ERC721MinterBurnerPauser"
+	"github.com/ChainSafe/ChainBridge/bindings/GenericHandler"
+	"github.com/ChainSafe/ChainBridge/config"
+	utils "github.com/ChainSafe/ChainBridge/shared/ethereum"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	log "github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/urfave/cli/v2"
+)
+
+// tokenHandler is the part of the ERC20 and ERC721 handlers used to check a token resource
+type tokenHandler interface {
+	ResourceIDToTokenContractAddress(opts *bind.CallOpts, arg0 [32]byte) (common.Address, error)
+	BurnList(opts *bind.CallOpts, arg0 common.Address) (bool, error)
+}
+
+// mintableToken is the part of the ERC20 and ERC721 tokens used to make a handler a minter
+type mintableToken interface {
+	MINTERROLE(opts *bind.CallOpts) ([32]byte, error)
+	HasRole(opts *bind.CallOpts, role [32]byte, account common.Address) (bool, error)
+	GrantRole(opts *bind.TransactOpts, role [32]byte, account common.Address) (*ethtypes.Transaction, error)
+}
+
+// deployEthereum deploys the bridge contracts missing from the spec and applies the relayers, threshold and
+// resources of the chain that differ from the contract state
+func deployEthereum(ctx *cli.Context, dHandler *dataHandler, spec *config.DeploySpec, chain *config.DeployChain, save func() error) error {
+	kp, err := ethKeypair(ctx, dHandler, chain.From)
+	if err != nil {
+		return err
+	}
+	client, err := newEthClient(ctx, chain.Endpoint, kp)
+	if err != nil {
+		return err
+	}
+	relayers := make([]common.Address, len(chain.Relayers))
+	for i, relayer := range chain.Relayers {
+		if !common.IsHexAddress(relayer) {
+			return fmt.Errorf("invalid relayer %s, expected an address", relayer)
+		}
+		relayers[i] = common.HexToAddress(relayer)
+	}
+	id, err := strconv.ParseUint(chain.Id, 10, 8)
+	if err != nil {
+		return err
+	}
+	threshold := new(big.Int).SetUint64(uint64(spec.Threshold))
+
+	contracts := &chain.Contracts
+	bridgeAddr, err := ensureContract(client, "Bridge", &contracts.Bridge, save, func() (common.Address, error) {
+		// Deposits can't be made before the bridge exists, so the relayer starts at the current block
+		head, err := client.Client.BlockNumber(context.Background())
+		if err != nil {
+			return utils.ZeroAddress, err
+		}
+		chain.StartBlock = strconv.FormatUint(head, 10)
+		return utils.DeployBridge(client, uint8(id), relayers, threshold)
+	})
+	if err != nil {
+		return err
+	}
+	erc20HandlerAddr, err := ensureContract(client, "ERC20Handler", &contracts.Erc20Handler, save, func() (common.Address, error) {
+		return utils.DeployERC20Handler(client, bridgeAddr)
+	})
+	if err != nil {
+		return err
+	}
+	erc721HandlerAddr, err := ensureContract(client, "ERC721Handler", &contracts.Erc721Handler, save, func() (common.Address, error) {
+		return utils.DeployERC721Handler(client, bridgeAddr)
+	})
+	if err != nil {
+		return err
+	}
+	genericHandlerAddr, err := ensureContract(client, "GenericHandler", &contracts.GenericHandler, save, func() (common.Address, error) {
+		return utils.DeployGenericHandler(client, bridgeAddr)
+	})
+	if err != nil {
+		return err
+	}
+
+	bridge, err := Bridge.NewBridge(bridgeAddr, client.Client)
+	if err != nil {
+		return err
+	}
+	admin := &ethAdmin{client: client, bridge: bridge}
+
+	for _, relayer := range relayers {
+		isRelayer, err := bridge.IsRelayer(client.CallOpts, relayer)
+		if err != nil {
+			return err
+		}
+		if isRelayer {
+			continue
+		}
+		log.Info("Adding relayer", "relayer", relayer.Hex())
+		err = admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			return bridge.AdminAddRelayer(opts, relayer)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	current, err := bridge.RelayerThreshold(client.CallOpts)
+	if err != nil {
+		return err
+	}
+	if current.Cmp(threshold) != 0 {
+		log.Info("Changing relayer threshold", "from", current, "to", threshold)
+		err = admin.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			return bridge.AdminChangeRelayerThreshold(opts, threshold)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range chain.Resources {
+		res := &chain.Resources[i]
+		switch res.Type {
+		case config.Erc20Resource:
+			handler, err := ERC20Handler.NewERC20Handler(erc20HandlerAddr, client.Client)
+			if err != nil {
+				return err
+			}
+			token, err := ensureContract(client, "ERC20 "+res.Symbol, &res.Address, save, func() (common.Address, error) {
+				return utils.DeployErc20(client, res.Name, res.Symbol)
+			})
+			if err != nil {
+				return err
+			}
+			instance, err := ERC20.NewERC20PresetMinterPauser(token, client.Client)
+			if err != nil {
+				return err
+			}
+			err = admin.ensureTokenResource(res, erc20HandlerAddr, handler, token, instance)
+			if err != nil {
+				return err
+			}
+		case config.Erc721Resource:
+			handler, err := ERC721Handler.NewERC721Handler(erc721HandlerAddr, client.Client)
+			if err != nil {
+				return err
+			}
+			token, err := ensureContract(client, "ERC721 "+res.Symbol, &res.Address, save, func() (common.Address, error) {
+				return utils.DeployErc721(client, res.Name, res.Symbol)
+			})
+			if err != nil {
+				return err
+			}
+			instance, err := ERC721MinterBurnerPauser.NewERC721MinterBurnerPauser(token, client.Client)
+			if err != nil {
+				return err
+			}
+			err = admin.ensureTokenResource(res, erc721HandlerAddr, handler, token, instance)
+			if err != nil {
+				return err
+			}
+		case config.GenericResource:
+			err = admin.ensureGenericResource(res, genericHandlerAddr)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ensureContract deploys a contract unless the spec has its address, which must then hold code. The address of a
+// deployed contract is written back to the spec.
+func ensureContract(client *utils.Client, name string, addr *string, save func() error, deploy func() (common.Address, error)) (common.Address, error) {
+	if *addr != "" {
+		if !common.IsHexAddress(*addr) {
+			return utils.ZeroAddress, fmt.Errorf("invalid %s address %s", name, *addr)
+		}
+		existing := common.HexToAddress(*addr)
+		code, err := client.Client.CodeAt(context.Background(), existing, nil)
+		if err != nil {
+			return utils.ZeroAddress, err
+		}
+		if len(code) == 0 {
+			return utils.ZeroAddress, fmt.Errorf("no %s contract at %s", name, existing.Hex())
+		}
+		log.Info("Using deployed contract", "contract", name, "address", existing.Hex())
+		return existing, nil
+	}
+
+	deployed, err := deploy()
+	if err != nil {
+		return utils.ZeroAddress, fmt.Errorf("failed to deploy %s: %w", name, err)
+	}
+	log.Info("Deployed contract", "contract", name, "address", deployed.Hex())
+	*addr = deployed.Hex()
+	return deployed, save()
+}
+
+// ensureTokenResource registers a token with its handler. Burnable tokens are marked as burnable in the handler,
+// which is made a minter of the token.
+func (a *ethAdmin) ensureTokenResource(res *config.DeployResource, handlerAddr common.Address, handler tokenHandler, token common.Address, instance mintableToken) error {
+	rId := msg.ResourceIdFromSlice(common.FromHex(res.Id))
+	registeredHandler, err := a.bridge.ResourceIDToHandlerAddress(a.client.CallOpts, rId)
+	if err != nil {
+		return err
+	}
+	registeredToken, err := handler.ResourceIDToTokenContractAddress(a.client.CallOpts, rId)
+	if err != nil {
+		return err
+	}
+	if registeredHandler != handlerAddr || registeredToken != token {
+		log.Info("Registering resource", "resourceId", res.Id, "token", token.Hex())
+		err = a.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			return a.bridge.AdminSetResource(opts, handlerAddr, rId, token)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if !res.Burnable {
+		return nil
+	}
+
+	burnable, err := handler.BurnList(a.client.CallOpts, token)
+	if err != nil {
+		return err
+	}
+	if !burnable {
+		log.Info("Marking token as burnable", "token", token.Hex())
+		err = a.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			return a.bridge.AdminSetBurnable(opts, handlerAddr, token)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	role, err := instance.MINTERROLE(a.client.CallOpts)
+	if err != nil {
+		return err
+	}
+	minter, err := instance.HasRole(a.client.CallOpts, role, handlerAddr)
+	if err != nil {
+		return err
+	}
+	if !minter {
+		log.Info("Adding handler as minter", "token", token.Hex(), "handler", handlerAddr.Hex())
+		err = a.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			return instance.GrantRole(opts, role, handlerAddr)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureGenericResource registers a contract and its functions with the generic handler
+func (a *ethAdmin) ensureGenericResource(res *config.DeployResource, handlerAddr common.Address) error {
+	if !common.IsHexAddress(res.Address) {
+		return fmt.Errorf("invalid address %s of resource %s", res.Address, res.Id)
+	}
+	target := common.HexToAddress(res.Address)
+	depositSig, err := decodeFunctionSig(res.DepositSig)
+	if err != nil {
+		return err
+	}
+	executeSig, err := decodeFunctionSig(res.ExecuteSig)
+	if err != nil {
+		return err
+	}
+	handler, err := GenericHandler.NewGenericHandler(handlerAddr, a.client.Client)
+	if err != nil {
+		return err
+	}
+
+	rId := msg.ResourceIdFromSlice(common.FromHex(res.Id))
+	registeredHandler, err := a.bridge.ResourceIDToHandlerAddress(a.client.CallOpts, rId)
+	if err != nil {
+		return err
+	}
+	registeredTarget, err := handler.ResourceIDToContractAddress(a.client.CallOpts, rId)
+	if err != nil {
+		return err
+	}
+	registeredDeposit, err := handler.ContractAddressToDepositFunctionSignature(a.client.CallOpts, target)
+	if err != nil {
+		return err
+	}
+	registeredExecute, err := handler.ContractAddressToExecuteFunctionSignature(a.client.CallOpts, target)
+	if err != nil {
+		return err
+	}
+	if registeredHandler == handlerAddr && registeredTarget == target && registeredDeposit == depositSig && registeredExecute == executeSig {
+		return nil
+	}
+
+	log.Info("Registering generic resource", "resourceId", res.Id, "contract", target.Hex())
+	return a.send(func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+		return a.bridge.AdminSetGenericResource(opts, handlerAddr, rId, target, depositSig, executeSig)
+	})
+}
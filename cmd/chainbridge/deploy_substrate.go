@@ -0,0 +1,139 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/ChainSafe/ChainBridge/config"
+	subutils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	log "github.com/ChainSafe/log15"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+)
+
+// deploySubstrate applies the relayers, whitelisted chains, resources and threshold of the chain that differ from the
+// pallet state. All other chains of the spec are whitelisted.
+func deploySubstrate(ctx *cli.Context, dHandler *dataHandler, spec *config.DeploySpec, chain *config.DeployChain, save func() error) error {
+	kp, err := subKeypair(ctx, dHandler, chain.From)
+	if err != nil {
+		return err
+	}
+	admin, err := connectSubAdmin(chain.Opts["runtime"], chain.Endpoint, kp.AsKeyringPair())
+	if err != nil {
+		return err
+	}
+	admin.batch = true
+
+	if chain.StartBlock == "" {
+		head, err := admin.client.LatestBlock()
+		if err != nil {
+			return err
+		}
+		chain.StartBlock = strconv.FormatUint(head, 10)
+		err = save()
+		if err != nil {
+			return err
+		}
+	}
+
+	var chains []msg.ChainId
+	for _, other := range spec.Chains {
+		if other.Id == chain.Id {
+			continue
+		}
+		id, err := strconv.ParseUint(other.Id, 10, 8)
+		if err != nil {
+			return err
+		}
+		chains = append(chains, msg.ChainId(id))
+	}
+
+	calls, err := admin.deployCalls(chain, chains, spec.Threshold)
+	if err != nil {
+		return err
+	}
+	if len(calls) == 0 {
+		log.Info("Bridge pallet is configured", "chain", chain.Id)
+		return nil
+	}
+	return admin.run(calls)
+}
+
+// deployCalls returns the calls for the relayers, chains, resources and threshold that are missing from the pallet
+func (a *subAdmin) deployCalls(chain *config.DeployChain, chains []msg.ChainId, threshold uint32) ([]subCall, error) {
+	var calls []subCall
+
+	for _, relayer := range chain.Relayers {
+		account, err := decodeSubAccount(relayer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relayer: %w", err)
+		}
+		var isRelayer types.Bool
+		exists, err := a.query(subutils.BridgeStoragePrefix, "Relayers", account[:], nil, &isRelayer)
+		if err != nil {
+			return nil, err
+		}
+		if exists && bool(isRelayer) {
+			continue
+		}
+		call, err := a.client.NewAddRelayerCall(account)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, subCall{desc: fmt.Sprintf("add relayer %s", relayer), call: call})
+	}
+
+	for _, id := range chains {
+		var nonce types.U64
+		exists, err := a.query(subutils.BridgeStoragePrefix, "ChainNonces", []byte{byte(id)}, nil, &nonce)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+		call, err := a.client.NewWhitelistChainCall(id)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, subCall{desc: fmt.Sprintf("whitelist chain %d", id), call: call})
+	}
+
+	for _, res := range chain.Resources {
+		rId := msg.ResourceIdFromSlice(common.FromHex(res.Id))
+		var method types.Bytes
+		exists, err := a.query(subutils.BridgeStoragePrefix, "Resources", rId[:], nil, &method)
+		if err != nil {
+			return nil, err
+		}
+		if exists && bytes.Equal(method, []byte(res.Method)) {
+			continue
+		}
+		call, err := a.client.NewRegisterResourceCall(rId, res.Method)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, subCall{desc: fmt.Sprintf("register resource %x as %s", rId, res.Method), call: call})
+	}
+
+	// The threshold is set last, once the relayers voting with it were added
+	var current types.U32
+	exists, err := a.query(subutils.BridgeStoragePrefix, "RelayerThreshold", nil, nil, &current)
+	if err != nil {
+		return nil, err
+	}
+	if !exists || uint32(current) != threshold {
+		call, err := a.client.NewSetRelayerThresholdCall(types.U32(threshold))
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, subCall{desc: fmt.Sprintf("set threshold %d", threshold), call: call})
+	}
+	return calls, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ChainSafe/ChainBridge/config"
+	subutils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	testAlice = "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"
+	testBob   = "5FHneW46xGXgs5mUiveU4sbTyGBzmstUspZC92UhjJM694ty"
+	testRId   = "0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00"
+)
+
+func TestRelayerConfig(t *testing.T) {
+	spec := &config.DeploySpec{
+		Threshold: 1,
+		Chains: []config.DeployChain{
+			{
+				Name:     "eth",
+				Type:     "ethereum",
+				Id:       "0",
+				Endpoint: "ws://localhost:8545",
+				From:     "0xff93B45308FD417dF303D6515aB04D9e89a750Ca",
+				Relayers: []string{"0xff93B45308FD417dF303D6515aB04D9e89a750Ca", "0x8e0a907331554AF72563Bd8D43051C2E64Be5d35"},
+				Relayer:  "0x8e0a907331554AF72563Bd8D43051C2E64Be5d35",
+				Opts:     map[string]string{"http": "true"},
+				Contracts: config.DeployContracts{
+					Bridge:         "0x01",
+					Erc20Handler:   "0x02",
+					Erc721Handler:  "0x03",
+					GenericHandler: "0x04",
+				},
+				StartBlock: "10",
+			},
+			{
+				Name:       "sub",
+				Type:       "substrate",
+				Id:         "1",
+				Endpoint:   "ws://localhost:9944",
+				From:       testAlice,
+				Relayers:   []string{testBob},
+				Opts:       map[string]string{"runtime": "acala"},
+				StartBlock: "20",
+			},
+		},
+	}
+
+	expected := []config.RawChainConfig{
+		{
+			Name:     "eth",
+			Type:     "ethereum",
+			Id:       "0",
+			Endpoint: "ws://localhost:8545",
+			From:     "0x8e0a907331554AF72563Bd8D43051C2E64Be5d35",
+			Opts: map[string]string{
+				"http":           "true",
+				"bridge":         "0x01",
+				"erc20Handler":   "0x02",
+				"erc721Handler":  "0x03",
+				"genericHandler": "0x04",
+				"startBlock":     "10",
+			},
+		},
+		{
+			Name:     "sub",
+			Type:     "substrate",
+			Id:       "1",
+			Endpoint: "ws://localhost:9944",
+			From:     testBob,
+			Opts:     map[string]string{"runtime": "acala", "startBlock": "20"},
+		},
+	}
+
+	cfg := relayerConfig(spec)
+	if !reflect.DeepEqual(cfg.Chains, expected) {
+		t.Fatalf("Relayer config mismatch.\n\tGot: %+v\n\tExpected: %+v", cfg.Chains, expected)
+	}
+	if spec.Chains[0].Opts["bridge"] != "" {
+		t.Fatal("Relayer config modified the opts of the spec")
+	}
+}
+
+// mockSubClient builds empty calls
+type mockSubClient struct{}
+
+func (mockSubClient) NewAddRelayerCall(types.AccountID) (types.Call, error) { return types.Call{}, nil }
+func (mockSubClient) NewSetRelayerThresholdCall(types.U32) (types.Call, error) {
+	return types.Call{}, nil
+}
+func (mockSubClient) NewWhitelistChainCall(msg.ChainId) (types.Call, error) { return types.Call{}, nil }
+func (mockSubClient) NewRegisterResourceCall(msg.ResourceId, string) (types.Call, error) {
+	return types.Call{}, nil
+}
+func (mockSubClient) LatestBlock() (uint64, error) { return 0, nil }
+
+// mockStorage returns a query function for storage values keyed by item name and key
+func mockStorage(t *testing.T, storage map[string]interface{}) func(prefix, method string, arg1, arg2 []byte, result interface{}) (bool, error) {
+	return func(prefix, method string, arg1, arg2 []byte, result interface{}) (bool, error) {
+		if prefix != subutils.BridgeStoragePrefix {
+			t.Fatalf("Unexpected storage prefix %s", prefix)
+		}
+		val, ok := storage[fmt.Sprintf("%s%x", method, arg1)]
+		if !ok {
+			return false, nil
+		}
+		bz, err := types.EncodeToBytes(val)
+		if err != nil {
+			return false, err
+		}
+		return true, types.DecodeFromBytes(bz, result)
+	}
+}
+
+func TestSubAdmin_deployCalls(t *testing.T) {
+	alice, _, err := subutils.DecodeSS58(testAlice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := &config.DeployChain{
+		Relayers:  []string{testAlice, testBob},
+		Resources: []config.DeployResource{{Id: testRId, Method: "Example.transfer"}},
+	}
+	chains := []msg.ChainId{0, 2}
+
+	// Nothing is configured
+	admin := &subAdmin{client: mockSubClient{}, query: mockStorage(t, map[string]interface{}{})}
+	calls, err := admin.deployCalls(chain, chains, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"add relayer " + testAlice,
+		"add relayer " + testBob,
+		"whitelist chain 0",
+		"whitelist chain 2",
+		fmt.Sprintf("register resource %x as Example.transfer", common.FromHex(testRId)),
+		"set threshold 2",
+	}
+	assertSubCalls(t, calls, expected)
+
+	// Alice, chain 0 and the threshold are configured, the resource executes another method
+	admin.query = mockStorage(t, map[string]interface{}{
+		fmt.Sprintf("Relayers%x", alice[:]): types.NewBool(true),
+		"ChainNonces00":                     types.U64(3),
+		"Resources" + testRId[2:]:           types.NewBytes([]byte("Example.remark")),
+		"RelayerThreshold":                  types.U32(2),
+	})
+	calls, err = admin.deployCalls(chain, chains, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSubCalls(t, calls, []string{expected[1], expected[3], expected[4]})
+}
+
+func assertSubCalls(t *testing.T, calls []subCall, expected []string) {
+	descs := make([]string, len(calls))
+	for i, call := range calls {
+		descs[i] = call.desc
+	}
+	if !reflect.DeepEqual(descs, expected) {
+		t.Fatalf("Unexpected calls.\n\tGot: %q\n\tExpected: %q", descs, expected)
+	}
+}
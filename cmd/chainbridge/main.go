@@ -17,7 +17,6 @@ import (
 
 	"github.com/ChainSafe/ChainBridge/chains/ethereum"
 	"github.com/ChainSafe/ChainBridge/chains/substrate"
-	"github.com/ChainSafe/ChainBridge/chains/acala"
 	"github.com/ChainSafe/ChainBridge/config"
 	"github.com/ChainSafe/chainbridge-utils/core"
 	"github.com/ChainSafe/chainbridge-utils/metrics/health"
@@ -113,6 +112,10 @@ func init() {
 	app.EnableBashCompletion = true
 	app.Commands = []*cli.Command{
 		&accountCommand,
+		&adminCommand,
+		&deployCommand,
+		&statusCommand,
+		&relayCommand,
 	}
 
 	app.Flags = append(app.Flags, cliFlags...)
@@ -154,37 +157,15 @@ func run(ctx *cli.Context) error {
 		return err
 	}
 
-	// Check for test key flag
-	var ks string
-	var insecure bool
-	if key := ctx.String(config.TestKeyFlag.Name); key != "" {
-		ks = key
-		insecure = true
-	} else {
-		ks = cfg.KeystorePath
-	}
-
 	// Used to signal core shutdown due to fatal error
 	sysErr := make(chan error)
 	c := core.NewCore(sysErr)
 
 	for _, chain := range cfg.Chains {
-		chainId, errr := strconv.Atoi(chain.Id)
+		chainConfig, chainType, errr := newChainConfig(ctx, cfg, chain)
 		if errr != nil {
 			return errr
 		}
-		chainConfig := &core.ChainConfig{
-			Name:           chain.Name,
-			Id:             msg.ChainId(chainId),
-			Endpoint:       chain.Endpoint,
-			From:           chain.From,
-			KeystorePath:   ks,
-			Insecure:       insecure,
-			BlockstorePath: ctx.String(config.BlockstorePathFlag.Name),
-			FreshStart:     ctx.Bool(config.FreshStartFlag.Name),
-			LatestBlock:    ctx.Bool(config.LatestBlockFlag.Name),
-			Opts:           chain.Opts,
-		}
 		var newChain core.Chain
 		var m *metrics.ChainMetrics
 
@@ -194,12 +175,10 @@ func run(ctx *cli.Context) error {
 			m = metrics.NewChainMetrics(chain.Name)
 		}
 
-		if chain.Type == "ethereum" {
+		if chainType == "ethereum" {
 			newChain, err = ethereum.InitializeChain(chainConfig, logger, sysErr, m)
-		} else if chain.Type == "substrate" {
+		} else if chainType == "substrate" {
 			newChain, err = substrate.InitializeChain(chainConfig, logger, sysErr, m)
-		} else if chain.Type == "acala" {
-			newChain, err = acala.InitializeChain(chainConfig, logger, sysErr, m)
 		} else {
 			return errors.New("unrecognized Chain Type")
 		}
@@ -240,3 +219,46 @@ func run(ctx *cli.Context) error {
 
 	return nil
 }
+
+// newChainConfig builds the config of a chain from the config file and the global flags, along with the type of the
+// chain. Acala chains are substrate chains using the acala runtime profile.
+func newChainConfig(ctx *cli.Context, cfg *config.Config, chain config.RawChainConfig) (*core.ChainConfig, string, error) {
+	chainId, err := strconv.Atoi(chain.Id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check for test key flag
+	var ks string
+	var insecure bool
+	if key := ctx.String(config.TestKeyFlag.Name); key != "" {
+		ks = key
+		insecure = true
+	} else {
+		ks = cfg.KeystorePath
+	}
+
+	chainConfig := &core.ChainConfig{
+		Name:           chain.Name,
+		Id:             msg.ChainId(chainId),
+		Endpoint:       chain.Endpoint,
+		From:           chain.From,
+		KeystorePath:   ks,
+		Insecure:       insecure,
+		BlockstorePath: ctx.String(config.BlockstorePathFlag.Name),
+		FreshStart:     ctx.Bool(config.FreshStartFlag.Name),
+		LatestBlock:    ctx.Bool(config.LatestBlockFlag.Name),
+		Opts:           chain.Opts,
+	}
+
+	if chain.Type == "acala" {
+		if chainConfig.Opts == nil {
+			chainConfig.Opts = make(map[string]string)
+		}
+		if _, ok := chainConfig.Opts["runtime"]; !ok {
+			chainConfig.Opts["runtime"] = substrate.AcalaProfile.Name
+		}
+		return chainConfig, "substrate", nil
+	}
+	return chainConfig, chain.Type, nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ChainSafe/ChainBridge/chains/ethereum"
+	"github.com/ChainSafe/ChainBridge/chains/substrate"
+	"github.com/ChainSafe/ChainBridge/config"
+	"github.com/ChainSafe/ChainBridge/outbox"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	log "github.com/ChainSafe/log15"
+	"github.com/urfave/cli/v2"
+)
+
+var relayCommand = cli.Command{
+	Action: handleRelayCmd,
+	Name:   "relay",
+	Usage:  "relay the deposits of a transaction or block that were skipped",
+	Flags:  []cli.Flag{config.RelayChainFlag, config.TxHashFlag, config.BlockFlag, config.NonceFlag, config.RelayTimeoutFlag},
+	Description: "The relay command builds the messages for the deposits of a transaction or block as the listener does and\n" +
+		"\tpasses each of them once to the writer of its destination chain, which votes on and executes the proposal.\n" +
+		"\tUse --nonce to relay a single deposit. The keys of the destination chains are loaded as by the relayer, and\n" +
+		"\tthe outcome is recorded in a separate outbox, so the outbox of a running relayer is not modified. Substrate deposits\n" +
+		"\tcan only be looked up by block.",
+}
+
+// relayPathPostfix is the default directory of the outboxes of the relay command
+const relayPathPostfix = ".chainbridge/relay"
+
+// relayer is a destination chain opened to resolve messages
+type relayer interface {
+	Relay(m msg.Message, timeout time.Duration) outbox.Entry
+	Stop()
+}
+
+func handleRelayCmd(ctx *cli.Context) error {
+	err := startLogger(ctx)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	src, err := requireString(ctx, config.RelayChainFlag)
+	if err != nil {
+		return err
+	}
+	tx := ctx.String(config.TxHashFlag.Name)
+	hasBlock := ctx.IsSet(config.BlockFlag.Name)
+	if (tx == "") == !hasBlock {
+		return fmt.Errorf("either --%s or --%s is required", config.TxHashFlag.Name, config.BlockFlag.Name)
+	}
+	var srcCfg *config.RawChainConfig
+	for i := range cfg.Chains {
+		if cfg.Chains[i].Id == src {
+			srcCfg = &cfg.Chains[i]
+		}
+	}
+	if srcCfg == nil {
+		return fmt.Errorf("chain %s is not in the config", src)
+	}
+
+	source, err := inspectChain(ctx, cfg, *srcCfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to chain %s: %w", src, err)
+	}
+	defer source.Stop()
+
+	var msgs []msg.Message
+	if hasBlock {
+		block := ctx.Uint64(config.BlockFlag.Name)
+		log.Info("Looking up deposits", "chain", src, "block", block)
+		msgs, err = source.DepositsInBlock(block)
+	} else {
+		log.Info("Looking up deposits", "chain", src, "tx", tx)
+		msgs, err = source.DepositsInTx(tx)
+	}
+	if err != nil {
+		return err
+	}
+	if ctx.IsSet(config.NonceFlag.Name) {
+		msgs = filterNonce(msgs, msg.Nonce(ctx.Uint64(config.NonceFlag.Name)))
+	}
+	if len(msgs) == 0 {
+		return errors.New("no deposits found")
+	}
+	destinations, err := relayDestinations(cfg, msgs)
+	if err != nil {
+		return err
+	}
+
+	// Fatal errors of the writers are reported in the results, only log them
+	sysErr := make(chan error)
+	go func() {
+		for err := range sysErr {
+			log.Error("Fatal error while relaying", "err", err)
+		}
+	}()
+
+	dests := make(map[msg.ChainId]relayer)
+	for id, chain := range destinations {
+		dest, err := relayChain(ctx, cfg, chain, sysErr)
+		if err != nil {
+			return fmt.Errorf("failed to connect to chain %s: %w", chain.Id, err)
+		}
+		defer dest.Stop()
+		dests[id] = dest
+	}
+
+	timeout := ctx.Duration(config.RelayTimeoutFlag.Name)
+	for _, m := range msgs {
+		log.Info("Relaying deposit", "src", m.Source, "dest", m.Destination, "nonce", m.DepositNonce)
+		printRelayResult(os.Stdout, m, dests[m.Destination].Relay(m, timeout))
+	}
+	return nil
+}
+
+func filterNonce(msgs []msg.Message, nonce msg.Nonce) []msg.Message {
+	var res []msg.Message
+	for _, m := range msgs {
+		if m.DepositNonce == nonce {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// relayDestinations returns the config of the destination chain of every message
+func relayDestinations(cfg *config.Config, msgs []msg.Message) (map[msg.ChainId]config.RawChainConfig, error) {
+	res := make(map[msg.ChainId]config.RawChainConfig)
+	for _, m := range msgs {
+		if _, ok := res[m.Destination]; ok {
+			continue
+		}
+		found := false
+		for _, chain := range cfg.Chains {
+			if chain.Id == fmt.Sprint(m.Destination) {
+				res[m.Destination] = chain
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("destination chain %d of deposit %d is not in the config", m.Destination, m.DepositNonce)
+		}
+	}
+	return res, nil
+}
+
+// relayChain connects to a chain of the config with its key, as the relayer does. The chain is not started.
+func relayChain(ctx *cli.Context, cfg *config.Config, chain config.RawChainConfig, sysErr chan<- error) (relayer, error) {
+	chainConfig, chainType, err := newChainConfig(ctx, cfg, chain)
+	if err != nil {
+		return nil, err
+	}
+	// The outbox of the relayer is rewritten on every update, sharing it would lose the entries of either process
+	chainConfig.BlockstorePath, err = relayStorePath(chainConfig.BlockstorePath)
+	if err != nil {
+		return nil, err
+	}
+	logger := log.Root().New("chain", chainConfig.Name)
+	switch chainType {
+	case "ethereum":
+		return ethereum.InitializeChain(chainConfig, logger, sysErr, nil)
+	case "substrate":
+		return substrate.InitializeChain(chainConfig, logger, sysErr, nil)
+	default:
+		return nil, errors.New("unrecognized Chain Type")
+	}
+}
+
+// relayStorePath returns the directory of the outboxes of the relay command, relay in the blockstore directory or
+// ~/.chainbridge/relay by default. The blockstore is only read while relaying.
+func relayStorePath(blockstorePath string) (string, error) {
+	if blockstorePath != "" {
+		return filepath.Join(blockstorePath, "relay"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, relayPathPostfix), nil
+}
+
+// printRelayResult reports the state a deposit was left in by the writer
+func printRelayResult(w io.Writer, m msg.Message, e outbox.Entry) {
+	state := string(e.State)
+	switch {
+	case e.State == "":
+		state = "not resolved, see the log"
+	case e.Reason != "":
+		state += ": " + e.Reason
+	case e.State == outbox.Voted:
+		state += ", the proposal has not been executed yet"
+	}
+	fmt.Fprintf(w, "Deposit %d from %d to %d: %s\n", m.DepositNonce, m.Source, m.Destination, state)
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChainSafe/ChainBridge/config"
+	"github.com/ChainSafe/ChainBridge/outbox"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+func TestRelayDestinations(t *testing.T) {
+	cfg := &config.Config{Chains: []config.RawChainConfig{{Name: "eth", Id: "0"}, {Name: "sub", Id: "1"}, {Name: "other", Id: "2"}}}
+	rId := msg.ResourceIdFromSlice([]byte{1})
+	msgs := []msg.Message{
+		msg.NewFungibleTransfer(0, 1, 3, big.NewInt(10), rId, []byte{0xab}),
+		msg.NewFungibleTransfer(0, 2, 4, big.NewInt(10), rId, []byte{0xab}),
+		msg.NewFungibleTransfer(0, 1, 5, big.NewInt(10), rId, []byte{0xab}),
+	}
+
+	res, err := relayDestinations(cfg, msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[1].Name != "sub" || res[2].Name != "other" {
+		t.Fatalf("Unexpected destinations: %#v", res)
+	}
+
+	_, err = relayDestinations(cfg, append(msgs, msg.NewGenericTransfer(0, 5, 1, rId, []byte{})))
+	if err == nil {
+		t.Fatal("Expected an error for a destination missing from the config")
+	}
+
+	if filtered := filterNonce(msgs, 4); len(filtered) != 1 || filtered[0].DepositNonce != 4 {
+		t.Fatalf("Unexpected messages for nonce 4: %#v", filtered)
+	}
+}
+
+func TestRelayStorePath(t *testing.T) {
+	path, err := relayStorePath("/data/blockstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join("/data/blockstore", "relay") {
+		t.Fatalf("Unexpected relay path %s", path)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err = relayStorePath("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The default directories of the blockstore and the outbox are not used
+	if path != filepath.Join(home, ".chainbridge", "relay") {
+		t.Fatalf("Unexpected default relay path %s", path)
+	}
+}
+
+func TestPrintRelayResult(t *testing.T) {
+	m := msg.NewFungibleTransfer(0, 1, 7, big.NewInt(100), msg.ResourceIdFromSlice([]byte{1}), []byte{0xab})
+
+	testCases := []struct {
+		entry    outbox.Entry
+		expected string
+	}{
+		{outbox.Entry{State: outbox.Executed}, "Deposit 7 from 0 to 1: executed\n"},
+		{outbox.Entry{State: outbox.Held, Reason: "simulation failed"}, "Deposit 7 from 0 to 1: held: simulation failed\n"},
+		{outbox.Entry{State: outbox.Voted}, "Deposit 7 from 0 to 1: voted, the proposal has not been executed yet\n"},
+		{outbox.Entry{}, "Deposit 7 from 0 to 1: not resolved, see the log\n"},
+	}
+
+	for _, tc := range testCases {
+		var out bytes.Buffer
+		printRelayResult(&out, m, tc.entry)
+		if out.String() != tc.expected {
+			t.Errorf("Got: %q Expected: %q", out.String(), tc.expected)
+		}
+	}
+}
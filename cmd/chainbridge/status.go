@@ -0,0 +1,169 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ChainSafe/ChainBridge/chains"
+	"github.com/ChainSafe/ChainBridge/chains/ethereum"
+	"github.com/ChainSafe/ChainBridge/chains/substrate"
+	"github.com/ChainSafe/ChainBridge/config"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	log "github.com/ChainSafe/log15"
+	"github.com/urfave/cli/v2"
+)
+
+var statusCommand = cli.Command{
+	Action: handleStatusCmd,
+	Name:   "status",
+	Usage:  "trace a deposit from its source chain to its proposal on the destination chain",
+	Flags:  []cli.Flag{config.SourceChainFlag, config.DestChainFlag, config.NonceFlag},
+	Description: "The status command looks up a deposit on the source chain and builds its message as the relayer does.\n" +
+		"\tIt reports the proposal for the message on the destination chain, with the data voted on, its status, the\n" +
+		"\trelayers that voted and the transaction or extrinsic executing it. The chains are read from the config file,\n" +
+		"\tno keys are required. Deposits and executions are searched in historical state, which requires archive nodes.",
+}
+
+// inspector is a chain opened to look up deposits and proposals
+type inspector interface {
+	FindDeposit(dest msg.ChainId, nonce msg.Nonce) (*chains.Deposit, error)
+	DepositsInBlock(block uint64) ([]msg.Message, error)
+	DepositsInTx(hash string) ([]msg.Message, error)
+	ProposalStatus(m msg.Message) (*chains.Proposal, error)
+	Stop()
+}
+
+func handleStatusCmd(ctx *cli.Context) error {
+	err := startLogger(ctx)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	src, err := requireString(ctx, config.SourceChainFlag)
+	if err != nil {
+		return err
+	}
+	if !ctx.IsSet(config.NonceFlag.Name) {
+		return fmt.Errorf("--%s is required", config.NonceFlag.Name)
+	}
+	nonce := msg.Nonce(ctx.Uint64(config.NonceFlag.Name))
+	srcCfg, destCfg, err := statusChains(cfg, src, ctx.String(config.DestChainFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	source, err := inspectChain(ctx, cfg, srcCfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to chain %s: %w", srcCfg.Id, err)
+	}
+	defer source.Stop()
+	dest, err := inspectChain(ctx, cfg, destCfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to chain %s: %w", destCfg.Id, err)
+	}
+	defer dest.Stop()
+
+	log.Info("Looking up deposit", "src", srcCfg.Id, "dest", destCfg.Id, "nonce", nonce)
+	destId, err := strconv.ParseUint(destCfg.Id, 10, 8)
+	if err != nil {
+		return err
+	}
+	deposit, err := source.FindDeposit(msg.ChainId(destId), nonce)
+	if err != nil {
+		return err
+	}
+	proposal, err := dest.ProposalStatus(deposit.Message)
+	if err != nil {
+		return fmt.Errorf("failed to get proposal: %w", err)
+	}
+	printStatus(os.Stdout, srcCfg, destCfg, deposit, proposal)
+	return nil
+}
+
+// statusChains returns the source and destination chain of the config. The destination may be omitted if the
+// config has two chains.
+func statusChains(cfg *config.Config, src, dest string) (config.RawChainConfig, config.RawChainConfig, error) {
+	var srcCfg, destCfg *config.RawChainConfig
+	for i := range cfg.Chains {
+		chain := &cfg.Chains[i]
+		if chain.Id == src {
+			srcCfg = chain
+		} else if chain.Id == dest || (dest == "" && len(cfg.Chains) == 2) {
+			destCfg = chain
+		}
+	}
+	if srcCfg == nil {
+		return config.RawChainConfig{}, config.RawChainConfig{}, fmt.Errorf("chain %s is not in the config", src)
+	}
+	if destCfg == nil {
+		if dest == "" {
+			return config.RawChainConfig{}, config.RawChainConfig{}, fmt.Errorf("--%s is required if the config has more than two chains", config.DestChainFlag.Name)
+		}
+		return config.RawChainConfig{}, config.RawChainConfig{}, fmt.Errorf("chain %s is not in the config", dest)
+	}
+	return *srcCfg, *destCfg, nil
+}
+
+// inspectChain connects to a chain of the config without loading its key
+func inspectChain(ctx *cli.Context, cfg *config.Config, chain config.RawChainConfig) (inspector, error) {
+	chainConfig, chainType, err := newChainConfig(ctx, cfg, chain)
+	if err != nil {
+		return nil, err
+	}
+	logger := log.Root().New("chain", chainConfig.Name)
+	switch chainType {
+	case "ethereum":
+		return ethereum.InspectChain(chainConfig, logger)
+	case "substrate":
+		return substrate.InspectChain(chainConfig, logger)
+	default:
+		return nil, errors.New("unrecognized Chain Type")
+	}
+}
+
+// printStatus reports a deposit and the state of its proposal
+func printStatus(w io.Writer, src, dest config.RawChainConfig, deposit *chains.Deposit, proposal *chains.Proposal) {
+	m := deposit.Message
+	fmt.Fprintf(w, "Deposit %d from %s (%s) to %s (%s)\n", m.DepositNonce, src.Name, src.Id, dest.Name, dest.Id)
+	location := fmt.Sprintf("block %d", deposit.Block)
+	if deposit.Tx != "" {
+		location += ", tx " + deposit.Tx
+	}
+	fmt.Fprintf(w, "  Source:        %s\n", location)
+	fmt.Fprintf(w, "  Type:          %s\n", m.Type)
+	fmt.Fprintf(w, "  Resource ID:   %s\n", m.ResourceId.Hex())
+	for i, p := range m.Payload {
+		if bz, ok := p.([]byte); ok {
+			fmt.Fprintf(w, "  Payload[%d]:    %#x\n", i, bz)
+		} else {
+			fmt.Fprintf(w, "  Payload[%d]:    %v\n", i, p)
+		}
+	}
+	fmt.Fprintf(w, "Proposal on %s (%s)\n", dest.Name, dest.Id)
+	fmt.Fprintf(w, "  Data:          %s\n", proposal.Data)
+	fmt.Fprintf(w, "  Status:        %s\n", proposal.Status)
+	fmt.Fprintf(w, "  Votes for:     %s\n", listOrNone(proposal.VotesFor))
+	fmt.Fprintf(w, "  Votes against: %s\n", listOrNone(proposal.VotesAgainst))
+	execution := proposal.Execution
+	if execution == "" {
+		execution = "none"
+	}
+	fmt.Fprintf(w, "  Execution:     %s\n", execution)
+}
+
+func listOrNone(vals []string) string {
+	if len(vals) == 0 {
+		return "none"
+	}
+	return strings.Join(vals, ", ")
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/ChainBridge/chains"
+	"github.com/ChainSafe/ChainBridge/config"
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+func TestStatusChains(t *testing.T) {
+	two := &config.Config{Chains: []config.RawChainConfig{{Name: "eth", Id: "0"}, {Name: "sub", Id: "1"}}}
+	three := &config.Config{Chains: append(two.Chains, config.RawChainConfig{Name: "other", Id: "2"})}
+
+	testCases := []struct {
+		name      string
+		cfg       *config.Config
+		src, dest string
+		expected  string // name of the destination, empty for an error
+	}{
+		{"implicit destination", two, "1", "", "eth"},
+		{"explicit destination", three, "0", "2", "other"},
+		{"destination required", three, "0", "", ""},
+		{"unknown source", two, "5", "", ""},
+		{"unknown destination", three, "0", "5", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			src, dest, err := statusChains(tc.cfg, tc.src, tc.dest)
+			if tc.expected == "" {
+				if err == nil {
+					t.Fatalf("Expected an error, got %s to %s", src.Name, dest.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if src.Id != tc.src || dest.Name != tc.expected {
+				t.Fatalf("Got: %s to %s Expected: %s to %s", src.Id, dest.Name, tc.src, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPrintStatus(t *testing.T) {
+	src := config.RawChainConfig{Name: "eth", Id: "0"}
+	dest := config.RawChainConfig{Name: "sub", Id: "1"}
+	deposit := &chains.Deposit{
+		Message: msg.NewFungibleTransfer(0, 1, 7, big.NewInt(100), msg.ResourceIdFromSlice([]byte{1}), []byte{0xab}),
+		Block:   120,
+		Tx:      "0x1234",
+	}
+	proposal := &chains.Proposal{
+		Data:     "0x5678",
+		Status:   "active",
+		VotesFor: []string{"0xaa", "0xbb"},
+	}
+
+	var out bytes.Buffer
+	printStatus(&out, src, dest, deposit, proposal)
+	for _, line := range []string{
+		"Deposit 7 from eth (0) to sub (1)",
+		"Source:        block 120, tx 0x1234",
+		"Payload[0]:    0x64",
+		"Payload[1]:    0xab",
+		"Status:        active",
+		"Votes for:     0xaa, 0xbb",
+		"Votes against: none",
+		"Execution:     none",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Missing %q in status:\n%s", line, out.String())
+		}
+	}
+}
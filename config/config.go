@@ -4,13 +4,18 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 
+	"github.com/BurntSushi/toml"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 )
 
 const DefaultConfigPath = "./config.json"
@@ -18,18 +23,74 @@ const DefaultKeystorePath = "./keys"
 const DefaultBlockTimeout = int64(180) // 3 minutes
 
 type Config struct {
-	Chains       []RawChainConfig `json:"chains"`
-	KeystorePath string           `json:"keystorePath,omitempty"`
+	Chains       []RawChainConfig `json:"chains" toml:"chains" yaml:"chains"`
+	KeystorePath string           `json:"keystorePath,omitempty" toml:"keystorePath,omitempty" yaml:"keystorePath,omitempty"`
 }
 
 // RawChainConfig is parsed directly from the config file and should be using to construct the core.ChainConfig
 type RawChainConfig struct {
-	Name     string            `json:"name"`
-	Type     string            `json:"type"`
-	Id       string            `json:"id"`       // ChainID
-	Endpoint string            `json:"endpoint"` // url for rpc endpoint
-	From     string            `json:"from"`     // address of key to use
-	Opts     map[string]string `json:"opts"`
+	Name     string            `json:"name" toml:"name" yaml:"name"`
+	Type     string            `json:"type" toml:"type" yaml:"type"`
+	Id       string            `json:"id" toml:"id" yaml:"id"`                   // ChainID
+	Endpoint string            `json:"endpoint" toml:"endpoint" yaml:"endpoint"` // url for rpc endpoint
+	From     string            `json:"from" toml:"from" yaml:"from"`             // address of key to use
+	Opts     map[string]string `json:"opts" toml:"opts" yaml:"opts"`
+}
+
+// UnmarshalTOML decodes a chain table. TOML is typed, so integer and boolean values are accepted
+// for the chain ID and options and converted to strings, eg. `id = 0` or `opts = { http = true }`.
+func (c *RawChainConfig) UnmarshalTOML(data interface{}) error {
+	fields, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("chain config must be a table, got %T", data)
+	}
+
+	var err error
+	for key, value := range fields {
+		switch key {
+		case "name":
+			c.Name, err = scalarToString(key, value)
+		case "type":
+			c.Type, err = scalarToString(key, value)
+		case "id":
+			c.Id, err = scalarToString(key, value)
+		case "endpoint":
+			c.Endpoint, err = scalarToString(key, value)
+		case "from":
+			c.From, err = scalarToString(key, value)
+		case "opts":
+			opts, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("chain opts must be a table, got %T", value)
+			}
+			c.Opts = make(map[string]string, len(opts))
+			for name, opt := range opts {
+				c.Opts[name], err = scalarToString(name, opt)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scalarToString(key string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value for %s: %v", key, value)
+	}
 }
 
 func NewConfig() *Config {
@@ -38,6 +99,8 @@ func NewConfig() *Config {
 	}
 }
 
+// ToJSON writes the config to file. Despite the name, the format is chosen by the file extension:
+// .toml and .yaml/.yml files are written as TOML and YAML respectively, all others as JSON.
 func (c *Config) ToJSON(file string) *os.File {
 	var (
 		newFile *os.File
@@ -45,8 +108,8 @@ func (c *Config) ToJSON(file string) *os.File {
 	)
 
 	var raw []byte
-	if raw, err = json.Marshal(*c); err != nil {
-		log.Warn("error marshalling json", "err", err)
+	if raw, err = marshal(*c, filepath.Ext(file)); err != nil {
+		log.Warn("error marshalling config", "err", err)
 		os.Exit(1)
 	}
 
@@ -65,6 +128,29 @@ func (c *Config) ToJSON(file string) *os.File {
 	return newFile
 }
 
+// Write saves the config in the format matching the file extension. Unlike ToJSON, failures are returned.
+func (c *Config) Write(file string) error {
+	raw, err := marshal(*c, filepath.Ext(file))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, raw, 0600)
+}
+
+// marshal encodes v in the format matching the file extension
+func marshal(v interface{}, ext string) ([]byte, error) {
+	switch ext {
+	case ".toml":
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(v)
+		return buf.Bytes(), err
+	case ".yaml", ".yml":
+		return yaml.Marshal(v)
+	default:
+		return json.MarshalIndent(v, "", "  ")
+	}
+}
+
 func (c *Config) validate() error {
 	for _, chain := range c.Chains {
 		if chain.Type == "" {
@@ -94,7 +180,7 @@ func GetConfig(ctx *cli.Context) (*Config, error) {
 	}
 	err := loadConfig(path, &fig)
 	if err != nil {
-		log.Warn("err loading json file", "err", err.Error())
+		log.Warn("err loading config file", "err", err.Error())
 		return &fig, err
 	}
 	if ksPath := ctx.String(KeystorePathFlag.Name); ksPath != "" {
@@ -108,7 +194,8 @@ func GetConfig(ctx *cli.Context) (*Config, error) {
 	return &fig, nil
 }
 
-func loadConfig(file string, config *Config) error {
+// loadConfig decodes a JSON, TOML or YAML file into config, which may be a *Config or a *DeploySpec
+func loadConfig(file string, config interface{}) error {
 	ext := filepath.Ext(file)
 	fp, err := filepath.Abs(file)
 	if err != nil {
@@ -122,11 +209,22 @@ func loadConfig(file string, config *Config) error {
 		return err
 	}
 
-	if ext == ".json" {
-		if err = json.NewDecoder(f).Decode(&config); err != nil {
+	defer f.Close()
+
+	switch ext {
+	case ".json":
+		if err = json.NewDecoder(f).Decode(config); err != nil {
+			return err
+		}
+	case ".toml":
+		if _, err = toml.DecodeReader(f, config); err != nil {
+			return err
+		}
+	case ".yaml", ".yml":
+		if err = yaml.NewDecoder(f).Decode(config); err != nil {
 			return err
 		}
-	} else {
+	default:
 		return fmt.Errorf("unrecognized extention: %s", ext)
 	}
 
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -15,6 +16,10 @@ import (
 )
 
 func createTempConfigFile() (*os.File, *Config) {
+	return createTempConfigFileWithExt(".json")
+}
+
+func createTempConfigFileWithExt(ext string) (*os.File, *Config) {
 	testConfig := NewConfig()
 	ethCfg := RawChainConfig{
 		Name:     "chain",
@@ -25,7 +30,7 @@ func createTempConfigFile() (*os.File, *Config) {
 		Opts:     map[string]string{"key": "value"},
 	}
 	testConfig.Chains = []RawChainConfig{ethCfg}
-	tmpFile, err := ioutil.TempFile(os.TempDir(), "*.json")
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "*"+ext)
 	if err != nil {
 		fmt.Println("Cannot create temporary file", "err", err)
 		os.Exit(1)
@@ -71,6 +76,114 @@ func TestLoadJSONConfig(t *testing.T) {
 	}
 }
 
+func TestLoadConfigFormats(t *testing.T) {
+	for _, ext := range []string{".json", ".toml", ".yaml", ".yml"} {
+		t.Run(ext, func(t *testing.T) {
+			file, cfg := createTempConfigFileWithExt(ext)
+			defer os.Remove(file.Name())
+			cfg.KeystorePath = "./keys"
+			cfg.ToJSON(file.Name())
+
+			res := NewConfig()
+			err := loadConfig(file.Name(), res)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(res, cfg) {
+				t.Errorf("did not match\ngot: %+v\nexpected: %+v", res, cfg)
+			}
+		})
+	}
+}
+
+func TestConfigWrite(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	_, cfg := createTempConfigFileWithExt(".json")
+
+	file := filepath.Join(dir, "config.toml")
+	err = cfg.Write(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := NewConfig()
+	err = loadConfig(file, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res, cfg) {
+		t.Errorf("did not match\ngot: %+v\nexpected: %+v", res, cfg)
+	}
+
+	err = cfg.Write(filepath.Join(dir, "missing", "config.json"))
+	if err == nil {
+		t.Fatal("expected error for a missing directory")
+	}
+}
+
+func TestLoadTOMLConfigWithTypedValues(t *testing.T) {
+	tmpFile, err := ioutil.TempFile(os.TempDir(), "*.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString(`
+[[chains]]
+name = "ethereum"
+type = "ethereum"
+id = 0
+endpoint = "ws://localhost:8545"
+from = "0xff93B45308FD417dF303D6515aB04D9e89a750Ca"
+opts = { bridge = "0x62877dDCd49aD22f5eDfc6ac108e9a4b5D2bD88B", startBlock = 10, http = true }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := NewConfig()
+	err = loadConfig(tmpFile.Name(), res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RawChainConfig{
+		Name:     "ethereum",
+		Type:     "ethereum",
+		Id:       "0",
+		Endpoint: "ws://localhost:8545",
+		From:     "0xff93B45308FD417dF303D6515aB04D9e89a750Ca",
+		Opts: map[string]string{
+			"bridge":     "0x62877dDCd49aD22f5eDfc6ac108e9a4b5D2bD88B",
+			"startBlock": "10",
+			"http":       "true",
+		},
+	}
+	if len(res.Chains) != 1 || !reflect.DeepEqual(res.Chains[0], expected) {
+		t.Errorf("did not match\ngot: %+v\nexpected: %+v", res.Chains, expected)
+	}
+}
+
+func TestLoadExampleTOMLConfigs(t *testing.T) {
+	for _, path := range []string{"../scripts/configs/config1.toml", "../scripts/configs/config2.toml"} {
+		res := NewConfig()
+		err := loadConfig(path, res)
+		if err != nil {
+			t.Fatalf("failed to load %s: %s", path, err)
+		}
+		if err = res.validate(); err != nil {
+			t.Fatalf("invalid config %s: %s", path, err)
+		}
+		if len(res.Chains) == 0 {
+			t.Fatalf("no chains loaded from %s", path)
+		}
+	}
+}
+
 func TestValdiateConfig(t *testing.T) {
 	valid := RawChainConfig{
 		Name:     "chain",
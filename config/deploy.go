@@ -0,0 +1,160 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Resource types of ethereum chains in a deployment spec
+const (
+	Erc20Resource   = "erc20"
+	Erc721Resource  = "erc721"
+	GenericResource = "generic"
+)
+
+// DeploySpec describes a bridge deployment. The deploy command writes the addresses of the contracts it deploys back
+// to the spec, so running it again reuses them and only applies configuration that is missing.
+type DeploySpec struct {
+	Threshold uint32        `json:"threshold" toml:"threshold" yaml:"threshold"` // Relayer votes required on every chain
+	Chains    []DeployChain `json:"chains" toml:"chains" yaml:"chains"`
+}
+
+// DeployChain is a chain of a deployment and the relayer config written for it
+type DeployChain struct {
+	Name      string            `json:"name" toml:"name" yaml:"name"`
+	Type      string            `json:"type" toml:"type" yaml:"type"`
+	Id        string            `json:"id" toml:"id" yaml:"id"`
+	Endpoint  string            `json:"endpoint" toml:"endpoint" yaml:"endpoint"`
+	From      string            `json:"from" toml:"from" yaml:"from"`                                        // Admin key deploying and configuring the bridge
+	Relayers  []string          `json:"relayers" toml:"relayers" yaml:"relayers"`                            // Relayer addresses on this chain
+	Relayer   string            `json:"relayer,omitempty" toml:"relayer,omitempty" yaml:"relayer,omitempty"` // From of the relayer config, defaults to the first relayer
+	Opts      map[string]string `json:"opts,omitempty" toml:"opts,omitempty" yaml:"opts,omitempty"`          // Copied to the relayer config
+	Resources []DeployResource  `json:"resources,omitempty" toml:"resources,omitempty" yaml:"resources,omitempty"`
+
+	// Written by the deploy command
+	Contracts  DeployContracts `json:"contracts" toml:"contracts" yaml:"contracts"`
+	StartBlock string          `json:"startBlock,omitempty" toml:"startBlock,omitempty" yaml:"startBlock,omitempty"`
+}
+
+// DeployContracts are the addresses of the bridge contracts of an ethereum chain
+type DeployContracts struct {
+	Bridge         string `json:"bridge,omitempty" toml:"bridge,omitempty" yaml:"bridge,omitempty"`
+	Erc20Handler   string `json:"erc20Handler,omitempty" toml:"erc20Handler,omitempty" yaml:"erc20Handler,omitempty"`
+	Erc721Handler  string `json:"erc721Handler,omitempty" toml:"erc721Handler,omitempty" yaml:"erc721Handler,omitempty"`
+	GenericHandler string `json:"genericHandler,omitempty" toml:"genericHandler,omitempty" yaml:"genericHandler,omitempty"`
+}
+
+// DeployResource is a resource registered on a chain. Ethereum chains use the type, the contract and its settings,
+// substrate chains the method executing the resource.
+type DeployResource struct {
+	Id         string `json:"id" toml:"id" yaml:"id"`
+	Type       string `json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty"`
+	Address    string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"` // Deployed for tokens if empty
+	Name       string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty"`
+	Symbol     string `json:"symbol,omitempty" toml:"symbol,omitempty" yaml:"symbol,omitempty"`
+	Burnable   bool   `json:"burnable,omitempty" toml:"burnable,omitempty" yaml:"burnable,omitempty"` // Burn on deposit and mint on execution
+	DepositSig string `json:"depositSig,omitempty" toml:"depositSig,omitempty" yaml:"depositSig,omitempty"`
+	ExecuteSig string `json:"executeSig,omitempty" toml:"executeSig,omitempty" yaml:"executeSig,omitempty"`
+	Method     string `json:"method,omitempty" toml:"method,omitempty" yaml:"method,omitempty"`
+}
+
+// RelayerFrom returns the relayer used as from of the relayer config
+func (c *DeployChain) RelayerFrom() string {
+	if c.Relayer != "" {
+		return c.Relayer
+	}
+	if len(c.Relayers) > 0 {
+		return c.Relayers[0]
+	}
+	return ""
+}
+
+// LoadDeploySpec reads and validates a JSON, TOML or YAML deployment spec
+func LoadDeploySpec(file string) (*DeploySpec, error) {
+	var spec DeploySpec
+	err := loadConfig(file, &spec)
+	if err != nil {
+		return nil, err
+	}
+	err = spec.validate()
+	if err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Write saves the spec in the format matching the file extension
+func (s *DeploySpec) Write(file string) error {
+	raw, err := marshal(*s, filepath.Ext(file))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, raw, 0600)
+}
+
+func (s *DeploySpec) validate() error {
+	if len(s.Chains) == 0 {
+		return fmt.Errorf("deployment spec has no chains")
+	}
+	ids := make(map[string]bool)
+	for _, chain := range s.Chains {
+		if _, err := strconv.ParseUint(chain.Id, 10, 8); err != nil {
+			return fmt.Errorf("invalid id %q for chain %s, expected 0 to 255", chain.Id, chain.Name)
+		}
+		if ids[chain.Id] {
+			return fmt.Errorf("duplicate chain id %s", chain.Id)
+		}
+		ids[chain.Id] = true
+		if chain.Type != "ethereum" && chain.Type != "substrate" {
+			return fmt.Errorf("unsupported type %q for chain %s", chain.Type, chain.Id)
+		}
+		if chain.Name == "" {
+			return fmt.Errorf("required field chain.Name empty for chain %s", chain.Id)
+		}
+		if chain.Endpoint == "" {
+			return fmt.Errorf("required field chain.Endpoint empty for chain %s", chain.Id)
+		}
+		if chain.From == "" {
+			return fmt.Errorf("required field chain.From empty for chain %s", chain.Id)
+		}
+		if s.Threshold == 0 || int(s.Threshold) > len(chain.Relayers) {
+			return fmt.Errorf("threshold %d must be between 1 and the %d relayers of chain %s", s.Threshold, len(chain.Relayers), chain.Id)
+		}
+		for _, res := range chain.Resources {
+			if err := validateResource(chain.Type, res); err != nil {
+				return fmt.Errorf("chain %s: %w", chain.Id, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateResource(chainType string, res DeployResource) error {
+	bz, err := hex.DecodeString(strings.TrimPrefix(res.Id, "0x"))
+	if err != nil || len(bz) != 32 {
+		return fmt.Errorf("invalid resource id %q, expected 32 bytes of hex", res.Id)
+	}
+	if chainType == "substrate" {
+		if res.Method == "" {
+			return fmt.Errorf("resource %s has no method", res.Id)
+		}
+		return nil
+	}
+	switch res.Type {
+	case Erc20Resource, Erc721Resource:
+	case GenericResource:
+		if res.Address == "" {
+			return fmt.Errorf("generic resource %s has no address", res.Id)
+		}
+	default:
+		return fmt.Errorf("unsupported type %q for resource %s, expected erc20, erc721 or generic", res.Type, res.Id)
+	}
+	return nil
+}
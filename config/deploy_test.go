@@ -0,0 +1,102 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testRId = "0x000000000000000000000000000000c76ebe4a02bbc34786d860b355f5a5ce00"
+
+func testDeploySpec() *DeploySpec {
+	return &DeploySpec{
+		Threshold: 1,
+		Chains: []DeployChain{
+			{
+				Name:      "eth",
+				Type:      "ethereum",
+				Id:        "0",
+				Endpoint:  "ws://localhost:8545",
+				From:      "0xff93B45308FD417dF303D6515aB04D9e89a750Ca",
+				Relayers:  []string{"0xff93B45308FD417dF303D6515aB04D9e89a750Ca"},
+				Resources: []DeployResource{{Id: testRId, Type: Erc20Resource, Name: "Token", Symbol: "TKN", Burnable: true}},
+			},
+			{
+				Name:      "sub",
+				Type:      "substrate",
+				Id:        "1",
+				Endpoint:  "ws://localhost:9944",
+				From:      "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+				Relayers:  []string{"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"},
+				Opts:      map[string]string{"runtime": "acala"},
+				Resources: []DeployResource{{Id: testRId, Method: "ChainSafeTransfer.transfer_from_bridge"}},
+			},
+		},
+	}
+}
+
+func TestDeploySpec_WriteAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spec := testDeploySpec()
+	spec.Chains[0].Contracts.Bridge = "0x62877dDCd49aD22f5eDfc6ac108e9a4b5D2bD88B"
+	spec.Chains[0].StartBlock = "10"
+
+	for _, ext := range []string{".json", ".toml", ".yaml"} {
+		path := filepath.Join(dir, "spec"+ext)
+		err = spec.Write(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadDeploySpec(path)
+		if err != nil {
+			t.Fatalf("%s: %s", ext, err)
+		}
+		if !reflect.DeepEqual(spec, loaded) {
+			t.Fatalf("%s: spec mismatch.\n\tGot: %+v\n\tExpected: %+v", ext, loaded, spec)
+		}
+	}
+}
+
+func TestDeploySpec_Validate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(spec *DeploySpec)
+		err    string
+	}{
+		{"valid", func(spec *DeploySpec) {}, ""},
+		{"no chains", func(spec *DeploySpec) { spec.Chains = nil }, "no chains"},
+		{"invalid id", func(spec *DeploySpec) { spec.Chains[0].Id = "256" }, "invalid id"},
+		{"duplicate id", func(spec *DeploySpec) { spec.Chains[1].Id = "0" }, "duplicate chain id"},
+		{"unknown type", func(spec *DeploySpec) { spec.Chains[0].Type = "bitcoin" }, "unsupported type"},
+		{"no from", func(spec *DeploySpec) { spec.Chains[1].From = "" }, "chain.From"},
+		{"zero threshold", func(spec *DeploySpec) { spec.Threshold = 0 }, "threshold"},
+		{"threshold above relayers", func(spec *DeploySpec) { spec.Threshold = 2 }, "threshold"},
+		{"short resource id", func(spec *DeploySpec) { spec.Chains[0].Resources[0].Id = "0x01" }, "invalid resource id"},
+		{"unknown resource type", func(spec *DeploySpec) { spec.Chains[0].Resources[0].Type = "erc1155" }, "unsupported type"},
+		{"generic without address", func(spec *DeploySpec) { spec.Chains[0].Resources[0].Type = GenericResource }, "no address"},
+		{"substrate without method", func(spec *DeploySpec) { spec.Chains[1].Resources[0].Method = "" }, "no method"},
+	}
+
+	for _, tc := range testCases {
+		spec := testDeploySpec()
+		tc.modify(spec)
+		err := spec.validate()
+		if tc.err == "" && err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+		}
+		if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.err, err)
+		}
+	}
+}
@@ -4,6 +4,8 @@
 package config
 
 import (
+	"time"
+
 	log "github.com/ChainSafe/log15"
 	"github.com/urfave/cli/v2"
 )
@@ -16,7 +18,7 @@ var (
 var (
 	ConfigFileFlag = &cli.StringFlag{
 		Name:  "config",
-		Usage: "JSON configuration file",
+		Usage: "JSON, TOML or YAML configuration file",
 	}
 
 	VerbosityFlag = &cli.StringFlag{
@@ -101,3 +103,139 @@ var (
 		Usage: "Applies a predetermined test keystore to the chains.",
 	}
 )
+
+// Admin subcommand flags
+var (
+	EndpointFlag = &cli.StringFlag{
+		Name:  "url",
+		Usage: "Endpoint of the chain",
+	}
+	FromFlag = &cli.StringFlag{
+		Name:  "from",
+		Usage: "Address of the key in the keystore used to sign transactions",
+	}
+	BridgeFlag = &cli.StringFlag{
+		Name:  "bridge",
+		Usage: "Address of the bridge contract",
+	}
+	HandlerFlag = &cli.StringFlag{
+		Name:  "handler",
+		Usage: "Address of the handler contract",
+	}
+	TargetFlag = &cli.StringFlag{
+		Name:  "target",
+		Usage: "Address of the token or generic contract",
+	}
+	ResourceIdFlag = &cli.StringFlag{
+		Name:  "resourceId",
+		Usage: "Resource ID as 32 bytes of hex",
+	}
+	RelayerFlag = &cli.StringFlag{
+		Name:  "relayer",
+		Usage: "Address of the relayer",
+	}
+	ThresholdFlag = &cli.Uint64Flag{
+		Name:  "threshold",
+		Usage: "Number of relayer votes required to pass a proposal",
+	}
+	FeeFlag = &cli.StringFlag{
+		Name:  "fee",
+		Usage: "Deposit fee in wei",
+	}
+	RecipientFlag = &cli.StringFlag{
+		Name:  "recipient",
+		Usage: "Address receiving the withdrawn tokens",
+	}
+	AmountFlag = &cli.StringFlag{
+		Name:  "amount",
+		Usage: "Amount of tokens, or the token ID for ERC721 tokens",
+	}
+	DepositSigFlag = &cli.StringFlag{
+		Name:  "deposit",
+		Usage: "Function called by the generic handler on deposits, as a signature like store(bytes32) or 4 bytes of hex",
+	}
+	ExecuteSigFlag = &cli.StringFlag{
+		Name:  "execute",
+		Usage: "Function called by the generic handler on execution, as a signature like store(bytes32) or 4 bytes of hex",
+	}
+	AdminGasLimitFlag = &cli.Uint64Flag{
+		Name:  "gasLimit",
+		Usage: "Gas limit for transactions",
+		Value: 6721975,
+	}
+	AdminGasPriceFlag = &cli.StringFlag{
+		Name:  "gasPrice",
+		Usage: "Gas price for transactions in wei",
+		Value: "20000000000",
+	}
+)
+
+// Substrate admin subcommand flags
+var (
+	RuntimeFlag = &cli.StringFlag{
+		Name:  "runtime",
+		Usage: "Runtime of the chain: chainsafe, acala or karura",
+		Value: "chainsafe",
+	}
+	BatchFlag = &cli.BoolFlag{
+		Name:  "batch",
+		Usage: "Submit all calls at once with BatchSubmit instead of one after another",
+	}
+	SubRelayerFlag = &cli.StringSliceFlag{
+		Name:  "relayer",
+		Usage: "SS58 address or hex account ID of a relayer, may be repeated",
+	}
+	ChainIdFlag = &cli.StringSliceFlag{
+		Name:  "chainId",
+		Usage: "ID of a chain to whitelist, may be repeated",
+	}
+	ResourceFlag = &cli.StringSliceFlag{
+		Name:  "resource",
+		Usage: "Resource ID and the method it executes as resourceId:method, eg. 0x00...01:Example.transfer, may be repeated",
+	}
+)
+
+// Deploy subcommand flags
+var (
+	DeploySpecFlag = &cli.StringFlag{
+		Name:  "spec",
+		Usage: "JSON, TOML or YAML deployment spec, contract addresses are written back to it",
+	}
+)
+
+// Status command flags
+var (
+	SourceChainFlag = &cli.StringFlag{
+		Name:  "src",
+		Usage: "ID of the chain the deposit was made on",
+	}
+	DestChainFlag = &cli.StringFlag{
+		Name:  "dest",
+		Usage: "ID of the destination chain of the deposit, may be omitted if the config has two chains",
+	}
+	NonceFlag = &cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "Nonce of the deposit",
+	}
+)
+
+// Relay command flags
+var (
+	RelayChainFlag = &cli.StringFlag{
+		Name:  "chain",
+		Usage: "ID of the chain the deposit was made on",
+	}
+	TxHashFlag = &cli.StringFlag{
+		Name:  "tx",
+		Usage: "Hash of the transaction making the deposit, ethereum chains only",
+	}
+	BlockFlag = &cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Block containing the deposit",
+	}
+	RelayTimeoutFlag = &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "How long to wait for a proposal to be executed after voting on it",
+		Value: 10 * time.Minute,
+	}
+)
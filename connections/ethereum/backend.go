@@ -0,0 +1,152 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var _ bind.ContractBackend = &backend{}
+
+// backend forwards all calls to the client of the active endpoint, so bound contracts follow endpoint failover.
+// The result of every call is recorded, so failing calls fail over to another endpoint.
+type backend struct {
+	conn *Connection
+}
+
+// Backend returns a contract backend that always uses the active endpoint
+func (c *Connection) Backend() bind.ContractBackend {
+	return &backend{conn: c}
+}
+
+// client returns the client of the active endpoint, or ErrNotConnected
+func (b *backend) client() (*ethclient.Client, error) {
+	client := b.conn.Client()
+	if client == nil {
+		return nil, ErrNotConnected
+	}
+	return client, nil
+}
+
+func (b *backend) CodeAt(ctx context.Context, contract ethcommon.Address, blockNumber *big.Int) ([]byte, error) {
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	code, err := client.CodeAt(ctx, contract, blockNumber)
+	b.conn.recordResult(err)
+	return code, err
+}
+
+func (b *backend) CallContract(ctx context.Context, call eth.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.CallContract(ctx, call, blockNumber)
+	b.conn.recordResult(err)
+	return res, err
+}
+
+func (b *backend) HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	header, err := client.HeaderByNumber(ctx, number)
+	b.conn.recordResult(err)
+	return header, err
+}
+
+func (b *backend) PendingCodeAt(ctx context.Context, account ethcommon.Address) ([]byte, error) {
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	code, err := client.PendingCodeAt(ctx, account)
+	b.conn.recordResult(err)
+	return code, err
+}
+
+func (b *backend) PendingNonceAt(ctx context.Context, account ethcommon.Address) (uint64, error) {
+	client, err := b.client()
+	if err != nil {
+		return 0, err
+	}
+	nonce, err := client.PendingNonceAt(ctx, account)
+	b.conn.recordResult(err)
+	return nonce, err
+}
+
+func (b *backend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	price, err := client.SuggestGasPrice(ctx)
+	b.conn.recordResult(err)
+	return price, err
+}
+
+func (b *backend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	tip, err := client.SuggestGasTipCap(ctx)
+	b.conn.recordResult(err)
+	return tip, err
+}
+
+func (b *backend) EstimateGas(ctx context.Context, call eth.CallMsg) (uint64, error) {
+	client, err := b.client()
+	if err != nil {
+		return 0, err
+	}
+	gas, err := client.EstimateGas(ctx, call)
+	b.conn.recordResult(err)
+	return gas, err
+}
+
+func (b *backend) SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error {
+	client, err := b.client()
+	if err != nil {
+		return err
+	}
+	err = client.SendTransaction(ctx, tx)
+	b.conn.recordResult(err)
+	return err
+}
+
+func (b *backend) FilterLogs(ctx context.Context, query eth.FilterQuery) ([]ethtypes.Log, error) {
+	return b.conn.FilterLogs(ctx, query)
+}
+
+func (b *backend) SubscribeFilterLogs(ctx context.Context, query eth.FilterQuery, ch chan<- ethtypes.Log) (eth.Subscription, error) {
+	client, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	sub, err := client.SubscribeFilterLogs(ctx, query, ch)
+	b.conn.recordResult(err)
+	return sub, err
+}
+
+// FilterLogs queries the logs of the active endpoint and records the result for failover
+func (c *Connection) FilterLogs(ctx context.Context, query eth.FilterQuery) ([]ethtypes.Log, error) {
+	client := c.Client()
+	if client == nil {
+		return nil, ErrNotConnected
+	}
+	logs, err := client.FilterLogs(ctx, query)
+	c.recordResult(err)
+	return logs, err
+}
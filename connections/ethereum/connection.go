@@ -22,14 +22,21 @@ import (
 
 var BlockRetryInterval = time.Second * 5
 
+var ErrNotConnected = errors.New("not connected to any endpoint")
+
 type Connection struct {
-	endpoint      string
+	endpoints     []*endpoint // all configured endpoints, in order of configuration
+	active        *endpoint   // endpoint currently in use
+	connLock      sync.RWMutex
 	http          bool
 	kp            *secp256k1.Keypair
 	gasLimit      *big.Int
 	maxGasPrice   *big.Int
 	gasMultiplier *big.Float
-	conn          *ethclient.Client
+	// EIP-1559 fee settings, see EnableDynamicFees
+	dynamicFees           bool
+	maxPriorityFee        *big.Int
+	priorityFeePercentile float64
 	// signer    ethtypes.Signer
 	opts     *bind.TransactOpts
 	callOpts *bind.CallOpts
@@ -40,9 +47,14 @@ type Connection struct {
 }
 
 // NewConnection returns an uninitialized connection, must call Connection.Connect() before using.
-func NewConnection(endpoint string, http bool, kp *secp256k1.Keypair, log log15.Logger, gasLimit, gasPrice *big.Int, gasMultiplier *big.Float) *Connection {
+// The first endpoint is preferred, the remaining endpoints are used as fallbacks.
+func NewConnection(endpoints []string, http bool, kp *secp256k1.Keypair, log log15.Logger, gasLimit, gasPrice *big.Int, gasMultiplier *big.Float) *Connection {
+	var eps []*endpoint
+	for _, url := range endpoints {
+		eps = append(eps, &endpoint{url: url})
+	}
 	return &Connection{
-		endpoint:      endpoint,
+		endpoints:     eps,
 		http:          http,
 		kp:            kp,
 		gasLimit:      gasLimit,
@@ -53,21 +65,28 @@ func NewConnection(endpoint string, http bool, kp *secp256k1.Keypair, log log15.
 	}
 }
 
-// Connect starts the ethereum WS connection
+// Connect starts the ethereum WS connection to all endpoints. If more than one endpoint is configured, their
+// health is checked in the background and the connection fails over to another endpoint when required.
 func (c *Connection) Connect() error {
-	c.log.Info("Connecting to ethereum chain...", "url", c.endpoint)
-	var rpcClient *rpc.Client
 	var err error
-	// Start http or ws client
-	if c.http {
-		rpcClient, err = rpc.DialHTTP(c.endpoint)
-	} else {
-		rpcClient, err = rpc.DialWebsocket(context.Background(), c.endpoint, "/ws")
+	for _, e := range c.endpoints {
+		c.log.Info("Connecting to ethereum chain...", "url", e.url)
+		err = e.dial(c.http)
+		if err != nil {
+			c.log.Warn("Failed to connect to endpoint", "url", e.url, "err", err)
+			continue
+		}
+		e.healthy = true
+		if c.active == nil {
+			c.active = e
+		}
 	}
-	if err != nil {
+	if c.active == nil {
+		if err == nil {
+			err = errors.New("no endpoints configured")
+		}
 		return err
 	}
-	c.conn = ethclient.NewClient(rpcClient)
 
 	// Construct tx opts, call opts, and nonce mechanism
 	opts, _, err := c.newTransactOpts(big.NewInt(0), c.gasLimit, c.maxGasPrice)
@@ -77,20 +96,109 @@ func (c *Connection) Connect() error {
 	c.opts = opts
 	c.nonce = 0
 	c.callOpts = &bind.CallOpts{From: c.kp.CommonAddress()}
+
+	if len(c.endpoints) > 1 {
+		go c.healthCheck()
+	}
 	return nil
 }
 
+// healthCheck periodically checks all endpoints until the connection is closed
+func (c *Connection) healthCheck() {
+	ticker := time.NewTicker(HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.checkEndpoints()
+		}
+	}
+}
+
+// checkEndpoints updates the health of all endpoints and switches to a better endpoint if the active one is
+// unreachable or lagging behind.
+func (c *Connection) checkEndpoints() {
+	// Check copies of the endpoints so the connection isn't locked during the requests
+	c.connLock.RLock()
+	results := make([]endpoint, len(c.endpoints))
+	for i, e := range c.endpoints {
+		results[i] = endpoint{url: e.url, rpc: e.rpc, client: e.client}
+	}
+	c.connLock.RUnlock()
+
+	for i := range results {
+		results[i].check(c.http)
+	}
+
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	for i, e := range c.endpoints {
+		e.rpc = results[i].rpc
+		e.client = results[i].client
+		e.healthy = results[i].healthy
+		e.latency = results[i].latency
+		e.latestBlock = results[i].latestBlock
+	}
+
+	best := highestBlock(c.endpoints)
+	ranked := rankEndpoints(c.endpoints)
+	if ranked[0] != c.active && endpointScore(ranked[0], best) < endpointScore(c.active, best) {
+		c.switchEndpoint(ranked[0])
+	}
+}
+
+// recordResult tracks errors of requests made to the active endpoint. After EndpointFailureLimit consecutive
+// errors the connection fails over to the best ranked endpoint. Errors returned by the node itself, such as a
+// reverted call, show the endpoint is working. Returns true if the endpoint was switched.
+func (c *Connection) recordResult(err error) bool {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+
+	if c.active == nil {
+		return false
+	}
+	if !endpointFailure(err) {
+		c.active.failures = 0
+		return false
+	}
+
+	c.active.failures++
+	if c.active.failures < EndpointFailureLimit {
+		return false
+	}
+
+	c.active.healthy = false
+	for _, e := range rankEndpoints(c.endpoints) {
+		if e != c.active && e.client != nil && e.healthy {
+			c.switchEndpoint(e)
+			return true
+		}
+	}
+	c.log.Warn("No healthy endpoint available to fail over to", "url", c.active.url, "err", err)
+	return false
+}
+
+// switchEndpoint makes e the active endpoint. Must be called with connLock held.
+func (c *Connection) switchEndpoint(e *endpoint) {
+	c.log.Warn("Switching rpc endpoint", "from", c.active.url, "to", e.url)
+	c.active.failures = 0
+	e.failures = 0
+	c.active = e
+}
+
 // newTransactOpts builds the TransactOpts for the connection's keypair.
 func (c *Connection) newTransactOpts(value, gasLimit, gasPrice *big.Int) (*bind.TransactOpts, uint64, error) {
 	privateKey := c.kp.PrivateKey()
 	address := ethcrypto.PubkeyToAddress(privateKey.PublicKey)
 
-	nonce, err := c.conn.PendingNonceAt(context.Background(), address)
+	nonce, err := c.Client().PendingNonceAt(context.Background(), address)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	id, err := c.conn.ChainID(context.Background())
+	id, err := c.Client().ChainID(context.Background())
 	if err != nil {
 		return nil, 0, err
 	}
@@ -113,8 +221,24 @@ func (c *Connection) Keypair() *secp256k1.Keypair {
 	return c.kp
 }
 
+// Client returns the client of the active endpoint, nil if no endpoint could be connected to
 func (c *Connection) Client() *ethclient.Client {
-	return c.conn
+	c.connLock.RLock()
+	defer c.connLock.RUnlock()
+	if c.active == nil {
+		return nil
+	}
+	return c.active.client
+}
+
+// rpcClient returns the rpc client of the active endpoint, nil if no endpoint could be connected to
+func (c *Connection) rpcClient() *rpc.Client {
+	c.connLock.RLock()
+	defer c.connLock.RUnlock()
+	if c.active == nil {
+		return nil
+	}
+	return c.active.rpc
 }
 
 func (c *Connection) Opts() *bind.TransactOpts {
@@ -127,8 +251,8 @@ func (c *Connection) CallOpts() *bind.CallOpts {
 
 func (c *Connection) SafeEstimateGas(ctx context.Context) (*big.Int, error) {
 
-	suggestedGasPrice, err := c.conn.SuggestGasPrice(context.TODO())
-
+	suggestedGasPrice, err := c.Client().SuggestGasPrice(context.TODO())
+	c.recordResult(err)
 	if err != nil {
 		return nil, err
 	}
@@ -157,17 +281,19 @@ func multiplyGasPrice(gasEstimate *big.Int, gasMultiplier *big.Float) *big.Int {
 }
 
 // LockAndUpdateOpts acquires a lock on the opts before updating the nonce
-// and gas price.
+// and gas price. If dynamic fees are enabled and the chain reports a base fee,
+// the opts are set up for an EIP-1559 transaction instead.
 func (c *Connection) LockAndUpdateOpts() error {
 	c.optsLock.Lock()
 
-	gasPrice, err := c.SafeEstimateGas(context.TODO())
+	err := c.updateGasPrice()
 	if err != nil {
+		c.optsLock.Unlock()
 		return err
 	}
-	c.opts.GasPrice = gasPrice
 
-	nonce, err := c.conn.PendingNonceAt(context.Background(), c.opts.From)
+	nonce, err := c.Client().PendingNonceAt(context.Background(), c.opts.From)
+	c.recordResult(err)
 	if err != nil {
 		c.optsLock.Unlock()
 		return err
@@ -176,13 +302,43 @@ func (c *Connection) LockAndUpdateOpts() error {
 	return nil
 }
 
+// updateGasPrice sets either the dynamic fees or the legacy gas price on the opts
+func (c *Connection) updateGasPrice() error {
+	if c.dynamicFees {
+		tipCap, feeCap, err := c.EstimateDynamicFees(context.TODO())
+		if err == nil {
+			c.opts.GasPrice = nil
+			c.opts.GasTipCap = tipCap
+			c.opts.GasFeeCap = feeCap
+			return nil
+		} else if !errors.Is(err, ErrNoBaseFee) {
+			return err
+		}
+		c.log.Debug("No base fee reported, falling back to legacy gas price")
+	}
+
+	gasPrice, err := c.SafeEstimateGas(context.TODO())
+	if err != nil {
+		return err
+	}
+	c.opts.GasPrice = gasPrice
+	c.opts.GasTipCap = nil
+	c.opts.GasFeeCap = nil
+	return nil
+}
+
 func (c *Connection) UnlockOpts() {
 	c.optsLock.Unlock()
 }
 
-// LatestBlock returns the latest block from the current chain
+// LatestBlock returns the latest block from the current chain. The request is retried once if the connection
+// failed over to another endpoint.
 func (c *Connection) LatestBlock() (*big.Int, error) {
-	header, err := c.conn.HeaderByNumber(context.Background(), nil)
+	header, err := c.Client().HeaderByNumber(context.Background(), nil)
+	if c.recordResult(err) {
+		header, err = c.Client().HeaderByNumber(context.Background(), nil)
+		c.recordResult(err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +347,8 @@ func (c *Connection) LatestBlock() (*big.Int, error) {
 
 // EnsureHasBytecode asserts if contract code exists at the specified address
 func (c *Connection) EnsureHasBytecode(addr ethcommon.Address) error {
-	code, err := c.conn.CodeAt(context.Background(), addr, nil)
+	code, err := c.Client().CodeAt(context.Background(), addr, nil)
+	c.recordResult(err)
 	if err != nil {
 		return err
 	}
@@ -232,8 +389,12 @@ func (c *Connection) WaitForBlock(targetBlock *big.Int, delay *big.Int) error {
 
 // Close terminates the client connection and stops any running routines
 func (c *Connection) Close() {
-	if c.conn != nil {
-		c.conn.Close()
-	}
 	close(c.stop)
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	for _, e := range c.endpoints {
+		if e.client != nil {
+			e.client.Close()
+		}
+	}
 }
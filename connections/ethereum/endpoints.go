@@ -0,0 +1,128 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Interval between health checks of all configured endpoints
+var HealthCheckInterval = time.Second * 15
+
+// Timeout for a single endpoint health check
+var HealthCheckTimeout = time.Second * 5
+
+// Number of consecutive errors on the active endpoint before switching to another
+var EndpointFailureLimit = 3
+
+// Number of blocks an endpoint may lag behind the best known endpoint before it is considered unhealthy
+var MaxEndpointBlockLag = int64(5)
+
+// endpoint tracks the client and health of a single rpc endpoint
+type endpoint struct {
+	url         string
+	rpc         *rpc.Client
+	client      *ethclient.Client
+	healthy     bool
+	latency     time.Duration
+	latestBlock *big.Int
+	failures    int
+}
+
+// dial opens a ws or http connection to the endpoint
+func (e *endpoint) dial(http bool) error {
+	var rpcClient *rpc.Client
+	var err error
+	// Start http or ws client
+	if http {
+		rpcClient, err = rpc.DialHTTP(e.url)
+	} else {
+		rpcClient, err = rpc.DialWebsocket(context.Background(), e.url, "/ws")
+	}
+	if err != nil {
+		return err
+	}
+	e.rpc = rpcClient
+	e.client = ethclient.NewClient(rpcClient)
+	return nil
+}
+
+// check fetches the latest header from the endpoint, recording its height and response time
+func (e *endpoint) check(http bool) {
+	if e.client == nil {
+		if err := e.dial(http); err != nil {
+			e.healthy = false
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), HealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	header, err := e.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		e.healthy = false
+		return
+	}
+	e.latency = time.Since(start)
+	e.latestBlock = header.Number
+	e.healthy = true
+}
+
+// highestBlock returns the highest block reported by any healthy endpoint
+func highestBlock(endpoints []*endpoint) *big.Int {
+	best := big.NewInt(0)
+	for _, e := range endpoints {
+		if e.healthy && e.latestBlock != nil && e.latestBlock.Cmp(best) == 1 {
+			best = e.latestBlock
+		}
+	}
+	return best
+}
+
+// endpointScore classifies an endpoint, lower is better. Healthy endpoints score 0, endpoints lagging more than
+// MaxEndpointBlockLag blocks behind the highest known block score 1 and unreachable endpoints score 2.
+func endpointScore(e *endpoint, best *big.Int) int {
+	if !e.healthy || e.client == nil {
+		return 2
+	}
+	if e.latestBlock != nil && big.NewInt(0).Sub(best, e.latestBlock).Int64() > MaxEndpointBlockLag {
+		return 1
+	}
+	return 0
+}
+
+// rankEndpoints returns the endpoints ordered by preference. Endpoints are ordered by score, ties are broken by latency.
+func rankEndpoints(endpoints []*endpoint) []*endpoint {
+	best := highestBlock(endpoints)
+	ranked := make([]*endpoint, len(endpoints))
+	copy(ranked, endpoints)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		si, sj := endpointScore(ranked[i], best), endpointScore(ranked[j], best)
+		if si != sj {
+			return si < sj
+		}
+		return ranked[i].latency < ranked[j].latency
+	})
+	return ranked
+}
+
+// endpointFailure reports whether err is a failure of the endpoint rather than an error returned by the node, such
+// as a reverted call, a rejected transaction or a missing result
+func endpointFailure(err error) bool {
+	if err == nil || errors.Is(err, eth.NotFound) {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
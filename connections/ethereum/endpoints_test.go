@@ -0,0 +1,110 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/log15"
+	eth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestRankEndpoints(t *testing.T) {
+	client := &ethclient.Client{}
+	unreachable := &endpoint{url: "unreachable", client: client, healthy: false, latestBlock: big.NewInt(100)}
+	lagging := &endpoint{url: "lagging", client: client, healthy: true, latency: time.Millisecond, latestBlock: big.NewInt(90)}
+	slow := &endpoint{url: "slow", client: client, healthy: true, latency: time.Second, latestBlock: big.NewInt(100)}
+	fast := &endpoint{url: "fast", client: client, healthy: true, latency: time.Millisecond * 10, latestBlock: big.NewInt(98)}
+	undialed := &endpoint{url: "undialed", healthy: true}
+
+	ranked := rankEndpoints([]*endpoint{unreachable, undialed, lagging, slow, fast})
+
+	expected := []string{"fast", "slow", "lagging", "unreachable", "undialed"}
+	for i, e := range ranked {
+		if e.url != expected[i] {
+			t.Fatalf("Unexpected rank %d. Expected: %s Got: %s", i, expected[i], e.url)
+		}
+	}
+}
+
+func TestEndpointFailure(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+	// The node responds with an error as the method doesn't exist
+	nodeErr := client.Call(nil, "eth_call")
+	client.Close()
+	quitErr := client.Call(nil, "eth_call")
+
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{eth.NotFound, false},
+		{nodeErr, false},
+		{quitErr, true},
+		{errors.New("connection refused"), true},
+	}
+	for _, tc := range testCases {
+		if res := endpointFailure(tc.err); res != tc.expected {
+			t.Errorf("Unexpected result for %v. Expected: %t Got: %t", tc.err, tc.expected, res)
+		}
+	}
+}
+
+func TestBackend_failover(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	broken := rpc.DialInProc(server)
+	broken.Close()
+	working := rpc.DialInProc(server)
+	defer working.Close()
+
+	active := &endpoint{url: "broken", rpc: broken, client: ethclient.NewClient(broken), healthy: true}
+	fallback := &endpoint{url: "working", rpc: working, client: ethclient.NewClient(working), healthy: true}
+	conn := &Connection{endpoints: []*endpoint{active, fallback}, active: active, log: log15.New()}
+	conn.log.SetHandler(log15.DiscardHandler())
+
+	for i := 0; i < EndpointFailureLimit; i++ {
+		if _, err := conn.Backend().CallContract(context.Background(), eth.CallMsg{}, nil); err == nil {
+			t.Fatal("Expected an error from the closed endpoint")
+		}
+	}
+	if conn.active != fallback {
+		t.Fatalf("Expected failover to %s, active endpoint is %s", fallback.url, conn.active.url)
+	}
+
+	// Errors returned by the node don't count as failures
+	for i := 0; i < EndpointFailureLimit; i++ {
+		if _, err := conn.FilterLogs(context.Background(), eth.FilterQuery{}); err == nil {
+			t.Fatal("Expected an error for the missing method")
+		}
+	}
+	if conn.active != fallback || fallback.failures != 0 {
+		t.Fatalf("Unexpected failures of %s: %d", conn.active.url, conn.active.failures)
+	}
+}
+
+func TestConnection_notConnected(t *testing.T) {
+	conn := NewConnection([]string{"ws://unreachable"}, false, nil, log15.New(), nil, nil, nil)
+	if conn.Client() != nil {
+		t.Fatal("Expected no client before connecting")
+	}
+	if conn.recordResult(errors.New("failed")) {
+		t.Fatal("Unexpected failover without an active endpoint")
+	}
+	if _, err := conn.Backend().CallContract(context.Background(), eth.CallMsg{}, nil); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Expected %v, got %v", ErrNotConnected, err)
+	}
+	if _, err := conn.FilterLogs(context.Background(), eth.FilterQuery{}); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Expected %v, got %v", ErrNotConnected, err)
+	}
+}
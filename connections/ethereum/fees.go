@@ -0,0 +1,101 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Number of recent blocks used to derive the priority fee
+var FeeHistoryBlocks = 10
+
+var ErrNoBaseFee = errors.New("chain does not report a base fee")
+
+// feeHistory is the result of an eth_feeHistory request
+type feeHistory struct {
+	OldestBlock *hexutil.Big     `json:"oldestBlock"`
+	Reward      [][]*hexutil.Big `json:"reward"`
+	BaseFee     []*hexutil.Big   `json:"baseFeePerGas"`
+}
+
+// EnableDynamicFees makes the connection send EIP-1559 transactions. The priority fee is derived from the given
+// percentile of priority fees paid in recent blocks and capped at maxPriorityFee. Must be called before Connect().
+func (c *Connection) EnableDynamicFees(maxPriorityFee *big.Int, percentile float64) {
+	c.dynamicFees = true
+	c.maxPriorityFee = maxPriorityFee
+	c.priorityFeePercentile = percentile
+}
+
+// EstimateDynamicFees returns the priority fee (maxPriorityFeePerGas) and fee cap (maxFeePerGas) to use for the
+// next transaction. ErrNoBaseFee is returned if the chain does not support EIP-1559.
+func (c *Connection) EstimateDynamicFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	header, err := c.Backend().HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.BaseFee == nil {
+		return nil, nil, ErrNoBaseFee
+	}
+	baseFee := header.BaseFee
+
+	var tip *big.Int
+	var history feeHistory
+	err = c.rpcClient().CallContext(ctx, &history, "eth_feeHistory", hexutil.Uint(FeeHistoryBlocks), "latest", []float64{c.priorityFeePercentile})
+	if err == nil && len(history.Reward) != 0 {
+		tip = medianReward(history.Reward)
+		// The last base fee returned is the base fee of the next block
+		if len(history.BaseFee) != 0 && history.BaseFee[len(history.BaseFee)-1] != nil {
+			baseFee = history.BaseFee[len(history.BaseFee)-1].ToInt()
+		}
+	} else {
+		c.log.Debug("Fee history unavailable, using suggested priority fee", "err", err)
+		tip, err = c.Backend().SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tipCap, feeCap := dynamicFees(baseFee, multiplyGasPrice(tip, c.gasMultiplier), c.maxPriorityFee, c.maxGasPrice)
+	return tipCap, feeCap, nil
+}
+
+// medianReward returns the median of the priority fees of each block
+func medianReward(rewards [][]*hexutil.Big) *big.Int {
+	var fees []*big.Int
+	for _, blockRewards := range rewards {
+		if len(blockRewards) != 0 && blockRewards[0] != nil {
+			fees = append(fees, blockRewards[0].ToInt())
+		}
+	}
+	if len(fees) == 0 {
+		return big.NewInt(0)
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i].Cmp(fees[j]) == -1 })
+	return fees[len(fees)/2]
+}
+
+// dynamicFees computes the priority fee and fee cap for the next block. The fee cap allows for the base fee to
+// double, but never exceeds maxFee. The priority fee is limited by maxTip and the fee cap.
+func dynamicFees(baseFee, tip, maxTip, maxFee *big.Int) (*big.Int, *big.Int) {
+	tipCap := new(big.Int).Set(tip)
+	if maxTip != nil && tipCap.Cmp(maxTip) == 1 {
+		tipCap.Set(maxTip)
+	}
+
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(2))
+	feeCap.Add(feeCap, tipCap)
+	if feeCap.Cmp(maxFee) == 1 {
+		feeCap.Set(maxFee)
+	}
+
+	if tipCap.Cmp(feeCap) == 1 {
+		tipCap.Set(feeCap)
+	}
+	return tipCap, feeCap
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestMedianReward(t *testing.T) {
+	rewards := [][]*hexutil.Big{
+		{(*hexutil.Big)(big.NewInt(30))},
+		{(*hexutil.Big)(big.NewInt(10))},
+		{},
+		{(*hexutil.Big)(big.NewInt(20))},
+	}
+	if median := medianReward(rewards); median.Cmp(big.NewInt(20)) != 0 {
+		t.Fatalf("Unexpected median. Expected: 20 Got: %s", median)
+	}
+
+	if median := medianReward(nil); median.Sign() != 0 {
+		t.Fatalf("Unexpected median. Expected: 0 Got: %s", median)
+	}
+}
+
+func TestDynamicFees(t *testing.T) {
+	testCases := []struct {
+		name    string
+		baseFee int64
+		tip     int64
+		maxTip  int64
+		maxFee  int64
+		tipCap  int64
+		feeCap  int64
+	}{
+		{"within limits", 100, 2, 10, 1000, 2, 202},
+		{"tip capped", 100, 20, 10, 1000, 10, 210},
+		{"fee capped", 100, 2, 10, 150, 2, 150},
+		{"tip capped by fee cap", 100, 20, 50, 15, 15, 15},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tipCap, feeCap := dynamicFees(big.NewInt(tc.baseFee), big.NewInt(tc.tip), big.NewInt(tc.maxTip), big.NewInt(tc.maxFee))
+			if tipCap.Int64() != tc.tipCap || feeCap.Int64() != tc.feeCap {
+				t.Fatalf("Expected tip %d and fee cap %d, got %s and %s", tc.tipCap, tc.feeCap, tipCap, feeCap)
+			}
+		})
+	}
+}
@@ -142,8 +142,13 @@ func submitSubToEth(t *testing.T, client *subutils.Client, destId msg.ChainId, r
 	}
 	log.Info("Submitting transactions", "numberOfTxs", numberOfTxs, "amount/tx", amountPerTest)
 
-	err := subutils.BatchSubmit(client, calls)
+	results, err := subutils.BatchSubmit(client, calls)
 	if err != nil {
 		t.Fatal(err)
 	}
+	for i, res := range results {
+		if res.Err != nil {
+			t.Fatalf("Transfer %d failed: %s", i+1, res.Err)
+		}
+	}
 }
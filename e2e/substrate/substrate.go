@@ -75,7 +75,7 @@ func WaitForProposalSuccessOrFail(t *testing.T, client *utils.Client, nonce type
 
 				// Decode the event records
 				events := utils.Events{}
-				err = types.EventRecordsRaw(chng.StorageData).DecodeEventRecords(client.Meta, &events)
+				err = utils.DecodeEvents(client.Meta, types.EventRecordsRaw(chng.StorageData), &events)
 				if err != nil {
 					t.Fatal(err)
 				}
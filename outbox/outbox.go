@@ -0,0 +1,267 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+/*
+Package outbox persists the messages routed to a destination chain along with their lifecycle state, so that
+proposals which are still in flight can be resumed after a restart.
+
+Messages start as Received when they reach the writer. They become Voted once this relayer's vote has been
+submitted, and Executed once the proposal has been executed or completed on chain. Messages that cannot be
+proposed are marked Failed with a reason. Messages the writer decided not to vote on for now are Held with a reason.
+Received, Held and Voted messages are considered unfinished.
+
+Executed messages are dropped from the outbox as soon as they are recorded. Failed messages are kept for
+FailedRetention so their reason can be inspected, then dropped, so the outbox only grows with the messages in flight.
+*/
+package outbox
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+const PathPostfix = ".chainbridge/outbox"
+
+// Time Failed entries are kept in the outbox
+var FailedRetention = time.Hour * 24 * 7
+
+type State string
+
+const (
+	Received State = "received"
+	Voted    State = "voted"
+	Executed State = "executed"
+	Failed   State = "failed"
+	Held     State = "held"
+)
+
+// Outboxer records the state of messages for a destination chain
+type Outboxer interface {
+	Update(m msg.Message, state State, reason string) error
+	Unfinished() []Entry
+}
+
+var _ Outboxer = &EmptyOutbox{}
+var _ Outboxer = &Outbox{}
+
+// Dummy outbox for testing only
+type EmptyOutbox struct{}
+
+func (o *EmptyOutbox) Update(_ msg.Message, _ State, _ string) error { return nil }
+func (o *EmptyOutbox) Unfinished() []Entry                           { return nil }
+
+// Entry is a message and its current state
+type Entry struct {
+	Message msg.Message
+	State   State
+	Reason  string // Reason for the Failed or Held state
+	Updated time.Time
+}
+
+// entryJSON is the serialized form of an Entry. Message payloads only contain byte slices.
+type entryJSON struct {
+	Source       msg.ChainId      `json:"source"`
+	Destination  msg.ChainId      `json:"destination"`
+	Type         msg.TransferType `json:"type"`
+	DepositNonce msg.Nonce        `json:"depositNonce"`
+	ResourceId   string           `json:"resourceId"`
+	Payload      [][]byte         `json:"payload"`
+	State        State            `json:"state"`
+	Reason       string           `json:"reason,omitempty"`
+	Updated      time.Time        `json:"updated"`
+}
+
+func (e Entry) MarshalJSON() ([]byte, error) {
+	payload := make([][]byte, len(e.Message.Payload))
+	for i, p := range e.Message.Payload {
+		bz, ok := p.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unsupported payload type %T", p)
+		}
+		payload[i] = bz
+	}
+	return json.Marshal(entryJSON{
+		Source:       e.Message.Source,
+		Destination:  e.Message.Destination,
+		Type:         e.Message.Type,
+		DepositNonce: e.Message.DepositNonce,
+		ResourceId:   e.Message.ResourceId.Hex(),
+		Payload:      payload,
+		State:        e.State,
+		Reason:       e.Reason,
+		Updated:      e.Updated,
+	})
+}
+
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	var raw entryJSON
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	rId, err := hex.DecodeString(raw.ResourceId)
+	if err != nil {
+		return fmt.Errorf("invalid resource ID %s: %w", raw.ResourceId, err)
+	}
+	payload := make([]interface{}, len(raw.Payload))
+	for i, p := range raw.Payload {
+		payload[i] = p
+	}
+	e.Message = msg.Message{
+		Source:       raw.Source,
+		Destination:  raw.Destination,
+		Type:         raw.Type,
+		DepositNonce: raw.DepositNonce,
+		ResourceId:   msg.ResourceIdFromSlice(rId),
+		Payload:      payload,
+	}
+	e.State = raw.State
+	e.Reason = raw.Reason
+	e.Updated = raw.Updated
+	return nil
+}
+
+type entryKey struct {
+	source msg.ChainId
+	nonce  msg.Nonce
+}
+
+// Outbox implements Outboxer, storing all entries of a destination chain in a single file
+type Outbox struct {
+	path     string // Path excluding filename
+	fullPath string
+	chain    msg.ChainId
+	relayer  string
+	entries  map[entryKey]*Entry
+	lock     sync.Mutex
+}
+
+// NewOutbox loads the outbox of the chain/relayer pair. Passing an empty string for path will cause it to use
+// the home directory. Executed entries and expired Failed entries of previous runs are discarded.
+func NewOutbox(path string, chain msg.ChainId, relayer string) (*Outbox, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, PathPostfix)
+	}
+
+	o := &Outbox{
+		path:     path,
+		fullPath: filepath.Join(path, fmt.Sprintf("%s-%d.outbox", relayer, chain)),
+		chain:    chain,
+		relayer:  relayer,
+		entries:  make(map[entryKey]*Entry),
+	}
+
+	dat, err := ioutil.ReadFile(o.fullPath)
+	if os.IsNotExist(err) {
+		return o, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	err = json.Unmarshal(dat, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load outbox %s: %w", o.fullPath, err)
+	}
+	for i := range entries {
+		if entries[i].State == Executed {
+			continue
+		}
+		o.entries[keyOf(entries[i].Message)] = &entries[i]
+	}
+	o.prune(time.Now())
+	return o, nil
+}
+
+func keyOf(m msg.Message) entryKey {
+	return entryKey{source: m.Source, nonce: m.DepositNonce}
+}
+
+// Update sets the state of a message and writes the outbox to disk. Executed messages are removed.
+func (o *Outbox) Update(m msg.Message, state State, reason string) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	now := time.Now()
+	if state == Executed {
+		delete(o.entries, keyOf(m))
+	} else {
+		o.entries[keyOf(m)] = &Entry{Message: m, State: state, Reason: reason, Updated: now}
+	}
+	o.prune(now)
+	return o.write()
+}
+
+// prune removes Failed entries that were last updated more than FailedRetention ago
+func (o *Outbox) prune(now time.Time) {
+	for key, e := range o.entries {
+		if e.State == Failed && now.Sub(e.Updated) > FailedRetention {
+			delete(o.entries, key)
+		}
+	}
+}
+
+// Unfinished returns all messages that are Received, Held or Voted, ordered by source chain and deposit nonce
+func (o *Outbox) Unfinished() []Entry {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	var res []Entry
+	for _, e := range o.sorted() {
+		if e.State == Received || e.State == Held || e.State == Voted {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func (o *Outbox) sorted() []Entry {
+	entries := make([]Entry, 0, len(o.entries))
+	for _, e := range o.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Message.Source != entries[j].Message.Source {
+			return entries[i].Message.Source < entries[j].Message.Source
+		}
+		return entries[i].Message.DepositNonce < entries[j].Message.DepositNonce
+	})
+	return entries
+}
+
+// write replaces the outbox file with the current entries
+func (o *Outbox) write() error {
+	// Create dir if it does not exist
+	if _, err := os.Stat(o.path); os.IsNotExist(err) {
+		errr := os.MkdirAll(o.path, os.ModePerm)
+		if errr != nil {
+			return errr
+		}
+	}
+
+	data, err := json.MarshalIndent(o.sorted(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash can't leave a partially written outbox
+	tmp := o.fullPath + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, o.fullPath)
+}
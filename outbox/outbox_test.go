@@ -0,0 +1,147 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package outbox
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+func newTestOutbox(t *testing.T, dir string) *Outbox {
+	o, err := NewOutbox(dir, 1, "relayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o
+}
+
+func TestOutbox_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rId := msg.ResourceIdFromSlice([]byte{1, 2, 3})
+	fungible := msg.NewFungibleTransfer(0, 1, 3, big.NewInt(10), rId, []byte{0xab})
+	nonFungible := msg.NewNonFungibleTransfer(0, 1, 1, rId, big.NewInt(5), []byte{0xcd}, []byte("metadata"))
+	generic := msg.NewGenericTransfer(2, 1, 1, rId, []byte("data"))
+	executed := msg.NewGenericTransfer(2, 1, 2, rId, []byte("done"))
+	failed := msg.NewGenericTransfer(2, 1, 3, rId, []byte("bad"))
+
+	o := newTestOutbox(t, dir)
+	for _, m := range []msg.Message{fungible, nonFungible, generic, executed, failed} {
+		if err := o.Update(m, Received, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := o.Update(nonFungible, Voted, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Update(generic, Held, "simulation failed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Update(executed, Executed, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Update(failed, Failed, "invalid recipient"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := newTestOutbox(t, dir)
+	unfinished := reloaded.Unfinished()
+
+	expected := []struct {
+		m     msg.Message
+		state State
+	}{
+		{nonFungible, Voted},
+		{fungible, Received},
+		{generic, Held},
+	}
+	if len(unfinished) != len(expected) {
+		t.Fatalf("Expected %d unfinished entries, got %d", len(expected), len(unfinished))
+	}
+	for i, e := range expected {
+		if !reflect.DeepEqual(unfinished[i].Message, e.m) || unfinished[i].State != e.state {
+			t.Errorf("Unexpected entry %d.\n\tExpected: %#v (%s)\n\tGot: %#v (%s)", i, e.m, e.state, unfinished[i].Message, unfinished[i].State)
+		}
+	}
+
+	// Failed entries are kept, executed ones are discarded
+	if _, ok := reloaded.entries[keyOf(failed)]; !ok {
+		t.Error("Expected failed entry to be kept")
+	}
+	if _, ok := reloaded.entries[keyOf(executed)]; ok {
+		t.Error("Expected executed entry to be discarded")
+	}
+}
+
+func TestOutbox_Prune(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rId := msg.ResourceIdFromSlice([]byte{1})
+	executed := msg.NewGenericTransfer(2, 1, 1, rId, []byte("done"))
+	expired := msg.NewGenericTransfer(2, 1, 2, rId, []byte("old"))
+	failed := msg.NewGenericTransfer(2, 1, 3, rId, []byte("bad"))
+
+	o := newTestOutbox(t, dir)
+	for _, m := range []msg.Message{executed, expired, failed} {
+		if err := o.Update(m, Received, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Executed entries are removed as soon as they are recorded
+	if err := o.Update(executed, Executed, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := o.entries[keyOf(executed)]; ok {
+		t.Error("Expected executed entry to be removed")
+	}
+
+	// Failed entries are removed once they expire
+	if err := o.Update(expired, Failed, "execution reverted"); err != nil {
+		t.Fatal(err)
+	}
+	o.entries[keyOf(expired)].Updated = time.Now().Add(-FailedRetention - time.Minute)
+	if err := o.Update(failed, Failed, "invalid recipient"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := o.entries[keyOf(expired)]; ok {
+		t.Error("Expected expired failed entry to be removed")
+	}
+	if _, ok := o.entries[keyOf(failed)]; !ok {
+		t.Error("Expected failed entry to be kept")
+	}
+
+	// Only the recent failed entry was written
+	reloaded := newTestOutbox(t, dir)
+	if len(reloaded.entries) != 1 || reloaded.entries[keyOf(failed)] == nil {
+		t.Fatalf("Unexpected entries after reload: %v", reloaded.sorted())
+	}
+}
+
+func TestOutbox_MissingFile(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	o := newTestOutbox(t, dir)
+	if len(o.Unfinished()) != 0 {
+		t.Fatal("Expected empty outbox")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package outbox
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+var _ Outboxer = &Watcher{}
+
+// Watcher forwards updates to an outbox and keeps the latest entry of each message it sees, so callers can wait
+// for a message to reach a state
+type Watcher struct {
+	Outboxer
+	entries map[entryKey]Entry
+	changed chan struct{} // Closed and replaced whenever an entry is updated
+	lock    sync.Mutex
+}
+
+func NewWatcher(o Outboxer) *Watcher {
+	return &Watcher{
+		Outboxer: o,
+		entries:  make(map[entryKey]Entry),
+		changed:  make(chan struct{}),
+	}
+}
+
+// Update records the state of a message and passes it on to the wrapped outbox
+func (w *Watcher) Update(m msg.Message, state State, reason string) error {
+	err := w.Outboxer.Update(m, state, reason)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.entries[keyOf(m)] = Entry{Message: m, State: state, Reason: reason, Updated: time.Now()}
+	close(w.changed)
+	w.changed = make(chan struct{})
+	return err
+}
+
+// Latest returns the latest entry of a message, if it was updated since the watcher was created
+func (w *Watcher) Latest(m msg.Message) (Entry, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	e, ok := w.entries[keyOf(m)]
+	return e, ok
+}
+
+// Wait blocks until the message is updated to one of the states or the timeout expires. It returns the latest
+// entry of the message and whether one of the states was reached.
+func (w *Watcher) Wait(m msg.Message, timeout time.Duration, states ...State) (Entry, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		w.lock.Lock()
+		e, ok := w.entries[keyOf(m)]
+		changed := w.changed
+		w.lock.Unlock()
+
+		if ok {
+			for _, s := range states {
+				if e.State == s {
+					return e, true
+				}
+			}
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			return e, false
+		}
+	}
+}
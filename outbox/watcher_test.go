@@ -0,0 +1,59 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package outbox
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+)
+
+func TestWatcher_Wait(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	o := newTestOutbox(t, dir)
+	w := NewWatcher(o)
+	rId := msg.ResourceIdFromSlice([]byte{1})
+	m := msg.NewFungibleTransfer(0, 1, 4, big.NewInt(10), rId, []byte{0xab})
+	other := msg.NewFungibleTransfer(0, 1, 5, big.NewInt(10), rId, []byte{0xab})
+
+	if _, ok := w.Latest(m); ok {
+		t.Fatal("Expected no entry before an update")
+	}
+	if err := w.Update(m, Voted, ""); err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := w.Latest(m); !ok || e.State != Voted {
+		t.Fatalf("Unexpected latest entry: %#v", e)
+	}
+
+	// The wrapped outbox receives the updates
+	if unfinished := o.Unfinished(); len(unfinished) != 1 || unfinished[0].State != Voted {
+		t.Fatalf("Update was not passed on to the outbox: %#v", unfinished)
+	}
+
+	// Times out while the message is only voted on
+	e, ok := w.Wait(m, 10*time.Millisecond, Executed, Failed)
+	if ok || e.State != Voted {
+		t.Fatalf("Expected a timeout in state %s, got %s", Voted, e.State)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = w.Update(other, Executed, "")
+		_ = w.Update(m, Failed, "execution reverted")
+	}()
+	e, ok = w.Wait(m, time.Second, Executed, Failed)
+	if !ok || e.State != Failed || e.Reason != "execution reverted" {
+		t.Fatalf("Unexpected entry after waiting: %#v", e)
+	}
+}
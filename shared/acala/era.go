@@ -0,0 +1,20 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"math/big"
+
+	substrate_utils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v3"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// DefaultMortalPeriod is the number of blocks a signed extrinsic stays valid for
+const DefaultMortalPeriod = substrate_utils.DefaultMortalPeriod
+
+// NewSignatureOptions prepares the options to sign an extrinsic, see substrate_utils.NewSignatureOptions
+func NewSignatureOptions(api *gsrpc.SubstrateAPI, genesis types.Hash, nonce uint64, period uint64, tip *big.Int) (types.SignatureOptions, error) {
+	return substrate_utils.NewSignatureOptions(api, genesis, nonce, period, tip)
+}
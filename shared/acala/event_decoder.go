@@ -0,0 +1,41 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	substrate_utils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+type EventUtilityItemCompleted = substrate_utils.EventUtilityItemCompleted
+
+// TypeDefs are the type definitions of the Acala runtime, used to skip events of metadata versions that describe arguments by name
+var TypeDefs = map[string]string{
+	"TokenSymbol":  "u8",
+	"DEXShare":     "enum{0:(TokenSymbol), 1:(EvmAddress)}",
+	"CurrencyId":   "enum{0:(TokenSymbol), 1:(DEXShare, DEXShare), 2:(EvmAddress), 3:([u8; 32])}",
+	"CurrencyIdOf": "CurrencyId",
+	"TradingPair":  "(CurrencyId, CurrencyId)",
+	"Amount":       "i128",
+	"AmountOf":     "i128",
+	"DebitAmount":  "i128",
+	"DebitBalance": "u128",
+	"Share":        "u128",
+	"Rate":         "u128",
+	"Ratio":        "u128",
+	"Price":        "u128",
+	"ExchangeRate": "u128",
+	"AuctionId":    "u32",
+}
+
+func init() {
+	for name, def := range TypeDefs {
+		substrate_utils.RegisterTypeDef(name, def)
+	}
+}
+
+// DecodeEvents decodes the events target has fields for and skips all others, see substrate_utils.DecodeEvents
+func DecodeEvents(meta *types.Metadata, raw types.EventRecordsRaw, target interface{}) error {
+	return substrate_utils.DecodeEvents(meta, raw, target)
+}
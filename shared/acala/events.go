@@ -1142,7 +1142,8 @@ type EventParachainSystemDownwardMessagesProcessed struct {
 	Topics          []types.Hash
 }
 
-
+// Events decodes all events of the Acala runtime. The ChainBridge events (FungibleTransfer,
+// NonFungibleTransfer, GenericTransfer, etc.) are provided by the embedded substrate Events.
 type Events struct {
 	substrate_utils.Events
 	
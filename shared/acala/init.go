@@ -5,6 +5,7 @@ package utils
 
 import (
 	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/ChainSafe/log15"
 	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
 )
 
@@ -46,5 +47,15 @@ func InitializeChain(client *Client, relayers []types.AccountID, chains []msg.Ch
 	}
 	calls = append(calls, call)
 
-	return BatchSubmit(client, calls)
+	results, err := BatchSubmit(client, calls)
+	if err != nil {
+		return err
+	}
+	// Calls fail if the chain was initialized before, eg. when a relayer was already added
+	for i, res := range results {
+		if res.Err != nil {
+			log15.Warn("Initialization call failed", "call", i, "err", res.Err)
+		}
+	}
+	return nil
 }
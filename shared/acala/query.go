@@ -4,9 +4,10 @@
 package utils
 
 import (
-	"fmt"
 	"math/big"
 
+	substrate_utils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v3"
 	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
 )
 
@@ -18,23 +19,19 @@ func QueryStorage(client *Client, prefix, method string, arg1, arg2 []byte, resu
 	return client.Api.RPC.State.GetStorageLatest(key, result)
 }
 
-// TODO: Add to GSRPC
-func getConst(meta *types.Metadata, prefix, name string, res interface{}) error {
-	for _, mod := range meta.AsMetadataV12.Modules {
-		if string(mod.Name) == prefix {
-			for _, cons := range mod.Constants {
-				if string(cons.Name) == name {
-					return types.DecodeFromBytes(cons.Value, res)
-				}
-			}
-		}
-	}
-	return fmt.Errorf("could not find constant %s.%s", prefix, name)
+// FetchMetadata retrieves the latest metadata from the node, see substrate_utils.FetchMetadata
+func FetchMetadata(api *gsrpc.SubstrateAPI) (*types.Metadata, error) {
+	return substrate_utils.FetchMetadata(api)
+}
+
+// GetConst decodes the value of the constant prefix.name into res
+func GetConst(meta *types.Metadata, prefix, name string, res interface{}) error {
+	return substrate_utils.GetConst(meta, prefix, name, res)
 }
 
 // QueryConst looks up a constant in the metadata
 func QueryConst(client *Client, prefix, name string, res interface{}) error {
-	err := getConst(client.Meta, prefix, name, res)
+	err := GetConst(client.Meta, prefix, name, res)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,18 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	substrate_utils "github.com/ChainSafe/ChainBridge/shared/substrate"
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v3"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// CallResult is the outcome of a call submitted with BatchSubmit
+type CallResult = substrate_utils.CallResult
+
+// ExtrinsicResult looks up the outcome of an extrinsic included in the block, see substrate_utils.ExtrinsicResult
+func ExtrinsicResult(api *gsrpc.SubstrateAPI, meta *types.Metadata, block types.Hash, ext types.Extrinsic) error {
+	return substrate_utils.ExtrinsicResult(api, meta, block, ext)
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/ChainSafe/log15"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/rpc/author"
 	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
 )
 
@@ -24,12 +25,6 @@ func SubmitTx(client *Client, method Method, args ...interface{}) error {
 	}
 	ext := types.NewExtrinsic(call)
 
-	// Get latest runtime version
-	rv, err := client.Api.RPC.State.GetRuntimeVersionLatest()
-	if err != nil {
-		return err
-	}
-
 	var acct types.AccountInfo
 	_, err = QueryStorage(client, "System", "Account", client.Key.PublicKey, nil, &acct)
 	if err != nil {
@@ -37,14 +32,9 @@ func SubmitTx(client *Client, method Method, args ...interface{}) error {
 	}
 
 	// Sign the extrinsic
-	o := types.SignatureOptions{
-		BlockHash:          client.Genesis,
-		Era:                types.ExtrinsicEra{IsMortalEra: false},
-		GenesisHash:        client.Genesis,
-		Nonce:              types.NewUCompactFromUInt(uint64(acct.Nonce)),
-		SpecVersion:        rv.SpecVersion,
-		Tip:                types.NewUCompactFromUInt(0),
-		TransactionVersion: 1,
+	o, err := NewSignatureOptions(client.Api, client.Genesis, uint64(acct.Nonce), client.MortalPeriod, client.Tip)
+	if err != nil {
+		return err
 	}
 	err = ext.Sign(*client.Key, o)
 	if err != nil {
@@ -79,71 +69,74 @@ func SubmitSudoTx(client *Client, method Method, args ...interface{}) error {
 	return SubmitTx(client, SudoMethod, call)
 }
 
-// Batch submit take multiple calls and attempts to sumbit all of them, then waits until they complete.
-// This should allow for the calls to be processed in a single block (to some limit).
-// WARNING: Failed calls are not reported
-func BatchSubmit(client *Client, calls []types.Call) error {
-	// Get latest runtime version
-	rv, err := client.Api.RPC.State.GetRuntimeVersionLatest()
-	if err != nil {
-		return err
-	}
-
+// BatchSubmit signs the calls as extrinsics with consecutive nonces and submits all of them, then waits until they
+// complete. This should allow for the calls to be processed in a single block (to some limit). The result of each
+// call is returned in order, calls that were not submitted, dropped or failed to dispatch have an error.
+func BatchSubmit(client *Client, calls []types.Call) ([]CallResult, error) {
 	var acct types.AccountInfo
-	_, err = QueryStorage(client, "System", "Account", client.Key.PublicKey, nil, &acct)
+	_, err := QueryStorage(client, "System", "Account", client.Key.PublicKey, nil, &acct)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Sign the extrinsic
-	o := types.SignatureOptions{
-		BlockHash:          client.Genesis,
-		Era:                types.ExtrinsicEra{IsMortalEra: false},
-		GenesisHash:        client.Genesis,
-		Nonce:              types.NewUCompactFromUInt(uint64(acct.Nonce)),
-		SpecVersion:        rv.SpecVersion,
-		Tip:                types.NewUCompactFromUInt(0),
-		TransactionVersion: 1,
+	o, err := NewSignatureOptions(client.Api, client.Genesis, uint64(acct.Nonce), client.MortalPeriod, client.Tip)
+	if err != nil {
+		return nil, err
 	}
 
+	results := make([]CallResult, len(calls))
 	wg := &sync.WaitGroup{}
-	wg.Add(len(calls))
 
-	for _, call := range calls {
+	for i, call := range calls {
 		ext := types.NewExtrinsic(call)
 
 		err = ext.Sign(*client.Key, o)
 		if err != nil {
-			return err
+			results[i].Err = err
+			continue
 		}
 
 		// Submit and watch the extrinsic
 		sub, err := client.Api.RPC.Author.SubmitAndWatchExtrinsic(ext)
 		if err != nil {
-			return err
+			results[i].Err = fmt.Errorf("submission of extrinsic failed: %w", err)
+			continue
 		}
 
-		go func() {
+		wg.Add(1)
+		go func(res *CallResult, ext types.Extrinsic, sub *author.ExtrinsicStatusSubscription) {
+			defer wg.Done()
+			defer sub.Unsubscribe()
 			for {
-				status := <-sub.Chan()
-				switch {
-				case status.IsInBlock:
-					wg.Done()
-					return
-				case status.IsDropped:
-					wg.Done()
-					return
-				case status.IsInvalid:
-					wg.Done()
+				select {
+				case status := <-sub.Chan():
+					switch {
+					case status.IsInBlock:
+						res.Block = status.AsInBlock
+						res.Err = ExtrinsicResult(client.Api, client.Meta, res.Block, ext)
+						return
+					case status.IsDropped:
+						res.Err = fmt.Errorf("extrinsic dropped")
+						return
+					case status.IsInvalid:
+						res.Err = fmt.Errorf("extrinsic invalid")
+						return
+					case status.IsUsurped:
+						res.Err = fmt.Errorf("extrinsic usurped")
+						return
+					}
+				case err := <-sub.Err():
+					res.Err = err
 					return
 				}
 			}
-		}()
+		}(&results[i], ext, sub)
 
 		bigNonce := big.Int(o.Nonce)
 		o.Nonce = types.NewUCompactFromUInt(bigNonce.Uint64() + 1)
 	}
 
 	wg.Wait()
-	return nil
+	return results, nil
 }
@@ -37,22 +37,22 @@ type DeployedContracts struct {
 
 // DeployContracts deploys Bridge, Relayer, ERC20Handler, ERC721Handler and CentrifugeAssetHandler and returns the addresses
 func DeployContracts(client *Client, chainID uint8, initialRelayerThreshold *big.Int) (*DeployedContracts, error) {
-	bridgeAddr, err := deployBridge(client, chainID, RelayerAddresses, initialRelayerThreshold)
+	bridgeAddr, err := DeployBridge(client, chainID, RelayerAddresses, initialRelayerThreshold)
 	if err != nil {
 		return nil, err
 	}
 
-	erc20HandlerAddr, err := deployERC20Handler(client, bridgeAddr)
+	erc20HandlerAddr, err := DeployERC20Handler(client, bridgeAddr)
 	if err != nil {
 		return nil, err
 	}
 
-	erc721HandlerAddr, err := deployERC721Handler(client, bridgeAddr)
+	erc721HandlerAddr, err := DeployERC721Handler(client, bridgeAddr)
 	if err != nil {
 		return nil, err
 	}
 
-	genericHandlerAddr, err := deployGenericHandler(client, bridgeAddr)
+	genericHandlerAddr, err := DeployGenericHandler(client, bridgeAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +74,8 @@ func UpdateNonce(client *Client) error {
 	return nil
 }
 
-func deployBridge(client *Client, chainID uint8, relayerAddrs []common.Address, initialRelayerThreshold *big.Int) (common.Address, error) {
+// DeployBridge deploys a bridge with an initial relayer set, no fee and an expiry of 100 blocks
+func DeployBridge(client *Client, chainID uint8, relayerAddrs []common.Address, initialRelayerThreshold *big.Int) (common.Address, error) {
 	err := client.LockNonceAndUpdate()
 	if err != nil {
 		return ZeroAddress, err
@@ -96,7 +97,8 @@ func deployBridge(client *Client, chainID uint8, relayerAddrs []common.Address,
 
 }
 
-func deployERC20Handler(client *Client, bridgeAddress common.Address) (common.Address, error) {
+// DeployERC20Handler deploys an ERC20 handler without resources
+func DeployERC20Handler(client *Client, bridgeAddress common.Address) (common.Address, error) {
 	err := client.LockNonceAndUpdate()
 	if err != nil {
 		return ZeroAddress, err
@@ -117,7 +119,8 @@ func deployERC20Handler(client *Client, bridgeAddress common.Address) (common.Ad
 	return erc20HandlerAddr, nil
 }
 
-func deployERC721Handler(client *Client, bridgeAddress common.Address) (common.Address, error) {
+// DeployERC721Handler deploys an ERC721 handler without resources
+func DeployERC721Handler(client *Client, bridgeAddress common.Address) (common.Address, error) {
 	err := client.LockNonceAndUpdate()
 	if err != nil {
 		return ZeroAddress, err
@@ -137,7 +140,8 @@ func deployERC721Handler(client *Client, bridgeAddress common.Address) (common.A
 	return erc721HandlerAddr, nil
 }
 
-func deployGenericHandler(client *Client, bridgeAddress common.Address) (common.Address, error) {
+// DeployGenericHandler deploys a generic handler without resources
+func DeployGenericHandler(client *Client, bridgeAddress common.Address) (common.Address, error) {
 	err := client.LockNonceAndUpdate()
 	if err != nil {
 		return ZeroAddress, err
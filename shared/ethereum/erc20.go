@@ -71,6 +71,28 @@ func DeployMintApproveErc20(client *Client, erc20Handler common.Address, amount
 	return erc20Addr, nil
 }
 
+// DeployErc20 deploys a new erc20 contract with the deployer as admin and minter
+func DeployErc20(client *Client, name, symbol string) (common.Address, error) {
+	err := client.LockNonceAndUpdate()
+	if err != nil {
+		return ZeroAddress, err
+	}
+
+	erc20Addr, tx, _, err := ERC20.DeployERC20PresetMinterPauser(client.Opts, client.Client, name, symbol)
+	if err != nil {
+		return ZeroAddress, err
+	}
+
+	err = WaitForTx(client, tx)
+	if err != nil {
+		return ZeroAddress, err
+	}
+
+	client.UnlockNonce()
+
+	return erc20Addr, nil
+}
+
 func DeployAndMintErc20(client *Client, amount *big.Int) (common.Address, error) {
 	err := client.LockNonceAndUpdate()
 	if err != nil {
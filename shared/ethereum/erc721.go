@@ -11,15 +11,15 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-// DeployMintAndApprove deploys a new erc721 contract, mints to the deployer, and approves the erc20 handler to transfer those token.
-func DeployErc721(client *Client) (common.Address, error) {
+// DeployErc721 deploys a new erc721 contract with the deployer as admin and minter
+func DeployErc721(client *Client, name, symbol string) (common.Address, error) {
 	err := client.LockNonceAndUpdate()
 	if err != nil {
 		return ZeroAddress, err
 	}
 
 	// Deploy
-	addr, tx, _, err := ERC721MinterBurnerPauser.DeployERC721MinterBurnerPauser(client.Opts, client.Client, "", "", "")
+	addr, tx, _, err := ERC721MinterBurnerPauser.DeployERC721MinterBurnerPauser(client.Opts, client.Client, name, symbol, "")
 	if err != nil {
 		return ZeroAddress, err
 	}
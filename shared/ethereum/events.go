@@ -4,6 +4,8 @@
 package utils
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -30,6 +32,15 @@ const (
 	Cancelled
 )
 
+var proposalStatusNames = []string{"inactive", "active", "passed", "executed", "cancelled"}
+
+func (s ProposalStatus) String() string {
+	if s < 0 || int(s) >= len(proposalStatusNames) {
+		return fmt.Sprintf("unknown (%d)", int(s))
+	}
+	return proposalStatusNames[s]
+}
+
 func IsActive(status uint8) bool {
 	return ProposalStatus(status) == Active
 }
@@ -32,7 +32,7 @@ func GenerateErc721Tokens(start int, numOfTokens int) []Erc721 {
 }
 
 func Erc721Deploy(t *testing.T, client *utils.Client) common.Address {
-	addr, err := utils.DeployErc721(client)
+	addr, err := utils.DeployErc721(client, "", "")
 	if err != nil {
 		t.Fatal(err)
 	}
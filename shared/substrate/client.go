@@ -21,10 +21,13 @@ type Client struct {
 	Meta    *types.Metadata
 	Genesis types.Hash
 	Key     *signature.KeyringPair
+
+	MortalPeriod uint64   // Number of blocks extrinsics stay valid for, 0 signs immortal extrinsics
+	Tip          *big.Int // Tip added to extrinsics, nil for none
 }
 
 func CreateClient(key *signature.KeyringPair, endpoint string) (*Client, error) {
-	c := &Client{Key: key}
+	c := &Client{Key: key, MortalPeriod: DefaultMortalPeriod}
 	api, err := gsrpc.NewSubstrateAPI(endpoint)
 	if err != nil {
 		return nil, err
@@ -32,7 +35,7 @@ func CreateClient(key *signature.KeyringPair, endpoint string) (*Client, error)
 	c.Api = api
 
 	// Fetch metadata
-	meta, err := api.RPC.State.GetMetadataLatest()
+	meta, err := FetchMetadata(api)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,101 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"math/big"
+	"math/bits"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v3"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// DefaultMortalPeriod is the number of blocks a signed extrinsic stays valid for
+const DefaultMortalPeriod = 64
+
+const (
+	minMortalPeriod = 4
+	maxMortalPeriod = 1 << 16
+)
+
+// NewMortalEra returns the era of an extrinsic that is valid for period blocks from the current block, along with
+// the number of the block the era starts at. The period is rounded up to a power of two between 4 and 65536, as
+// required by the encoding. The hash of the starting block must be used as the block hash when signing.
+func NewMortalEra(current, period uint64) (types.ExtrinsicEra, uint64) {
+	if period < minMortalPeriod {
+		period = minMortalPeriod
+	}
+	if period > maxMortalPeriod {
+		period = maxMortalPeriod
+	}
+	period = 1 << (64 - bits.LeadingZeros64(period-1))
+
+	phase := current % period
+	quantizeFactor := period >> 12
+	if quantizeFactor < 1 {
+		quantizeFactor = 1
+	}
+	quantizedPhase := phase / quantizeFactor * quantizeFactor
+
+	low := uint64(bits.TrailingZeros64(period) - 1)
+	if low < 1 {
+		low = 1
+	}
+	if low > 15 {
+		low = 15
+	}
+	encoded := uint16(low | (quantizedPhase/quantizeFactor)<<4)
+
+	birth := (current-quantizedPhase)/period*period + quantizedPhase
+
+	era := types.ExtrinsicEra{IsMortalEra: true, AsMortalEra: types.MortalEra{First: byte(encoded), Second: byte(encoded >> 8)}}
+	return era, birth
+}
+
+// NewSignatureOptions prepares the options to sign an extrinsic with the transaction version of the latest runtime.
+// For a period greater than 0 the extrinsic is mortal and anchored at the latest finalized block, otherwise it is
+// immortal and anchored at genesis. A nil tip is treated as zero.
+func NewSignatureOptions(api *gsrpc.SubstrateAPI, genesis types.Hash, nonce uint64, period uint64, tip *big.Int) (types.SignatureOptions, error) {
+	rv, err := api.RPC.State.GetRuntimeVersionLatest()
+	if err != nil {
+		return types.SignatureOptions{}, err
+	}
+
+	if tip == nil {
+		tip = big.NewInt(0)
+	}
+
+	o := types.SignatureOptions{
+		BlockHash:          genesis,
+		Era:                types.ExtrinsicEra{IsImmortalEra: true},
+		GenesisHash:        genesis,
+		Nonce:              types.NewUCompactFromUInt(nonce),
+		SpecVersion:        rv.SpecVersion,
+		Tip:                types.NewUCompact(tip),
+		TransactionVersion: rv.TransactionVersion,
+	}
+	if period == 0 {
+		return o, nil
+	}
+
+	finalized, err := api.RPC.Chain.GetFinalizedHead()
+	if err != nil {
+		return o, err
+	}
+	header, err := api.RPC.Chain.GetHeader(finalized)
+	if err != nil {
+		return o, err
+	}
+
+	era, birth := NewMortalEra(uint64(header.Number), period)
+	o.Era = era
+	o.BlockHash = finalized
+	if birth != uint64(header.Number) {
+		o.BlockHash, err = api.RPC.Chain.GetBlockHash(birth)
+		if err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
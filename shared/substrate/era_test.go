@@ -0,0 +1,38 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func TestNewMortalEra(t *testing.T) {
+	tests := []struct {
+		current uint64
+		period  uint64
+		era     types.MortalEra
+		birth   uint64
+	}{
+		// Period 64, phase 42
+		{42, 64, types.MortalEra{First: 0xa5, Second: 0x02}, 42},
+		// Rounded up to a period of 128
+		{1000, 100, types.MortalEra{First: 0x86, Second: 0x06}, 1000},
+		// Raised to the minimum period of 4
+		{5, 1, types.MortalEra{First: 0x11, Second: 0x00}, 5},
+		// Clamped to 65536 with a quantized phase
+		{4000010, 1 << 20, types.MortalEra{First: 0x0f, Second: 0x09}, 4000000},
+	}
+
+	for _, tt := range tests {
+		era, birth := NewMortalEra(tt.current, tt.period)
+		if !era.IsMortalEra || era.AsMortalEra != tt.era {
+			t.Errorf("current %d, period %d: expected era %v, got %v", tt.current, tt.period, tt.era, era.AsMortalEra)
+		}
+		if birth != tt.birth {
+			t.Errorf("current %d, period %d: expected birth %d, got %d", tt.current, tt.period, tt.birth, birth)
+		}
+	}
+}
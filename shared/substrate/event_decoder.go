@@ -0,0 +1,334 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/ChainSafe/log15"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// DecodeEvents decodes the events of a block into target, which must be a pointer to a struct with slice fields
+// named <Module>_<Event> like the Events struct. Only the events target has a field for are decoded, all other
+// events are skipped using the sizes of their arguments as described by the metadata. New events and pallets
+// therefore do not require changes to target. Events with argument types that have no type definition are
+// skipped as well if the records following them can be located, see resync.
+func DecodeEvents(meta *types.Metadata, raw types.EventRecordsRaw, target interface{}) error {
+	ptr := reflect.ValueOf(target)
+	if ptr.Kind() != reflect.Ptr || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a pointer to a struct, got %T", target)
+	}
+	dst := ptr.Elem()
+
+	d := &eventDecoder{
+		meta:    meta,
+		raw:     raw,
+		dst:     dst,
+		args:    make(map[types.EventID][]*scaleType),
+		records: make(map[recordsKey]*recordsResult),
+	}
+	reader := bytes.NewReader(raw)
+	n, err := scale.NewDecoder(reader).DecodeUintCompact()
+	if err != nil {
+		return err
+	}
+
+	res := d.decodeRecords(len(raw)-reader.Len(), n.Uint64(), -1)
+	if res.err != nil {
+		return res.err
+	}
+	for _, name := range res.skipped {
+		log15.Warn("Skipped event with undecodable arguments", "event", name)
+	}
+	for _, evt := range res.events {
+		field := dst.Field(evt.field)
+		field.Set(reflect.Append(field, evt.value))
+	}
+	return nil
+}
+
+// decodeEvent decodes the arguments and topics of an event into a struct with a leading Phase and trailing Topics field
+func decodeEvent(decoder *scale.Decoder, t reflect.Type, phase types.Phase) (reflect.Value, error) {
+	evt := reflect.New(t).Elem()
+	if t.NumField() < 2 || t.Field(0).Name != "Phase" || t.Field(t.NumField()-1).Name != "Topics" {
+		return evt, fmt.Errorf("%s must start with Phase and end with Topics", t)
+	}
+	evt.Field(0).Set(reflect.ValueOf(phase))
+
+	for i := 1; i < t.NumField(); i++ {
+		err := decoder.Decode(evt.Field(i).Addr().Interface())
+		if err != nil {
+			return evt, fmt.Errorf("field %s: %w", t.Field(i).Name, err)
+		}
+	}
+	return evt, nil
+}
+
+// maxResyncOffsets limits the offsets tried to locate the records following undecodable events in a block
+const maxResyncOffsets = 1 << 20
+
+var (
+	errAmbiguousEvents = errors.New("the following events decode differently from several offsets")
+	errResyncLimit     = errors.New("too many offsets tried to locate the following events")
+)
+
+type eventDecoder struct {
+	meta    *types.Metadata
+	raw     []byte
+	dst     reflect.Value
+	args    map[types.EventID][]*scaleType
+	records map[recordsKey]*recordsResult // Records decoded while locating the records after undecodable events
+	offsets int                           // Offsets tried by resync
+}
+
+type recordsKey struct {
+	pos   int
+	count uint64
+	after int64
+}
+
+// recordsResult holds the decoded target events of a sequence of records, and the names of the undecodable
+// events that were skipped
+type recordsResult struct {
+	events  []decodedEvent
+	skipped []string
+	err     error
+}
+
+// decodedEvent is an event decoded into the element type of a field of the target
+type decodedEvent struct {
+	field int
+	value reflect.Value
+}
+
+// undecodableEventError is returned if the argument types of an event have no type definition
+type undecodableEventError struct {
+	err error
+}
+
+func (e *undecodableEventError) Error() string {
+	return e.err.Error()
+}
+
+// phaseOrder orders the phases of the events of a block. Events are deposited during initialization, by the
+// extrinsics in their order and during finalization.
+func phaseOrder(phase types.Phase) int64 {
+	switch {
+	case phase.IsInitialization:
+		return 0
+	case phase.IsApplyExtrinsic:
+		return 1 + int64(phase.AsApplyExtrinsic)
+	default:
+		return 2 + math.MaxUint32
+	}
+}
+
+// decodeRecords decodes count event records starting at pos, which must end with the block. If after is not
+// negative, the phases of the records must be ordered and not precede after.
+func (d *eventDecoder) decodeRecords(pos int, count uint64, after int64) *recordsResult {
+	res := &recordsResult{}
+	reader := bytes.NewReader(d.raw[pos:])
+	decoder := scale.NewDecoder(reader)
+
+	for i := uint64(0); i < count; i++ {
+		var phase types.Phase
+		// Phase does not reject unknown variants
+		if b := d.raw[len(d.raw)-reader.Len():]; len(b) > 0 && b[0] > 2 {
+			return &recordsResult{err: fmt.Errorf("unable to decode phase of event #%d: invalid variant %d", i, b[0])}
+		}
+		err := decoder.Decode(&phase)
+		if err != nil {
+			return &recordsResult{err: fmt.Errorf("unable to decode phase of event #%d: %w", i, err)}
+		}
+		if after >= 0 {
+			if phaseOrder(phase) < after {
+				return &recordsResult{err: fmt.Errorf("phase of event #%d precedes the previous event", i)}
+			}
+			after = phaseOrder(phase)
+		}
+		var id types.EventID
+		err = decoder.Decode(&id)
+		if err != nil {
+			return &recordsResult{err: fmt.Errorf("unable to decode id of event #%d: %w", i, err)}
+		}
+
+		module, event, err := d.meta.FindEventNamesForEventID(id)
+		if err != nil {
+			return &recordsResult{err: fmt.Errorf("unable to find event #%d with id %v: %w", i, id, err)}
+		}
+
+		name := fmt.Sprintf("%s_%s", module, event)
+		field, ok := d.dst.Type().FieldByName(name)
+		if ok && field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			evt, err := decodeEvent(decoder, field.Type.Elem(), phase)
+			if err != nil {
+				return &recordsResult{err: fmt.Errorf("unable to decode event %s.%s: %w", module, event, err)}
+			}
+			res.events = append(res.events, decodedEvent{field: field.Index[0], value: evt})
+			continue
+		}
+
+		err = d.skipEvent(decoder, id)
+		var undecodable *undecodableEventError
+		if errors.As(err, &undecodable) {
+			rest := d.resync(len(d.raw)-reader.Len(), count-i-1, phaseOrder(phase))
+			if rest.err != nil {
+				return &recordsResult{err: fmt.Errorf("unable to skip event %s.%s: %s: %w", module, event, undecodable, rest.err)}
+			}
+			res.events = append(res.events, rest.events...)
+			res.skipped = append(append(res.skipped, fmt.Sprintf("%s.%s", module, event)), rest.skipped...)
+			return res
+		} else if err != nil {
+			return &recordsResult{err: fmt.Errorf("unable to skip event %s.%s: %w", module, event, err)}
+		}
+		var topics []types.Hash
+		err = decoder.Decode(&topics)
+		if err != nil {
+			return &recordsResult{err: fmt.Errorf("unable to decode topics of event %s.%s: %w", module, event, err)}
+		}
+	}
+
+	if reader.Len() != 0 {
+		return &recordsResult{err: fmt.Errorf("%d bytes left after the last event", reader.Len())}
+	}
+	return res
+}
+
+// resync locates the records following an event with undecodable arguments that start at pos. The topics of the
+// event may start at every offset from which the remaining count records decode up to the end of the block, with
+// their phases in order. As the actual offset is one of them, the events are only used if all offsets result in the
+// same events. Records are memoized, as offsets tried for several undecodable events can lead to the same records.
+func (d *eventDecoder) resync(pos int, count uint64, after int64) *recordsResult {
+	var found *recordsResult
+	for p := pos; p < len(d.raw); p++ {
+		d.offsets++
+		if d.offsets > maxResyncOffsets {
+			return &recordsResult{err: errResyncLimit}
+		}
+
+		reader := bytes.NewReader(d.raw[p:])
+		var topics []types.Hash
+		err := scale.NewDecoder(reader).Decode(&topics)
+		if err != nil || !canonicalTopics(topics, len(d.raw)-p-reader.Len()) {
+			continue
+		}
+		key := recordsKey{pos: len(d.raw) - reader.Len(), count: count, after: after}
+		res, ok := d.records[key]
+		if !ok {
+			res = d.decodeRecords(key.pos, count, after)
+			d.records[key] = res
+		}
+
+		switch {
+		case errors.Is(res.err, errAmbiguousEvents) || errors.Is(res.err, errResyncLimit):
+			return res
+		case res.err != nil:
+			continue
+		case found == nil:
+			found = res
+		case !sameEvents(found.events, res.events):
+			return &recordsResult{err: errAmbiguousEvents}
+		}
+	}
+	if found == nil {
+		return &recordsResult{err: errors.New("the following events cannot be located")}
+	}
+	return found
+}
+
+// sameEvents compares decoded events by value
+func sameEvents(a, b []decodedEvent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].field != b[i].field || !reflect.DeepEqual(a[i].value.Interface(), b[i].value.Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalTopics checks that topics were read from size bytes, as the decoder accepts lengths that are not
+// encoded in the shortest form
+func canonicalTopics(topics []types.Hash, size int) bool {
+	bz, err := types.EncodeToBytes(topics)
+	return err == nil && len(bz) == size
+}
+
+// skipEvent reads past the arguments of an event. An undecodableEventError is returned if an argument type has no
+// type definition.
+func (d *eventDecoder) skipEvent(decoder *scale.Decoder, id types.EventID) error {
+	args, ok := d.args[id]
+	if !ok {
+		names, err := eventArgs(d.meta, id)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			arg, err := parseTypeDef(string(name))
+			if err != nil {
+				return &undecodableEventError{err: err}
+			}
+			args = append(args, arg)
+		}
+		d.args[id] = args
+	}
+
+	for _, arg := range args {
+		err := arg.skip(decoder)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eventArgs returns the argument types of an event
+func eventArgs(meta *types.Metadata, id types.EventID) ([]types.Type, error) {
+	var events [][]types.EventMetadataV4
+	switch {
+	case meta.IsMetadataV12:
+		for _, mod := range meta.AsMetadataV12.Modules {
+			if mod.HasEvents && mod.Index == id[0] {
+				events = append(events, mod.Events)
+			}
+		}
+	case meta.IsMetadataV11:
+		for _, mod := range meta.AsMetadataV11.Modules {
+			if mod.HasEvents {
+				events = append(events, mod.Events)
+			}
+		}
+		events = eventsAt(events, id[0])
+	case meta.IsMetadataV10:
+		for _, mod := range meta.AsMetadataV10.Modules {
+			if mod.HasEvents {
+				events = append(events, mod.Events)
+			}
+		}
+		events = eventsAt(events, id[0])
+	default:
+		return nil, fmt.Errorf("unsupported metadata version %d", meta.Version)
+	}
+
+	if len(events) == 0 || int(id[1]) >= len(events[0]) {
+		return nil, fmt.Errorf("event %v not found", id)
+	}
+	return events[0][id[1]].Args, nil
+}
+
+// eventsAt selects the events of a module by position, as modules are indexed before V12
+func eventsAt(events [][]types.EventMetadataV4, index uint8) [][]types.EventMetadataV4 {
+	if int(index) >= len(events) {
+		return nil
+	}
+	return events[index : index+1]
+}
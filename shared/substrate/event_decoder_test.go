@@ -0,0 +1,158 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	events "github.com/ChainSafe/chainbridge-substrate-events"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+type testEvents struct {
+	ChainBridge_FungibleTransfer []events.EventFungibleTransfer //nolint:stylecheck,golint
+	System_CodeUpdated           []types.EventSystemCodeUpdated //nolint:stylecheck,golint
+}
+
+func testEventMetadata() *types.Metadata {
+	event := func(name string, args ...types.Type) types.EventMetadataV4 {
+		return types.EventMetadataV4{Name: types.Text(name), Args: args}
+	}
+	meta := types.NewMetadataV12()
+	meta.AsMetadataV12.Modules = []types.ModuleMetadataV12{
+		{Name: "System", Index: 0, HasEvents: true, Events: []types.EventMetadataV4{
+			event("ExtrinsicSuccess", "DispatchInfo"),
+			event("ExtrinsicFailed", "DispatchError", "DispatchInfo"),
+			event("CodeUpdated"),
+		}},
+		{Name: "Balances", Index: 5, HasEvents: true, Events: []types.EventMetadataV4{
+			event("Endowed", "AccountId", "Balance"),
+			event("Transfer", "AccountId", "AccountId", "Balance"),
+		}},
+		{Name: "ChainBridge", Index: 8, HasEvents: true, Events: []types.EventMetadataV4{
+			event("RelayerAdded", "AccountId"),
+			event("FungibleTransfer", "ChainId", "DepositNonce", "ResourceId", "U256", "Vec<u8>"),
+		}},
+		// A pallet added by a runtime upgrade
+		{Name: "Upgrade", Index: 20, HasEvents: true, Events: []types.EventMetadataV4{
+			event("Stored", "Vec<(T::AccountId, Option<Compact<BalanceOf<T>>>)>"),
+			event("Opaque", "SomethingNew"),
+		}},
+	}
+	return meta
+}
+
+func eventRecords(t *testing.T, records ...[]interface{}) types.EventRecordsRaw {
+	values := []interface{}{types.NewUCompactFromUInt(uint64(len(records)))}
+	for _, r := range records {
+		values = append(values, r...)
+		values = append(values, []types.Hash{})
+	}
+	return encode(t, values...)
+}
+
+func TestDecodeEvents(t *testing.T) {
+	phase := types.Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: 1}
+	account := types.NewAccountID(make([]byte, 32))
+	balance := types.NewU128(*big.NewInt(100))
+	resource := types.NewBytes32([32]byte{1})
+
+	raw := eventRecords(t,
+		[]interface{}{phase, types.EventID{5, 1}, account, account, balance},
+		[]interface{}{phase, types.EventID{20, 0}, types.NewUCompactFromUInt(2),
+			account, types.U8(1), types.NewUCompactFromUInt(1 << 20), account, types.U8(0)},
+		[]interface{}{phase, types.EventID{8, 1}, types.U8(2), types.U64(7), resource, types.NewU256(*big.NewInt(10)), types.NewBytes([]byte{0xab})},
+		[]interface{}{types.Phase{IsFinalization: true}, types.EventID{0, 2}},
+		[]interface{}{phase, types.EventID{0, 0}, types.U64(10), types.U8(0), types.U8(0)},
+	)
+
+	e := testEvents{}
+	err := DecodeEvents(testEventMetadata(), raw, &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(e.ChainBridge_FungibleTransfer) != 1 {
+		t.Fatalf("expected 1 FungibleTransfer, got %d", len(e.ChainBridge_FungibleTransfer))
+	}
+	transfer := e.ChainBridge_FungibleTransfer[0]
+	if transfer.Phase != phase || transfer.Destination != 2 || transfer.DepositNonce != 7 || transfer.ResourceId != resource ||
+		transfer.Amount.Int64() != 10 || len(transfer.Recipient) != 1 || transfer.Recipient[0] != 0xab {
+		t.Fatalf("unexpected transfer %+v", transfer)
+	}
+	if len(e.System_CodeUpdated) != 1 || !e.System_CodeUpdated[0].Phase.IsFinalization {
+		t.Fatalf("unexpected CodeUpdated events %+v", e.System_CodeUpdated)
+	}
+}
+
+func TestDecodeEventsUndecodable(t *testing.T) {
+	phase := types.Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: 1}
+	resource := types.NewBytes32([32]byte{1})
+	transfer := func(nonce uint64) []interface{} {
+		return []interface{}{phase, types.EventID{8, 1}, types.U8(2), types.U64(nonce), resource, types.NewU256(*big.NewInt(10)), types.NewBytes([]byte{0xab})}
+	}
+	// SomethingNew has no type definition, its encoding is unknown to the decoder
+	opaque := []interface{}{phase, types.EventID{20, 1}, types.U32(0), types.U8(1), types.NewBytes32([32]byte{2})}
+
+	raw := eventRecords(t,
+		transfer(1),
+		opaque,
+		transfer(2),
+		opaque,
+		[]interface{}{phase, types.EventID{0, 0}, types.U64(10), types.U8(0), types.U8(0)},
+		transfer(3),
+		opaque,
+	)
+	e := testEvents{}
+	err := DecodeEvents(testEventMetadata(), raw, &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.ChainBridge_FungibleTransfer) != 3 {
+		t.Fatalf("expected 3 FungibleTransfers, got %d", len(e.ChainBridge_FungibleTransfer))
+	}
+	for i, transfer := range e.ChainBridge_FungibleTransfer {
+		if transfer.DepositNonce != types.U64(i+1) || transfer.ResourceId != resource || transfer.Amount.Int64() != 10 {
+			t.Fatalf("unexpected transfer %+v", transfer)
+		}
+	}
+
+	// The events following an undecodable event must decode up to the end of the block
+	raw = eventRecords(t, opaque, []interface{}{phase, types.EventID{5, 0}, types.U8(1)})
+	err = DecodeEvents(testEventMetadata(), raw, &testEvents{})
+	if err == nil {
+		t.Fatal("expected error for undecodable event followed by a truncated event")
+	}
+}
+
+func TestDecodeEventsInvalid(t *testing.T) {
+	phase := types.Phase{IsApplyExtrinsic: true}
+
+	// Events not in the metadata cannot be skipped
+	raw := eventRecords(t, []interface{}{phase, types.EventID{30, 0}})
+	err := DecodeEvents(testEventMetadata(), raw, &testEvents{})
+	if err == nil {
+		t.Fatal("expected error for unknown event")
+	}
+
+	// Truncated records are reported
+	raw = eventRecords(t, []interface{}{phase, types.EventID{5, 0}, types.U8(1)})
+	err = DecodeEvents(testEventMetadata(), raw, &testEvents{})
+	if err == nil {
+		t.Fatal("expected error for truncated event")
+	}
+
+	// Records must end with the block
+	raw = append(eventRecords(t, []interface{}{phase, types.EventID{0, 2}}), 0)
+	err = DecodeEvents(testEventMetadata(), raw, &testEvents{})
+	if err == nil {
+		t.Fatal("expected error for trailing bytes")
+	}
+
+	err = DecodeEvents(testEventMetadata(), eventRecords(t), testEvents{})
+	if err == nil {
+		t.Fatal("expected error for non-pointer target")
+	}
+}
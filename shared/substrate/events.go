@@ -42,6 +42,12 @@ type EventNFTDeposited struct {
 	Topics []types.Hash
 }
 
+// EventUtilityItemCompleted is emitted when a single call of a batch completed without error
+type EventUtilityItemCompleted struct {
+	Phase  types.Phase
+	Topics []types.Hash
+}
+
 // EventFeeChanged is emitted when a fee for a given key is changed.
 type EventFeeChanged struct {
 	Phase    types.Phase
@@ -199,4 +205,5 @@ type Events struct {
 	Registry_Mint                    []EventRegistryMint                   //nolint:stylecheck,golint
 	Registry_RegistryCreated         []EventRegistryRegistryCreated        //nolint:stylecheck,golint
 	Registry_RegistryTmp             []EventRegistryTmp                    //nolint:stylecheck,golint
+	Utility_ItemCompleted            []EventUtilityItemCompleted           //nolint:stylecheck,golint
 }
@@ -0,0 +1,113 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v3"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+const (
+	MetadataV13 = 13
+	MetadataV14 = 14
+)
+
+// FetchMetadata retrieves the latest metadata from the node. See DecodeMetadata for the supported versions.
+func FetchMetadata(api *gsrpc.SubstrateAPI) (*types.Metadata, error) {
+	var res string
+	err := api.Client.Call(&res, "state_getMetadata")
+	if err != nil {
+		return nil, err
+	}
+	bz, err := types.HexDecodeString(res)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeMetadata(bz)
+}
+
+// DecodeMetadata decodes SCALE encoded metadata, selecting the decoder from the version in the prefix.
+// Versions up to V12 are decoded by GSRPC. V13 and V14 are converted to the V12 layout so that call indices,
+// storage keys, constants and event names can be resolved by the GSRPC helpers. Storage entries with more
+// than two keys cannot be represented in that layout and are omitted.
+func DecodeMetadata(bz []byte) (*types.Metadata, error) {
+	decoder := scale.NewDecoder(bytes.NewReader(bz))
+
+	var magic uint32
+	err := decoder.Decode(&magic)
+	if err != nil {
+		return nil, err
+	}
+	if magic != types.MagicNumber {
+		return nil, fmt.Errorf("magic number mismatch: expected %#x, found %#x", types.MagicNumber, magic)
+	}
+	version, err := decoder.ReadOneByte()
+	if err != nil {
+		return nil, err
+	}
+
+	var res types.MetadataV12
+	switch version {
+	case MetadataV13:
+		res, err = decodeMetadataV13(decoder)
+	case MetadataV14:
+		res, err = decodeMetadataV14(decoder)
+	default:
+		var meta types.Metadata
+		err = types.DecodeFromBytes(bz, &meta)
+		if err != nil {
+			return nil, err
+		}
+		return &meta, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode metadata v%d: %w", version, err)
+	}
+
+	return &types.Metadata{
+		MagicNumber:   magic,
+		Version:       12,
+		IsMetadataV12: true,
+		AsMetadataV12: res,
+	}, nil
+}
+
+// GetConst decodes the value of the constant prefix.name into res
+// TODO: Add to GSRPC
+func GetConst(meta *types.Metadata, prefix, name string, res interface{}) error {
+	var constants []types.ModuleConstantMetadataV6
+	switch {
+	case meta.IsMetadataV12:
+		for _, mod := range meta.AsMetadataV12.Modules {
+			if string(mod.Name) == prefix {
+				constants = mod.Constants
+			}
+		}
+	case meta.IsMetadataV11:
+		for _, mod := range meta.AsMetadataV11.Modules {
+			if string(mod.Name) == prefix {
+				constants = mod.Constants
+			}
+		}
+	case meta.IsMetadataV10:
+		for _, mod := range meta.AsMetadataV10.Modules {
+			if string(mod.Name) == prefix {
+				constants = mod.Constants
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported metadata version %d", meta.Version)
+	}
+
+	for _, cons := range constants {
+		if string(cons.Name) == name {
+			return types.DecodeFromBytes(cons.Value, res)
+		}
+	}
+	return fmt.Errorf("could not find constant %s.%s", prefix, name)
+}
@@ -0,0 +1,268 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/chainbridge-utils/msg"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/client"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// metadata_v12.hex is the Polkadot metadata shipped with GSRPC. metadata_v13.hex and metadata_v14.hex are synthetic,
+// they contain a System and a ChainBridge pallet encoded in the layout of the respective frame-metadata version and
+// cover the edge cases of the converters. Metadata recorded from live nodes is kept in testdata/recorded, see
+// TestRecordMetadata.
+func loadMetadata(t *testing.T, version string) *types.Metadata {
+	return loadMetadataFile(t, "testdata/metadata_"+version+".hex")
+}
+
+func loadMetadataFile(t *testing.T, path string) *types.Metadata {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bz, err := types.HexDecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := DecodeMetadata(bz)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %s", path, err)
+	}
+	return meta
+}
+
+// Storage key prefix of System.Account, twox128("System") ++ twox128("Account")
+const systemAccountPrefix = "0x26aa394eea5630e07c48ae0c9558cef7b99d880ec681799c0cf30e8886371da9"
+
+func TestDecodeMetadata(t *testing.T) {
+	for _, version := range []string{"v12", "v13", "v14"} {
+		meta := loadMetadata(t, version)
+		if !meta.IsMetadataV12 {
+			t.Fatalf("%s: expected metadata in the V12 layout", version)
+		}
+
+		var blockHashCount types.U32
+		err := GetConst(meta, "System", "BlockHashCount", &blockHashCount)
+		if err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+		if blockHashCount != 2400 {
+			t.Fatalf("%s: expected BlockHashCount 2400, got %d", version, blockHashCount)
+		}
+
+		account := bytes.Repeat([]byte{1}, 32)
+		key, err := types.CreateStorageKey(meta, "System", "Account", account, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+		if !strings.HasPrefix(key.Hex(), systemAccountPrefix) || !bytes.HasSuffix(key, account) {
+			t.Fatalf("%s: unexpected System.Account key %s", version, key.Hex())
+		}
+	}
+}
+
+func TestMetadataCalls(t *testing.T) {
+	tests := []struct {
+		version string
+		call    string
+		index   types.CallIndex
+	}{
+		{"v12", "Balances.transfer", types.CallIndex{SectionIndex: 6, MethodIndex: 0}},
+		{"v13", "System.remark", types.CallIndex{SectionIndex: 0, MethodIndex: 0}},
+		{"v13", string(SetThresholdMethod), types.CallIndex{SectionIndex: 8, MethodIndex: 0}},
+		{"v13", BridgePalletName + ".acknowledge_proposal", types.CallIndex{SectionIndex: 8, MethodIndex: 1}},
+		// V14 call indices are explicit and may have gaps
+		{"v14", "System.remark", types.CallIndex{SectionIndex: 0, MethodIndex: 1}},
+		{"v14", string(SetThresholdMethod), types.CallIndex{SectionIndex: 8, MethodIndex: 0}},
+		{"v14", BridgePalletName + ".acknowledge_proposal", types.CallIndex{SectionIndex: 8, MethodIndex: 5}},
+	}
+
+	for _, tt := range tests {
+		meta := loadMetadata(t, tt.version)
+		index, err := meta.FindCallIndex(tt.call)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.version, err)
+		}
+		if index != tt.index {
+			t.Errorf("%s: expected %s to have index %v, got %v", tt.version, tt.call, tt.index, index)
+		}
+	}
+}
+
+func TestMetadataBridgePallet(t *testing.T) {
+	for _, version := range []string{"v13", "v14"} {
+		meta := loadMetadata(t, version)
+
+		var chainId msg.ChainId
+		err := GetConst(meta, BridgePalletName, "ChainIdentity", &chainId)
+		if err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+		if chainId != 1 {
+			t.Fatalf("%s: expected chain id 1, got %d", version, chainId)
+		}
+
+		_, err = types.CreateStorageKey(meta, BridgeStoragePrefix, "Resources", make([]byte, 32), nil)
+		if err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+
+		entry, err := meta.FindStorageEntryMetadata(BridgeStoragePrefix, "Votes")
+		if err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+		if !entry.IsDoubleMap() {
+			t.Fatalf("%s: expected Votes to be a double map", version)
+		}
+		_, err = types.CreateStorageKey(meta, BridgeStoragePrefix, "Votes", []byte{1}, []byte{2})
+		if err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+
+		// Maps with more than two keys cannot be represented
+		_, err = meta.FindStorageEntryMetadata(BridgeStoragePrefix, "Triple")
+		if err == nil {
+			t.Fatalf("%s: expected Triple to be omitted", version)
+		}
+
+		mod, evt, err := meta.FindEventNamesForEventID(types.EventID{0, 1})
+		if err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+		if mod != "System" || evt != "ExtrinsicFailed" {
+			t.Fatalf("%s: expected System.ExtrinsicFailed, got %s.%s", version, mod, evt)
+		}
+
+		for _, m := range meta.AsMetadataV12.Modules {
+			if m.Name == BridgePalletName && (len(m.Errors) != 2 || m.Errors[1].Name != "InvalidChainId") {
+				t.Fatalf("%s: unexpected errors %v", version, m.Errors)
+			}
+		}
+	}
+}
+
+// TestRecordMetadata writes the metadata of live nodes to testdata/recorded. RECORD_METADATA is a comma separated
+// list of name=url entries, a block hash can be appended as name=url@hash to record the metadata of an older runtime,
+// eg. a V13 runtime of Karura. The file is named after the entry and the metadata version, eg. karura_v14.hex.
+func TestRecordMetadata(t *testing.T) {
+	entries := os.Getenv("RECORD_METADATA")
+	if entries == "" {
+		t.Skip("RECORD_METADATA is not set")
+	}
+	for _, entry := range strings.Split(entries, ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			t.Fatalf("invalid entry %q, expected name=url", entry)
+		}
+		name, url := parts[0], parts[1]
+		var args []interface{}
+		if i := strings.LastIndex(url, "@"); i > 0 {
+			url, args = url[:i], []interface{}{url[i+1:]}
+		}
+
+		cl, err := client.Connect(url)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		var res string
+		err = cl.Call(&res, "state_getMetadata", args...)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		bz, err := types.HexDecodeString(res)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		_, err = DecodeMetadata(bz)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		err = os.MkdirAll(filepath.Join("testdata", "recorded"), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join("testdata", "recorded", fmt.Sprintf("%s_v%d.hex", name, bz[4]))
+		err = ioutil.WriteFile(path, []byte(res+"\n"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("Recorded %s", path)
+	}
+}
+
+// TestRecordedMetadata checks the converters on the type tables of real runtimes
+func TestRecordedMetadata(t *testing.T) {
+	paths, err := filepath.Glob("testdata/recorded/*.hex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Skip("no recorded metadata, see TestRecordMetadata")
+	}
+
+	for _, path := range paths {
+		meta := loadMetadataFile(t, path)
+
+		var blockHashCount types.U32
+		err := GetConst(meta, "System", "BlockHashCount", &blockHashCount)
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if blockHashCount == 0 {
+			t.Fatalf("%s: expected BlockHashCount to be set", path)
+		}
+
+		account := bytes.Repeat([]byte{1}, 32)
+		key, err := types.CreateStorageKey(meta, "System", "Account", account, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if !strings.HasPrefix(key.Hex(), systemAccountPrefix) {
+			t.Fatalf("%s: unexpected System.Account key %s", path, key.Hex())
+		}
+
+		_, err = meta.FindCallIndex("System.remark")
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		mod, evt, err := meta.FindEventNamesForEventID(types.EventID{0, 0})
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if mod != "System" || evt != "ExtrinsicSuccess" {
+			t.Fatalf("%s: expected System.ExtrinsicSuccess, got %s.%s", path, mod, evt)
+		}
+
+		if !meta.ExistsModuleMetadata(BridgePalletName) {
+			continue
+		}
+		for _, call := range []string{string(SetThresholdMethod), BridgePalletName + ".acknowledge_proposal"} {
+			_, err = meta.FindCallIndex(call)
+			if err != nil {
+				t.Fatalf("%s: %s", path, err)
+			}
+		}
+		_, err = types.CreateStorageKey(meta, BridgeStoragePrefix, "Votes", []byte{1}, []byte{2})
+		if err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+	}
+}
+
+func TestDecodeMetadataUnsupportedVersion(t *testing.T) {
+	_, err := DecodeMetadata([]byte{0x6d, 0x65, 0x74, 0x61, 15})
+	if err == nil {
+		t.Fatal("expected error for unsupported metadata version")
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// decodeMetadataV13 decodes V13 metadata into the V12 layout. V13 only differs from V12 by the NMap storage entry type.
+func decodeMetadataV13(decoder *scale.Decoder) (types.MetadataV12, error) {
+	var modules []moduleMetadataV13
+	err := decoder.Decode(&modules)
+	if err != nil {
+		return types.MetadataV12{}, err
+	}
+	var extrinsic types.ExtrinsicV11
+	err = decoder.Decode(&extrinsic)
+	if err != nil {
+		return types.MetadataV12{}, err
+	}
+
+	res := types.MetadataV12{Modules: make([]types.ModuleMetadataV12, len(modules)), Extrinsic: extrinsic}
+	for i, mod := range modules {
+		res.Modules[i] = mod.ModuleMetadataV12
+	}
+	return res, nil
+}
+
+type moduleMetadataV13 struct {
+	types.ModuleMetadataV12
+}
+
+func (m *moduleMetadataV13) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&m.Name)
+	if err != nil {
+		return err
+	}
+
+	var storage storageMetadataV13
+	err = decoder.DecodeOption(&m.HasStorage, &storage)
+	if err != nil {
+		return err
+	}
+	m.Storage = storage.StorageMetadataV10
+
+	err = decoder.DecodeOption(&m.HasCalls, &m.Calls)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.DecodeOption(&m.HasEvents, &m.Events)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&m.Constants)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&m.Errors)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(&m.Index)
+}
+
+type storageMetadataV13 struct {
+	types.StorageMetadataV10
+}
+
+func (s *storageMetadataV13) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&s.Prefix)
+	if err != nil {
+		return err
+	}
+
+	var entries []storageEntryV13
+	err = decoder.Decode(&entries)
+	if err != nil {
+		return err
+	}
+
+	s.Items = make([]types.StorageFunctionMetadataV10, 0, len(entries))
+	for _, entry := range entries {
+		if entry.supported {
+			s.Items = append(s.Items, entry.StorageFunctionMetadataV10)
+		}
+	}
+	return nil
+}
+
+// storageEntryV13 is a storage entry that may use the NMap type. NMaps with one or two keys are converted
+// to maps and double maps, larger ones are not supported.
+type storageEntryV13 struct {
+	types.StorageFunctionMetadataV10
+	supported bool
+}
+
+func (s *storageEntryV13) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&s.Name)
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&s.Modifier)
+	if err != nil {
+		return err
+	}
+
+	t, err := decoder.ReadOneByte()
+	if err != nil {
+		return err
+	}
+
+	s.supported = true
+	switch t {
+	case 0:
+		s.Type.IsType = true
+		err = decoder.Decode(&s.Type.AsType)
+	case 1:
+		s.Type.IsMap = true
+		err = decoder.Decode(&s.Type.AsMap)
+	case 2:
+		s.Type.IsDoubleMap = true
+		err = decoder.Decode(&s.Type.AsDoubleMap)
+	case 3:
+		err = s.decodeNMap(decoder)
+	default:
+		return fmt.Errorf("received unexpected storage function type %v", t)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&s.Fallback)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(&s.Documentation)
+}
+
+func (s *storageEntryV13) decodeNMap(decoder scale.Decoder) error {
+	var keys []types.Type
+	err := decoder.Decode(&keys)
+	if err != nil {
+		return err
+	}
+	var hashers []types.StorageHasherV10
+	err = decoder.Decode(&hashers)
+	if err != nil {
+		return err
+	}
+	var value types.Type
+	err = decoder.Decode(&value)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(keys) == 1 && len(hashers) == 1:
+		s.Type.IsMap = true
+		s.Type.AsMap = types.MapTypeV10{Hasher: hashers[0], Key: keys[0], Value: value}
+	case len(keys) == 2 && len(hashers) == 2:
+		s.Type.IsDoubleMap = true
+		s.Type.AsDoubleMap = types.DoubleMapTypeV10{Hasher: hashers[0], Key1: keys[0], Key2: keys[1], Value: value, Key2Hasher: hashers[1]}
+	default:
+		s.supported = false
+	}
+	return nil
+}
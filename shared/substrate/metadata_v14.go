@@ -0,0 +1,613 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// V14 metadata describes all types in a portable registry and refers to them by id. Only the parts required
+// to build the V12 layout are decoded, the trailing runtime type is ignored.
+
+const (
+	typeDefComposite = iota
+	typeDefVariant
+	typeDefSequence
+	typeDefArray
+	typeDefTuple
+	typeDefPrimitive
+	typeDefCompact
+	typeDefBitSequence
+)
+
+var primitiveNames = []string{"bool", "char", "str", "u8", "u16", "u32", "u64", "u128", "u256", "i8", "i16", "i32", "i64", "i128", "i256"}
+
+type typeRegistry map[uint32]*portableType
+
+type portableType struct {
+	ID     uint32
+	Path   []types.Text
+	Params []typeParameter
+	Def    typeDef
+	Docs   []types.Text
+}
+
+func (p *portableType) Decode(decoder scale.Decoder) error {
+	var err error
+	p.ID, err = decodeTypeID(decoder)
+	if err != nil {
+		return err
+	}
+	err = decoder.Decode(&p.Path)
+	if err != nil {
+		return err
+	}
+	err = decoder.Decode(&p.Params)
+	if err != nil {
+		return err
+	}
+	err = decoder.Decode(&p.Def)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(&p.Docs)
+}
+
+type typeParameter struct {
+	Name    types.Text
+	HasType bool
+	Type    uint32
+}
+
+func (p *typeParameter) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&p.Name)
+	if err != nil {
+		return err
+	}
+	p.HasType, p.Type, err = decodeOptionalTypeID(decoder)
+	return err
+}
+
+type typeDef struct {
+	Kind      uint8
+	Fields    []field   // Composite
+	Variants  []variant // Variant
+	Type      uint32    // Sequence, Array and Compact element type
+	Len       uint32    // Array length
+	Tuple     []uint32  // Tuple element types
+	Primitive uint8     // Primitive kind
+}
+
+func (t *typeDef) Decode(decoder scale.Decoder) error {
+	var err error
+	t.Kind, err = decoder.ReadOneByte()
+	if err != nil {
+		return err
+	}
+
+	switch t.Kind {
+	case typeDefComposite:
+		return decoder.Decode(&t.Fields)
+	case typeDefVariant:
+		return decoder.Decode(&t.Variants)
+	case typeDefSequence, typeDefCompact:
+		t.Type, err = decodeTypeID(decoder)
+		return err
+	case typeDefArray:
+		err = decoder.Decode(&t.Len)
+		if err != nil {
+			return err
+		}
+		t.Type, err = decodeTypeID(decoder)
+		return err
+	case typeDefTuple:
+		t.Tuple, err = decodeTypeIDs(decoder)
+		return err
+	case typeDefPrimitive:
+		t.Primitive, err = decoder.ReadOneByte()
+		return err
+	case typeDefBitSequence:
+		// Bit store and bit order types
+		_, err = decodeTypeID(decoder)
+		if err != nil {
+			return err
+		}
+		_, err = decodeTypeID(decoder)
+		return err
+	default:
+		return fmt.Errorf("unknown type definition %d", t.Kind)
+	}
+}
+
+type field struct {
+	Name        types.Text
+	Type        uint32
+	HasTypeName bool
+	TypeName    types.Text
+	Docs        []types.Text
+}
+
+func (f *field) Decode(decoder scale.Decoder) error {
+	var hasName bool
+	err := decoder.DecodeOption(&hasName, &f.Name)
+	if err != nil {
+		return err
+	}
+	f.Type, err = decodeTypeID(decoder)
+	if err != nil {
+		return err
+	}
+	err = decoder.DecodeOption(&f.HasTypeName, &f.TypeName)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(&f.Docs)
+}
+
+type variant struct {
+	Name   types.Text
+	Fields []field
+	Index  uint8
+	Docs   []types.Text
+}
+
+type palletMetadataV14 struct {
+	Name       types.Text
+	HasStorage bool
+	Storage    palletStorageV14
+	HasCalls   bool
+	Calls      uint32
+	HasEvents  bool
+	Events     uint32
+	Constants  []palletConstantV14
+	HasErrors  bool
+	Errors     uint32
+	Index      uint8
+}
+
+func (p *palletMetadataV14) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&p.Name)
+	if err != nil {
+		return err
+	}
+	err = decoder.DecodeOption(&p.HasStorage, &p.Storage)
+	if err != nil {
+		return err
+	}
+	p.HasCalls, p.Calls, err = decodeOptionalTypeID(decoder)
+	if err != nil {
+		return err
+	}
+	p.HasEvents, p.Events, err = decodeOptionalTypeID(decoder)
+	if err != nil {
+		return err
+	}
+	err = decoder.Decode(&p.Constants)
+	if err != nil {
+		return err
+	}
+	p.HasErrors, p.Errors, err = decodeOptionalTypeID(decoder)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(&p.Index)
+}
+
+type palletStorageV14 struct {
+	Prefix  types.Text
+	Entries []storageEntryV14
+}
+
+type storageEntryV14 struct {
+	Name     types.Text
+	Modifier types.StorageFunctionModifierV0
+	IsMap    bool
+	Hashers  []types.StorageHasherV10
+	Key      uint32
+	Value    uint32
+	Default  types.Bytes
+	Docs     []types.Text
+}
+
+func (s *storageEntryV14) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&s.Name)
+	if err != nil {
+		return err
+	}
+	err = decoder.Decode(&s.Modifier)
+	if err != nil {
+		return err
+	}
+
+	t, err := decoder.ReadOneByte()
+	if err != nil {
+		return err
+	}
+	switch t {
+	case 0:
+		s.Value, err = decodeTypeID(decoder)
+	case 1:
+		s.IsMap = true
+		err = decoder.Decode(&s.Hashers)
+		if err != nil {
+			return err
+		}
+		s.Key, err = decodeTypeID(decoder)
+		if err != nil {
+			return err
+		}
+		s.Value, err = decodeTypeID(decoder)
+	default:
+		return fmt.Errorf("received unexpected storage entry type %v", t)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(&s.Default)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(&s.Docs)
+}
+
+type palletConstantV14 struct {
+	Name  types.Text
+	Type  uint32
+	Value types.Bytes
+	Docs  []types.Text
+}
+
+func (c *palletConstantV14) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&c.Name)
+	if err != nil {
+		return err
+	}
+	c.Type, err = decodeTypeID(decoder)
+	if err != nil {
+		return err
+	}
+	err = decoder.Decode(&c.Value)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(&c.Docs)
+}
+
+type extrinsicV14 struct {
+	Type             uint32
+	Version          uint8
+	SignedExtensions []signedExtensionV14
+}
+
+func (e *extrinsicV14) Decode(decoder scale.Decoder) error {
+	var err error
+	e.Type, err = decodeTypeID(decoder)
+	if err != nil {
+		return err
+	}
+	err = decoder.Decode(&e.Version)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(&e.SignedExtensions)
+}
+
+type signedExtensionV14 struct {
+	Identifier       types.Text
+	Type             uint32
+	AdditionalSigned uint32
+}
+
+func (s *signedExtensionV14) Decode(decoder scale.Decoder) error {
+	err := decoder.Decode(&s.Identifier)
+	if err != nil {
+		return err
+	}
+	s.Type, err = decodeTypeID(decoder)
+	if err != nil {
+		return err
+	}
+	s.AdditionalSigned, err = decodeTypeID(decoder)
+	return err
+}
+
+// decodeTypeID decodes a compact encoded reference into the type registry
+func decodeTypeID(decoder scale.Decoder) (uint32, error) {
+	id, err := decoder.DecodeUintCompact()
+	if err != nil {
+		return 0, err
+	}
+	return uint32(id.Uint64()), nil
+}
+
+func decodeOptionalTypeID(decoder scale.Decoder) (bool, uint32, error) {
+	b, err := decoder.ReadOneByte()
+	if err != nil {
+		return false, 0, err
+	}
+	switch b {
+	case 0:
+		return false, 0, nil
+	case 1:
+		id, err := decodeTypeID(decoder)
+		return true, id, err
+	default:
+		return false, 0, fmt.Errorf("unknown option prefix %d", b)
+	}
+}
+
+func decodeTypeIDs(decoder scale.Decoder) ([]uint32, error) {
+	n, err := decoder.DecodeUintCompact()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint32, n.Uint64())
+	for i := range ids {
+		ids[i], err = decodeTypeID(decoder)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// decodeMetadataV14 decodes V14 metadata and converts it into the V12 layout
+func decodeMetadataV14(decoder *scale.Decoder) (types.MetadataV12, error) {
+	var lookup []portableType
+	err := decoder.Decode(&lookup)
+	if err != nil {
+		return types.MetadataV12{}, err
+	}
+	var pallets []palletMetadataV14
+	err = decoder.Decode(&pallets)
+	if err != nil {
+		return types.MetadataV12{}, err
+	}
+	var extrinsic extrinsicV14
+	err = decoder.Decode(&extrinsic)
+	if err != nil {
+		return types.MetadataV12{}, err
+	}
+
+	reg := make(typeRegistry, len(lookup))
+	for i := range lookup {
+		reg[lookup[i].ID] = &lookup[i]
+	}
+
+	res := types.MetadataV12{
+		Modules:   make([]types.ModuleMetadataV12, len(pallets)),
+		Extrinsic: types.ExtrinsicV11{Version: extrinsic.Version},
+	}
+	for _, ext := range extrinsic.SignedExtensions {
+		res.Extrinsic.SignedExtensions = append(res.Extrinsic.SignedExtensions, string(ext.Identifier))
+	}
+	for i, pallet := range pallets {
+		res.Modules[i], err = reg.convertPallet(pallet)
+		if err != nil {
+			return types.MetadataV12{}, fmt.Errorf("pallet %s: %w", pallet.Name, err)
+		}
+	}
+	return res, nil
+}
+
+func (r typeRegistry) convertPallet(p palletMetadataV14) (types.ModuleMetadataV12, error) {
+	mod := types.ModuleMetadataV12{
+		Name:       p.Name,
+		HasStorage: p.HasStorage,
+		HasCalls:   p.HasCalls,
+		HasEvents:  p.HasEvents,
+		Index:      p.Index,
+	}
+
+	if p.HasStorage {
+		mod.Storage.Prefix = p.Storage.Prefix
+		for _, entry := range p.Storage.Entries {
+			item, ok := r.convertStorageEntry(entry)
+			if ok {
+				mod.Storage.Items = append(mod.Storage.Items, item)
+			}
+		}
+	}
+
+	if p.HasCalls {
+		variants, err := r.variants(p.Calls)
+		if err != nil {
+			return mod, err
+		}
+		mod.Calls = make([]types.FunctionMetadataV4, len(variants))
+		for i, v := range variants {
+			mod.Calls[i] = types.FunctionMetadataV4{Name: v.Name, Documentation: v.Docs}
+			for _, f := range v.Fields {
+				mod.Calls[i].Args = append(mod.Calls[i].Args, types.FunctionArgumentMetadata{Name: f.Name, Type: r.fieldTypeName(f)})
+			}
+		}
+	}
+
+	if p.HasEvents {
+		variants, err := r.variants(p.Events)
+		if err != nil {
+			return mod, err
+		}
+		mod.Events = make([]types.EventMetadataV4, len(variants))
+		for i, v := range variants {
+			mod.Events[i] = types.EventMetadataV4{Name: v.Name, Documentation: v.Docs}
+			for _, f := range v.Fields {
+				mod.Events[i].Args = append(mod.Events[i].Args, types.Type(r.scaleDef(f.Type, 0)))
+			}
+		}
+	}
+
+	for _, c := range p.Constants {
+		mod.Constants = append(mod.Constants, types.ModuleConstantMetadataV6{
+			Name:          c.Name,
+			Type:          types.Type(r.typeName(c.Type)),
+			Value:         c.Value,
+			Documentation: c.Docs,
+		})
+	}
+
+	if p.HasErrors {
+		variants, err := r.variants(p.Errors)
+		if err != nil {
+			return mod, err
+		}
+		mod.Errors = make([]types.ErrorMetadataV8, len(variants))
+		for i, v := range variants {
+			mod.Errors[i] = types.ErrorMetadataV8{Name: v.Name, Documentation: v.Docs}
+		}
+	}
+
+	return mod, nil
+}
+
+// convertStorageEntry converts plain entries and maps with up to two keys. Larger maps are not supported.
+func (r typeRegistry) convertStorageEntry(s storageEntryV14) (types.StorageFunctionMetadataV10, bool) {
+	item := types.StorageFunctionMetadataV10{
+		Name:          s.Name,
+		Modifier:      s.Modifier,
+		Fallback:      s.Default,
+		Documentation: s.Docs,
+	}
+	value := types.Type(r.typeName(s.Value))
+
+	switch {
+	case !s.IsMap:
+		item.Type.IsType = true
+		item.Type.AsType = value
+	case len(s.Hashers) == 1:
+		item.Type.IsMap = true
+		item.Type.AsMap = types.MapTypeV10{Hasher: s.Hashers[0], Key: types.Type(r.typeName(s.Key)), Value: value}
+	case len(s.Hashers) == 2:
+		key, ok := r[s.Key]
+		if !ok || key.Def.Kind != typeDefTuple || len(key.Def.Tuple) != 2 {
+			return item, false
+		}
+		item.Type.IsDoubleMap = true
+		item.Type.AsDoubleMap = types.DoubleMapTypeV10{
+			Hasher:     s.Hashers[0],
+			Key1:       types.Type(r.typeName(key.Def.Tuple[0])),
+			Key2:       types.Type(r.typeName(key.Def.Tuple[1])),
+			Value:      value,
+			Key2Hasher: s.Hashers[1],
+		}
+	default:
+		return item, false
+	}
+	return item, true
+}
+
+// variants returns the variants of an enum type, positioned by their index. Unused indices are left empty.
+func (r typeRegistry) variants(id uint32) ([]variant, error) {
+	t, ok := r[id]
+	if !ok {
+		return nil, fmt.Errorf("type %d not found", id)
+	}
+	if t.Def.Kind != typeDefVariant {
+		return nil, fmt.Errorf("type %d is not an enum", id)
+	}
+
+	size := 0
+	for _, v := range t.Def.Variants {
+		if int(v.Index) >= size {
+			size = int(v.Index) + 1
+		}
+	}
+	res := make([]variant, size)
+	for _, v := range t.Def.Variants {
+		res[v.Index] = v
+	}
+	return res, nil
+}
+
+func (r typeRegistry) fieldTypeName(f field) types.Type {
+	if f.HasTypeName {
+		return types.Type(f.TypeName)
+	}
+	return types.Type(r.typeName(f.Type))
+}
+
+// typeName returns a readable name for a type, similar to the names used by earlier metadata versions
+func (r typeRegistry) typeName(id uint32) string {
+	t, ok := r[id]
+	if !ok {
+		return ""
+	}
+
+	switch t.Def.Kind {
+	case typeDefSequence:
+		return fmt.Sprintf("Vec<%s>", r.typeName(t.Def.Type))
+	case typeDefArray:
+		return fmt.Sprintf("[%s; %d]", r.typeName(t.Def.Type), t.Def.Len)
+	case typeDefCompact:
+		return fmt.Sprintf("Compact<%s>", r.typeName(t.Def.Type))
+	case typeDefTuple:
+		names := make([]string, len(t.Def.Tuple))
+		for i, elem := range t.Def.Tuple {
+			names[i] = r.typeName(elem)
+		}
+		return fmt.Sprintf("(%s)", strings.Join(names, ", "))
+	case typeDefPrimitive:
+		if int(t.Def.Primitive) < len(primitiveNames) {
+			return primitiveNames[t.Def.Primitive]
+		}
+		return ""
+	case typeDefBitSequence:
+		return "BitVec"
+	default:
+		if len(t.Path) == 0 {
+			return ""
+		}
+		return string(t.Path[len(t.Path)-1])
+	}
+}
+
+// scaleDef returns the structure of a type in the syntax of parseTypeDef. Event arguments use these definitions
+// instead of names so that events can be skipped without registering the types of the runtime.
+func (r typeRegistry) scaleDef(id uint32, depth int) string {
+	t, ok := r[id]
+	if !ok || depth > maxTypeDepth {
+		return "unresolved"
+	}
+
+	switch t.Def.Kind {
+	case typeDefComposite:
+		if len(t.Def.Fields) == 1 {
+			return r.scaleDef(t.Def.Fields[0].Type, depth+1)
+		}
+		defs := make([]string, len(t.Def.Fields))
+		for i, f := range t.Def.Fields {
+			defs[i] = r.scaleDef(f.Type, depth+1)
+		}
+		return fmt.Sprintf("(%s)", strings.Join(defs, ", "))
+	case typeDefVariant:
+		defs := make([]string, len(t.Def.Variants))
+		for i, v := range t.Def.Variants {
+			fields := make([]string, len(v.Fields))
+			for j, f := range v.Fields {
+				fields[j] = r.scaleDef(f.Type, depth+1)
+			}
+			defs[i] = fmt.Sprintf("%d:(%s)", v.Index, strings.Join(fields, ", "))
+		}
+		return fmt.Sprintf("enum{%s}", strings.Join(defs, ", "))
+	case typeDefSequence:
+		return fmt.Sprintf("Vec<%s>", r.scaleDef(t.Def.Type, depth+1))
+	case typeDefArray:
+		return fmt.Sprintf("[%s; %d]", r.scaleDef(t.Def.Type, depth+1), t.Def.Len)
+	case typeDefTuple:
+		defs := make([]string, len(t.Def.Tuple))
+		for i, elem := range t.Def.Tuple {
+			defs[i] = r.scaleDef(elem, depth+1)
+		}
+		return fmt.Sprintf("(%s)", strings.Join(defs, ", "))
+	case typeDefCompact:
+		return "Compact<u128>"
+	default:
+		return r.typeName(id)
+	}
+}
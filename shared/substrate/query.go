@@ -18,23 +18,9 @@ func QueryStorage(client *Client, prefix, method string, arg1, arg2 []byte, resu
 	return client.Api.RPC.State.GetStorageLatest(key, result)
 }
 
-// TODO: Add to GSRPC
-func getConst(meta *types.Metadata, prefix, name string, res interface{}) error {
-	for _, mod := range meta.AsMetadataV12.Modules {
-		if string(mod.Name) == prefix {
-			for _, cons := range mod.Constants {
-				if string(cons.Name) == name {
-					return types.DecodeFromBytes(cons.Value, res)
-				}
-			}
-		}
-	}
-	return fmt.Errorf("could not find constant %s.%s", prefix, name)
-}
-
 // QueryConst looks up a constant in the metadata
 func QueryConst(client *Client, prefix, name string, res interface{}) error {
-	err := getConst(client.Meta, prefix, name, res)
+	err := GetConst(client.Meta, prefix, name, res)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,107 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v3"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+// CallResult is the outcome of a call submitted with BatchSubmit
+type CallResult struct {
+	Block types.Hash // Block the extrinsic was included in, zero if it was not included
+	Err   error      // Reason the call was not included or failed to dispatch, nil on success
+}
+
+// resultEvents are the events used to determine the outcome of an extrinsic
+type resultEvents struct {
+	System_ExtrinsicSuccess []types.EventSystemExtrinsicSuccess //nolint:stylecheck,golint
+	System_ExtrinsicFailed  []types.EventSystemExtrinsicFailed  //nolint:stylecheck,golint
+	Sudo_Sudid              []types.EventSudoSudid              //nolint:stylecheck,golint
+}
+
+// ExtrinsicResult looks up the outcome of an extrinsic included in the block. An error is returned if the dispatch
+// failed, including calls wrapped in Sudo.sudo that failed while the sudo call itself succeeded.
+func ExtrinsicResult(api *gsrpc.SubstrateAPI, meta *types.Metadata, block types.Hash, ext types.Extrinsic) error {
+	signed, err := api.RPC.Chain.GetBlock(block)
+	if err != nil {
+		return fmt.Errorf("failed to fetch block %s: %w", block.Hex(), err)
+	}
+	target, err := types.EncodeToBytes(ext)
+	if err != nil {
+		return err
+	}
+	index := -1
+	for i, e := range signed.Block.Extrinsics {
+		enc, err := types.EncodeToBytes(e)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(enc, target) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("extrinsic not found in block %s", block.Hex())
+	}
+
+	key, err := types.CreateStorageKey(meta, "System", "Events", nil, nil)
+	if err != nil {
+		return err
+	}
+	var records types.EventRecordsRaw
+	_, err = api.RPC.State.GetStorage(key, &records, block)
+	if err != nil {
+		return fmt.Errorf("failed to fetch events of block %s: %w", block.Hex(), err)
+	}
+	evts := resultEvents{}
+	err = DecodeEvents(meta, records, &evts)
+	if err != nil {
+		return fmt.Errorf("failed to decode events of block %s: %w", block.Hex(), err)
+	}
+	return extrinsicResult(meta, &evts, uint32(index))
+}
+
+// extrinsicResult finds the result events of the extrinsic at index
+func extrinsicResult(meta *types.Metadata, evts *resultEvents, index uint32) error {
+	applied := func(p types.Phase) bool { return p.IsApplyExtrinsic && p.AsApplyExtrinsic == index }
+
+	for _, evt := range evts.System_ExtrinsicFailed {
+		if applied(evt.Phase) {
+			return fmt.Errorf("extrinsic failed: %s", DispatchErrorName(meta, evt.DispatchError))
+		}
+	}
+	for _, evt := range evts.Sudo_Sudid {
+		if applied(evt.Phase) && !evt.Result.Ok {
+			return fmt.Errorf("sudo call failed: %s", DispatchErrorName(meta, evt.Result.Error))
+		}
+	}
+	for _, evt := range evts.System_ExtrinsicSuccess {
+		if applied(evt.Phase) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no result event found for extrinsic %d", index)
+}
+
+// DispatchErrorName returns the module and error name of a dispatch error, as found in the metadata
+func DispatchErrorName(meta *types.Metadata, dispatchErr types.DispatchError) string {
+	if !dispatchErr.HasModule {
+		return fmt.Sprintf("%+v", dispatchErr)
+	}
+	for _, mod := range meta.AsMetadataV12.Modules {
+		if mod.Index != dispatchErr.Module {
+			continue
+		}
+		if int(dispatchErr.Error) < len(mod.Errors) {
+			return fmt.Sprintf("%s.%s", mod.Name, mod.Errors[dispatchErr.Error].Name)
+		}
+		return fmt.Sprintf("%s error %d", mod.Name, dispatchErr.Error)
+	}
+	return fmt.Sprintf("module %d error %d", dispatchErr.Module, dispatchErr.Error)
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func TestExtrinsicResult(t *testing.T) {
+	meta := loadMetadata(t, "v12")
+	phase := func(i uint32) types.Phase { return types.Phase{IsApplyExtrinsic: true, AsApplyExtrinsic: i} }
+	requireSudo := types.DispatchError{HasModule: true, Module: 19, Error: 0}
+
+	evts := &resultEvents{
+		System_ExtrinsicSuccess: []types.EventSystemExtrinsicSuccess{{Phase: phase(1)}, {Phase: phase(2)}},
+		System_ExtrinsicFailed:  []types.EventSystemExtrinsicFailed{{Phase: phase(3), DispatchError: requireSudo}},
+		Sudo_Sudid:              []types.EventSudoSudid{{Phase: phase(1), Result: types.DispatchResult{Ok: true}}, {Phase: phase(2), Result: types.DispatchResult{Error: requireSudo}}},
+	}
+
+	if err := extrinsicResult(meta, evts, 1); err != nil {
+		t.Fatalf("Expected extrinsic 1 to succeed, got %s", err)
+	}
+	for _, index := range []uint32{2, 3} {
+		err := extrinsicResult(meta, evts, index)
+		if err == nil || !strings.Contains(err.Error(), "Sudo.RequireSudo") {
+			t.Fatalf("Expected extrinsic %d to fail with Sudo.RequireSudo, got %v", index, err)
+		}
+	}
+	if err := extrinsicResult(meta, evts, 4); err == nil {
+		t.Fatal("Expected an error for an extrinsic without result events")
+	}
+}
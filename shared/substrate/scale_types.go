@@ -0,0 +1,487 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/scale"
+)
+
+// Type definitions describe the SCALE encoding of a type so that encoded values can be skipped without decoding
+// them. Definitions use Rust-like syntax:
+//
+//   u8 ... u256, i8 ... i256, bool, char, str   primitives
+//   Vec<T>, Option<T>, Compact<T>, Box<T>      generics
+//   [T; N]                                      arrays
+//   (A, B)                                      tuples and structs
+//   enum{0:(A), 1:(), 3:(B, C)}                 enums with the encoded index of each variant
+//
+// Other names are resolved with the registered type definitions. Generic arguments of registered names are ignored.
+
+var typeDefsLock sync.RWMutex
+var typeDefs = map[string]string{
+	"AccountId":           "[u8; 32]",
+	"AccountIdOf":         "[u8; 32]",
+	"AccountIndex":        "u32",
+	"Address":             "[u8; 32]",
+	"Balance":             "u128",
+	"BalanceOf":           "u128",
+	"BlockNumber":         "u32",
+	"Bytes":               "Vec<u8>",
+	"CallHash":            "[u8; 32]",
+	"ChainId":             "u8",
+	"DepositNonce":        "u64",
+	"DispatchClass":       "u8",
+	"DispatchError":       "enum{0:(), 1:(), 2:(), 3:(u8, u8), 4:(), 5:(), 6:(u8), 7:(u8)}",
+	"DispatchInfo":        "(u64, u8, u8)",
+	"DispatchResult":      "enum{0:(), 1:(DispatchError)}",
+	"EraIndex":            "u32",
+	"EvmAddress":          "[u8; 20]",
+	"H160":                "[u8; 20]",
+	"H256":                "[u8; 32]",
+	"Hash":                "[u8; 32]",
+	"Index":               "u32",
+	"LockIdentifier":      "[u8; 8]",
+	"MemberCount":         "u32",
+	"Moment":              "u64",
+	"Pays":                "u8",
+	"PropIndex":           "u32",
+	"ProposalIndex":       "u32",
+	"ReferendumIndex":     "u32",
+	"ResourceId":          "[u8; 32]",
+	"SessionIndex":        "u32",
+	"Status":              "u8",
+	"String":              "str",
+	"Text":                "str",
+	"Timepoint":           "(u32, u32)",
+	"U256":                "[u8; 32]",
+	"VoteThreshold":       "u8",
+	"Weight":              "u64",
+	"AuthorityId":         "[u8; 32]",
+	"AuthorityWeight":     "u64",
+	"AuthorityList":       "Vec<([u8; 32], u64)>",
+	"RefCount":            "u32",
+	"TaskAddress":         "(u32, u32)",
+	"AccountVote":         "enum{0:(u8, u128), 1:(u128, u128)}",
+	"ElectionCompute":     "u8",
+	"OpaqueTimeSlot":      "Vec<u8>",
+	"Kind":                "[u8; 16]",
+	"BalanceStatus":       "u8",
+	"BountyIndex":         "u32",
+	"CallIndex":           "(u8, u8)",
+	"ProxyType":           "u8",
+	"StorageKey":          "Vec<u8>",
+	"IdentificationTuple": "([u8; 32], (u128, u128, Vec<([u8; 32], u128)>))",
+}
+
+// RegisterTypeDef adds or replaces the definition of a type name, for example for chain specific types
+func RegisterTypeDef(name, def string) {
+	typeDefsLock.Lock()
+	defer typeDefsLock.Unlock()
+	typeDefs[name] = def
+}
+
+func lookupTypeDef(name string) (string, bool) {
+	typeDefsLock.RLock()
+	defer typeDefsLock.RUnlock()
+	def, ok := typeDefs[name]
+	return def, ok
+}
+
+type scaleKind int
+
+const (
+	kindFixed scaleKind = iota
+	kindVec
+	kindArray
+	kindTuple
+	kindCompact
+	kindEnum
+)
+
+// scaleType is a parsed type definition
+type scaleType struct {
+	kind     scaleKind
+	size     int                 // Size of fixed types
+	elem     *scaleType          // Element of vectors and arrays
+	len      int                 // Length of arrays
+	fields   []*scaleType        // Fields of tuples
+	variants map[byte]*scaleType // Variants of enums by index
+}
+
+// fixedSize returns the encoded size of the type if it does not depend on the value
+func (t *scaleType) fixedSize() (int, bool) {
+	switch t.kind {
+	case kindFixed:
+		return t.size, true
+	case kindArray:
+		size, ok := t.elem.fixedSize()
+		return size * t.len, ok
+	case kindTuple:
+		total := 0
+		for _, f := range t.fields {
+			size, ok := f.fixedSize()
+			if !ok {
+				return 0, false
+			}
+			total += size
+		}
+		return total, true
+	default:
+		return 0, false
+	}
+}
+
+// skip reads past an encoded value of the type
+func (t *scaleType) skip(decoder *scale.Decoder) error {
+	if size, ok := t.fixedSize(); ok {
+		return skipBytes(decoder, size)
+	}
+
+	switch t.kind {
+	case kindVec:
+		n, err := decoder.DecodeUintCompact()
+		if err != nil {
+			return err
+		}
+		if size, ok := t.elem.fixedSize(); ok {
+			return skipBytes(decoder, int(n.Int64())*size)
+		}
+		for i := int64(0); i < n.Int64(); i++ {
+			err = t.elem.skip(decoder)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case kindArray:
+		for i := 0; i < t.len; i++ {
+			err := t.elem.skip(decoder)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case kindTuple:
+		for _, f := range t.fields {
+			err := f.skip(decoder)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case kindCompact:
+		_, err := decoder.DecodeUintCompact()
+		return err
+	case kindEnum:
+		index, err := decoder.ReadOneByte()
+		if err != nil {
+			return err
+		}
+		v, ok := t.variants[index]
+		if !ok {
+			return fmt.Errorf("unknown enum variant %d", index)
+		}
+		return v.skip(decoder)
+	default:
+		return fmt.Errorf("unknown type kind %d", t.kind)
+	}
+}
+
+func skipBytes(decoder *scale.Decoder, n int) error {
+	if n == 0 {
+		return nil
+	}
+	buf := make([]byte, n)
+	err := decoder.Read(buf)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+var primitiveSizes = map[string]int{
+	"bool": 1, "u8": 1, "i8": 1, "u16": 2, "i16": 2, "u32": 4, "i32": 4, "char": 4,
+	"u64": 8, "i64": 8, "u128": 16, "i128": 16, "u256": 32, "i256": 32,
+}
+
+// Prefixes referring to associated types, e.g. T::AccountId or <T as frame_system::Config>::AccountId
+var associatedTypePrefix = regexp.MustCompile(`<[^<>]+ as [^<>]+>::|\bT::|\bI::`)
+
+// maxTypeDepth limits the nesting of definitions, which protects against recursive type names
+const maxTypeDepth = 32
+
+// parseTypeDef parses a type definition or type name
+func parseTypeDef(def string) (*scaleType, error) {
+	p := &typeParser{input: associatedTypePrefix.ReplaceAllString(def, "")}
+	t, err := p.parseType(0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse type %s: %w", def, err)
+	}
+	p.skipSpace()
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("cannot parse type %s: unexpected %q", def, p.input[p.pos:])
+	}
+	return t, nil
+}
+
+type typeParser struct {
+	input string
+	pos   int
+}
+
+func (p *typeParser) skipSpace() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *typeParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *typeParser) expect(c byte) error {
+	if p.peek() != c {
+		return fmt.Errorf("expected %q at position %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *typeParser) ident() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		if strings.HasPrefix(p.input[p.pos:], "::") {
+			p.pos += 2
+			continue
+		}
+		break
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *typeParser) parseType(depth int) (*scaleType, error) {
+	if depth > maxTypeDepth {
+		return nil, fmt.Errorf("type nested too deeply")
+	}
+
+	switch p.peek() {
+	case '(':
+		p.pos++
+		fields, err := p.parseList(')', depth)
+		if err != nil {
+			return nil, err
+		}
+		return &scaleType{kind: kindTuple, fields: fields}, nil
+	case '[':
+		p.pos++
+		elem, err := p.parseType(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(';')
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(p.ident())
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(']')
+		if err != nil {
+			return nil, err
+		}
+		return &scaleType{kind: kindArray, elem: elem, len: n}, nil
+	}
+
+	name := p.ident()
+	if name == "" {
+		return nil, fmt.Errorf("expected type at position %d", p.pos)
+	}
+	if name == "enum" {
+		return p.parseEnum(depth)
+	}
+
+	// Generic arguments are only parsed for the generic types known here
+	var rawArgs string
+	if p.peek() == '<' {
+		start := p.pos
+		err := p.skipGenericArgs()
+		if err != nil {
+			return nil, err
+		}
+		rawArgs = p.input[start:p.pos]
+	}
+	args := func(n int) ([]*scaleType, error) {
+		if rawArgs == "" {
+			return nil, fmt.Errorf("%s requires %d type arguments", name, n)
+		}
+		sub := &typeParser{input: rawArgs, pos: 1}
+		res, err := sub.parseList('>', depth)
+		if err != nil {
+			return nil, err
+		}
+		if len(res) < n {
+			return nil, fmt.Errorf("%s requires %d type arguments", name, n)
+		}
+		return res, nil
+	}
+
+	// Paths such as sp_runtime::DispatchError are resolved by their last segment
+	if i := strings.LastIndex(name, "::"); i >= 0 {
+		name = name[i+2:]
+	}
+
+	switch name {
+	case "Vec", "BTreeSet", "VecDeque", "BoundedVec", "WeakBoundedVec":
+		res, err := args(1)
+		if err != nil {
+			return nil, err
+		}
+		return &scaleType{kind: kindVec, elem: res[0]}, nil
+	case "Option":
+		res, err := args(1)
+		if err != nil {
+			return nil, err
+		}
+		return &scaleType{kind: kindEnum, variants: map[byte]*scaleType{0: {kind: kindFixed}, 1: res[0]}}, nil
+	case "Result":
+		res, err := args(2)
+		if err != nil {
+			return nil, err
+		}
+		return &scaleType{kind: kindEnum, variants: map[byte]*scaleType{0: res[0], 1: res[1]}}, nil
+	case "Compact":
+		return &scaleType{kind: kindCompact}, nil
+	case "Box":
+		res, err := args(1)
+		if err != nil {
+			return nil, err
+		}
+		return res[0], nil
+	case "BTreeMap":
+		res, err := args(2)
+		if err != nil {
+			return nil, err
+		}
+		return &scaleType{kind: kindVec, elem: &scaleType{kind: kindTuple, fields: res[:2]}}, nil
+	case "PhantomData":
+		return &scaleType{kind: kindFixed}, nil
+	case "str":
+		return &scaleType{kind: kindVec, elem: &scaleType{kind: kindFixed, size: 1}}, nil
+	}
+
+	if size, ok := primitiveSizes[name]; ok {
+		return &scaleType{kind: kindFixed, size: size}, nil
+	}
+
+	def, ok := lookupTypeDef(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s%s", name, rawArgs)
+	}
+	sub := &typeParser{input: associatedTypePrefix.ReplaceAllString(def, "")}
+	t, err := sub.parseType(depth + 1)
+	if err != nil {
+		return nil, fmt.Errorf("definition of %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// skipGenericArgs moves past balanced angle brackets
+func (p *typeParser) skipGenericArgs() error {
+	level := 0
+	for ; p.pos < len(p.input); p.pos++ {
+		switch p.input[p.pos] {
+		case '<':
+			level++
+		case '>':
+			level--
+			if level == 0 {
+				p.pos++
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unbalanced generic arguments")
+}
+
+// parseList parses comma separated types up to the closing character
+func (p *typeParser) parseList(end byte, depth int) ([]*scaleType, error) {
+	var res []*scaleType
+	if p.peek() == end {
+		p.pos++
+		return res, nil
+	}
+	for {
+		t, err := p.parseType(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case end:
+			p.pos++
+			return res, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or %q at position %d", end, p.pos)
+		}
+	}
+}
+
+// parseEnum parses the variants of enum{index:type, ...}
+func (p *typeParser) parseEnum(depth int) (*scaleType, error) {
+	err := p.expect('{')
+	if err != nil {
+		return nil, err
+	}
+	t := &scaleType{kind: kindEnum, variants: make(map[byte]*scaleType)}
+	if p.peek() == '}' {
+		p.pos++
+		return t, nil
+	}
+	for {
+		index, err := strconv.ParseUint(p.ident(), 10, 8)
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(':')
+		if err != nil {
+			return nil, err
+		}
+		v, err := p.parseType(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		t.variants[byte(index)] = v
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return t, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or '}' at position %d", p.pos)
+		}
+	}
+}
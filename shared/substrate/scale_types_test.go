@@ -0,0 +1,132 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/scale"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+)
+
+func encode(t *testing.T, values ...interface{}) []byte {
+	var buf bytes.Buffer
+	encoder := scale.NewEncoder(&buf)
+	for _, v := range values {
+		err := encoder.Encode(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestScaleTypeSkip(t *testing.T) {
+	tests := []struct {
+		def     string
+		encoded []byte
+	}{
+		{"u8", encode(t, types.U8(1))},
+		{"T::Balance", encode(t, types.NewU128(*big.NewInt(1)))},
+		{"<T as frame_system::Config>::AccountId", encode(t, types.NewAccountID(make([]byte, 32)))},
+		{"BalanceOf<T, I>", encode(t, types.NewU128(*big.NewInt(1)))},
+		{"Vec<u8>", encode(t, types.NewBytes([]byte{1, 2, 3}))},
+		{"Vec<(T::AccountId, Balance)>", encode(t, types.NewUCompactFromUInt(2), [96]byte{})},
+		{"Option<Compact<u64>>", encode(t, types.U8(1), types.NewUCompactFromUInt(1<<40))},
+		{"Option<Vec<u8>>", encode(t, types.NewOptionBytesEmpty())},
+		{"[u8; 4]", []byte{1, 2, 3, 4}},
+		{"(u32, bool)", encode(t, types.U32(1), true)},
+		{"enum{0:(), 2:(Vec<u8>, u16)}", encode(t, types.U8(2), types.NewBytes([]byte{1}), types.U16(1))},
+		{"DispatchError", encode(t, types.U8(3), types.U8(7), types.U8(1))},
+		{"sp_runtime::DispatchResult", encode(t, types.U8(0))},
+		{"BTreeMap<u32, Vec<u8>>", encode(t, types.NewUCompactFromUInt(1), types.U32(1), types.NewBytes([]byte{1}))},
+		{"Text", encode(t, types.Text("hello"))},
+	}
+
+	for _, tt := range tests {
+		st, err := parseTypeDef(tt.def)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.def, err)
+		}
+		// A trailing byte ensures only the value is consumed
+		reader := bytes.NewReader(append(tt.encoded, 0xff))
+		err = st.skip(scale.NewDecoder(reader))
+		if err != nil {
+			t.Fatalf("%s: %s", tt.def, err)
+		}
+		if reader.Len() != 1 {
+			t.Errorf("%s: expected to skip %d bytes, skipped %d", tt.def, len(tt.encoded), len(tt.encoded)+1-reader.Len())
+		}
+	}
+}
+
+func TestScaleTypeInvalid(t *testing.T) {
+	for _, def := range []string{"", "NotRegistered", "Vec<u8", "(u8,", "[u8; x]", "enum{a:()}", "Vec"} {
+		_, err := parseTypeDef(def)
+		if err == nil {
+			t.Errorf("%q: expected error", def)
+		}
+	}
+
+	st, err := parseTypeDef("enum{0:(), 1:(u8)}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.skip(scale.NewDecoder(bytes.NewReader([]byte{5})))
+	if err == nil {
+		t.Fatal("expected error for unknown variant")
+	}
+	err = st.skip(scale.NewDecoder(bytes.NewReader([]byte{1})))
+	if err == nil {
+		t.Fatal("expected error for truncated value")
+	}
+}
+
+func TestRegisterTypeDef(t *testing.T) {
+	_, err := parseTypeDef("CustomPair")
+	if err == nil {
+		t.Fatal("expected error for unregistered type")
+	}
+	RegisterTypeDef("CustomPair", "(u8, Option<AccountId>)")
+	st, err := parseTypeDef("CustomPair")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := bytes.NewReader([]byte{1, 0})
+	err = st.skip(scale.NewDecoder(reader))
+	if err != nil || reader.Len() != 0 {
+		t.Fatalf("failed to skip CustomPair: %v", err)
+	}
+}
+
+func TestScaleDef(t *testing.T) {
+	r := typeRegistry{
+		0: {Def: typeDef{Kind: typeDefPrimitive, Primitive: 3}}, // u8
+		1: {Def: typeDef{Kind: typeDefArray, Type: 0, Len: 32}},
+		2: {Def: typeDef{Kind: typeDefComposite, Fields: []field{{Type: 1}}}},
+		3: {Def: typeDef{Kind: typeDefSequence, Type: 0}},
+		4: {Def: typeDef{Kind: typeDefVariant, Variants: []variant{{Index: 0}, {Index: 3, Fields: []field{{Type: 2}, {Type: 3}}}}}},
+		5: {Def: typeDef{Kind: typeDefComposite, Fields: []field{{Type: 4}, {Type: 6}}}},
+		6: {Def: typeDef{Kind: typeDefCompact, Type: 0}},
+		7: {Def: typeDef{Kind: typeDefSequence, Type: 7}},
+	}
+
+	def := r.scaleDef(5, 0)
+	expected := "(enum{0:(), 3:([u8; 32], Vec<u8>)}, Compact<u128>)"
+	if def != expected {
+		t.Fatalf("expected %s, got %s", expected, def)
+	}
+	_, err := parseTypeDef(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Recursive types cannot be described
+	_, err = parseTypeDef(r.scaleDef(7, 0))
+	if err == nil {
+		t.Fatal("expected error for recursive type")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/hash"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+	"github.com/decred/base58"
+)
+
+var ss58ChecksumPrefix = []byte("SS58PRE")
+
+// DecodeSS58 decodes an SS58 address of a 32 byte account ID and verifies its checksum. The network prefix of the
+// address is returned along with the account ID.
+func DecodeSS58(address string) (types.AccountID, uint16, error) {
+	data := base58.Decode(address)
+	if len(data) == 0 {
+		return types.AccountID{}, 0, fmt.Errorf("address %q is not base58 encoded", address)
+	}
+
+	// Prefixes below 64 take one byte, larger prefixes are encoded in two bytes
+	var prefix uint16
+	var prefixLen int
+	if data[0] < 64 {
+		prefix, prefixLen = uint16(data[0]), 1
+	} else if data[0] < 128 && len(data) > 1 {
+		prefix = uint16(data[0]&0x3f)<<2 | uint16(data[1])>>6 | uint16(data[1]&0x3f)<<8
+		prefixLen = 2
+	} else {
+		return types.AccountID{}, 0, fmt.Errorf("address %q has an invalid network prefix", address)
+	}
+
+	if len(data) != prefixLen+32+2 {
+		return types.AccountID{}, 0, fmt.Errorf("address %q does not encode a 32 byte account ID", address)
+	}
+
+	hasher, err := hash.NewBlake2b512(nil)
+	if err != nil {
+		return types.AccountID{}, 0, err
+	}
+	_, err = hasher.Write(append(append([]byte{}, ss58ChecksumPrefix...), data[:prefixLen+32]...))
+	if err != nil {
+		return types.AccountID{}, 0, err
+	}
+	checksum := hasher.Sum(nil)
+	if !bytes.Equal(checksum[:2], data[prefixLen+32:]) {
+		return types.AccountID{}, 0, fmt.Errorf("address %q has an invalid checksum", address)
+	}
+
+	return types.NewAccountID(data[prefixLen : prefixLen+32]), prefix, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 ChainSafe Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v3/hash"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v3/types"
+	"github.com/decred/base58"
+)
+
+// encodeSS58 encodes an account ID as described by the SS58 format
+func encodeSS58(t *testing.T, account []byte, prefix uint16) string {
+	var data []byte
+	if prefix < 64 {
+		data = []byte{byte(prefix)}
+	} else {
+		data = []byte{byte(prefix&0xfc)>>2 | 0x40, byte(prefix>>8) | byte(prefix&0x03)<<6}
+	}
+	data = append(data, account...)
+
+	hasher, err := hash.NewBlake2b512(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = hasher.Write(append([]byte("SS58PRE"), data...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base58.Encode(append(data, hasher.Sum(nil)[:2]...))
+}
+
+func TestDecodeSS58(t *testing.T) {
+	alice := signature.TestKeyringPairAlice
+
+	testCases := []struct {
+		address string
+		prefix  uint16
+	}{
+		{alice.Address, 42},
+		// Alice on Polkadot and Kusama
+		{"15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5", 0},
+		{"HNZata7iMYWmk5RvZRTiAsSDhV8366zq2YGb3tLH5Upf74F", 2},
+		{encodeSS58(t, alice.PublicKey, 10), 10},
+		// Prefixes from 64 are encoded in two bytes
+		{encodeSS58(t, alice.PublicKey, 64), 64},
+		{encodeSS58(t, alice.PublicKey, 1284), 1284},
+	}
+
+	for _, tc := range testCases {
+		account, prefix, err := DecodeSS58(tc.address)
+		if err != nil {
+			t.Errorf("Failed to decode %s: %s", tc.address, err)
+			continue
+		}
+		if account != types.NewAccountID(alice.PublicKey) {
+			t.Errorf("Unexpected account ID %x for %s", account, tc.address)
+		}
+		if prefix != tc.prefix {
+			t.Errorf("Unexpected prefix for %s. Got: %d Expected: %d", tc.address, prefix, tc.prefix)
+		}
+	}
+
+	for _, address := range []string{"", "0x1234", "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQZ", "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQ"} {
+		_, _, err := DecodeSS58(address)
+		if err == nil {
+			t.Errorf("Expected %q to be rejected", address)
+		}
+	}
+}
@@ -39,7 +39,7 @@ func WaitForRemarkEvent(t *testing.T, client *utils.Client, hash types.Hash) {
 
 				// Decode the event records
 				events := utils.Events{}
-				err = types.EventRecordsRaw(chng.StorageData).DecodeEventRecords(client.Meta, &events)
+				err = utils.DecodeEvents(client.Meta, types.EventRecordsRaw(chng.StorageData), &events)
 				if err != nil {
 					t.Fatal(err)
 				}